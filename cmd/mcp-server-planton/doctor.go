@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/grpcpool"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/logging"
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"github.com/plantoncloud/mcp-server-planton/internal/doctor"
+)
+
+// doctorCommand diagnoses the configuration, the connection to Planton APIs and the
+// permissions of the API key. It exits with exitError when a check fails.
+func doctorCommand(args []string) int {
+	fs := newFlagSet("doctor", "mcp-server-planton doctor [--org <slug>] [--json] [flags]")
+	opts := configFlags(fs)
+	org := fs.String("org", "", "organization to check environment and service access for (default "+config.DefaultOrgEnvVar+")")
+	timeout := fs.Duration("timeout", doctor.DefaultTimeout, "time allowed for each check")
+	jsonOutput := fs.Bool("json", false, "print the settings and checks as JSON")
+	if code, ok := parseFlags(fs, args, 0); !ok {
+		return code
+	}
+
+	// A missing key is reported by the api key check rather than failing the load
+	opts.APIKeyOptional = true
+	cfg, err := config.Load(*opts)
+	if err != nil {
+		return printDoctorReport(os.Stdout, nil, doctor.ConfigurationFailure(err), *jsonOutput)
+	}
+	logging.Setup(cfg)
+
+	// With unreadable TLS files the tls check fails and the API checks, which would
+	// use the pool, are skipped
+	if err := grpcpool.Setup(cfg); err != nil {
+		slog.Debug("gRPC connection setup error", "error", err)
+	}
+	defer func() {
+		if err := grpcpool.Close(); err != nil {
+			slog.Warn("Error closing gRPC connections", "error", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report := doctor.Run(ctx, cfg, clientfactory.New(cfg), doctor.Options{Org: *org, Timeout: *timeout})
+	return printDoctorReport(os.Stdout, doctor.Settings(cfg), report, *jsonOutput)
+}
+
+// printDoctorReport prints the resolved settings and the check results, and returns
+// the exit code.
+func printDoctorReport(w io.Writer, settings []doctor.Setting, report doctor.Report, jsonOutput bool) int {
+	code := exitOK
+	if report.Failed() > 0 {
+		code = exitError
+	}
+
+	if jsonOutput {
+		if printJSON(w, map[string]any{"settings": settings, "checks": report.Results}) != exitOK {
+			return exitError
+		}
+		return code
+	}
+
+	if len(settings) > 0 {
+		fmt.Fprintln(w, "Settings")
+		for _, setting := range settings {
+			value := setting.Value
+			if value == "" {
+				value = "-"
+			}
+			fmt.Fprintf(w, "  %-34s %s\n", setting.Name, value)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w, "Checks")
+	for _, result := range report.Results {
+		fmt.Fprintf(w, "  %-4s  %-15s %s\n", strings.ToUpper(string(result.Status)), result.Name,
+			indent(result.Detail, strings.Repeat(" ", 24))[24:])
+		if result.Fix != "" {
+			fmt.Fprintf(w, "%s\n", indent("Fix: "+result.Fix, strings.Repeat(" ", 24)))
+		}
+	}
+
+	fmt.Fprintln(w)
+	if failed := report.Failed(); failed > 0 {
+		fmt.Fprintf(w, "%d check(s) failed.\n", failed)
+	} else {
+		fmt.Fprintln(w, "No problems found.")
+	}
+	return code
+}
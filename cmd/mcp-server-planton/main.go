@@ -1,90 +1,160 @@
+// Command mcp-server-planton runs the Planton Cloud MCP server, and lists and calls
+// its tools from the command line.
 package main
 
 import (
-	"log"
+	"errors"
+	"flag"
+	"fmt"
 	"os"
-	"os/signal"
-	"sync"
-	"syscall"
+	"strings"
 
+	"github.com/plantoncloud/mcp-server-planton/internal/common/buildinfo"
 	"github.com/plantoncloud/mcp-server-planton/internal/config"
-	"github.com/plantoncloud/mcp-server-planton/internal/mcp"
 )
 
-func main() {
-	// Set up logging
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
-
-	// Load configuration from environment
-	cfg, err := config.LoadFromEnv()
-	if err != nil {
-		log.Fatalf("Configuration error: %v", err)
-	}
+// Build info, set by release builds with
+// -ldflags "-X main.version=... -X main.commit=... -X main.date=..."
+var (
+	version = buildinfo.DevVersion
+	commit  = ""
+	date    = ""
+)
 
-	// Create MCP server
-	server := mcp.NewServer(cfg)
+// Process exit codes
+const (
+	// exitOK means the server stopped cleanly (signal, or stdin closed in STDIO mode)
+	exitOK = 0
 
-	// Handle OS signals for graceful shutdown
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	// exitError means a transport failed (e.g. the HTTP port could not be bound), the
+	// configuration is invalid, a tool call failed, or a doctor check failed
+	exitError = 1
 
-	// Start server(s) based on transport configuration
-	var wg sync.WaitGroup
-	errChan := make(chan error, 2)
+	// exitShutdownTimeout means in-flight work was abandoned because graceful
+	// shutdown did not complete within PLANTON_MCP_SHUTDOWN_TIMEOUT
+	exitShutdownTimeout = 2
 
-	switch cfg.Transport {
-	case config.TransportStdio:
-		// STDIO only mode
-		log.Println("Starting in STDIO-only mode")
-		if err := server.Serve(); err != nil {
-			log.Fatalf("STDIO server error: %v", err)
-		}
+	// exitUsage means the command line is invalid (EX_USAGE)
+	exitUsage = 64
+)
 
-	case config.TransportHTTP:
-		// HTTP only mode
-		log.Println("Starting in HTTP-only mode")
-		httpOpts := mcp.DefaultHTTPOptions(cfg)
-		if err := server.ServeHTTP(httpOpts); err != nil {
-			log.Fatalf("HTTP server error: %v", err)
-		}
+// usage is printed by the help command and for unknown commands
+const usage = `Usage: mcp-server-planton [command] [flags]
+
+Commands:
+  serve        Run the MCP server (default when no command is given)
+  tools list   List the tools with their input schemas
+  tools call   Call a tool against the configured Planton APIs
+  doctor       Diagnose the configuration, connectivity and permissions
+  version      Print build information
+
+Run "mcp-server-planton <command> -h" for the flags of a command.
+`
+
+// settingFlags are the command-line flags that override a setting. Every other
+// setting is read from its environment variable or the config file.
+var settingFlags = []struct {
+	name   string
+	envVar string
+	usage  string
+}{
+	{"environment", config.EnvironmentEnvVar, "Planton Cloud environment (live, test, local)"},
+	{"endpoint", config.EndpointOverrideEnvVar, "Planton APIs gRPC endpoint (host:port)"},
+	{"transport", config.TransportEnvVar, "MCP transport (stdio, http, both)"},
+	{"http-port", config.HTTPPortEnvVar, "HTTP server port"},
+	{"log-level", config.LogLevelEnvVar, "minimum log level (debug, info, warn, error)"},
+	{"log-format", config.LogFormatEnvVar, "log format (text, json)"},
+}
 
-	case config.TransportBoth:
-		// Both transports - run HTTP in goroutine, STDIO in main
-		log.Println("Starting in dual transport mode (STDIO + HTTP)")
-
-		// Start HTTP server in background
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			httpOpts := mcp.DefaultHTTPOptions(cfg)
-			if err := server.ServeHTTP(httpOpts); err != nil {
-				errChan <- err
-			}
-		}()
-
-		// Start STDIO server in background (for dual mode)
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			if err := server.Serve(); err != nil {
-				errChan <- err
-			}
-		}()
-
-		// Wait for shutdown signal or error
-		select {
-		case <-sigChan:
-			log.Println("Shutdown signal received, stopping servers...")
-		case err := <-errChan:
-			log.Printf("Server error: %v", err)
-		}
+func main() {
+	buildinfo.Set(buildinfo.Info{Version: version, Commit: commit, Date: date})
+	os.Exit(runCommand(os.Args[1:]))
+}
 
-		// Wait for both servers to stop
-		wg.Wait()
+// runCommand runs the command named by the first argument and returns the process
+// exit code. Without a command, or when the first argument is a flag, it runs
+// "serve", so MCP clients keep starting the server without arguments.
+func runCommand(args []string) int {
+	command := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
 
+	switch command {
+	case "serve":
+		return serveCommand(args)
+	case "tools":
+		return toolsCommand(args)
+	case "doctor":
+		return doctorCommand(args)
+	case "version":
+		return versionCommand(args)
+	case "help":
+		fmt.Fprint(os.Stdout, usage)
+		return exitOK
 	default:
-		log.Fatalf("Invalid transport mode: %s", cfg.Transport)
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		return exitUsage
 	}
+}
 
-	log.Println("MCP server stopped")
+// newFlagSet returns the flag set of a command. Parse errors are returned rather
+// than exiting, see parseFlags.
+//
+// Args:
+//   - name: Command name, e.g. "tools call"
+//   - synopsis: Usage line printed above the flags
+func newFlagSet(name, synopsis string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s\n\nFlags:\n", synopsis)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// configFlags registers the flags that select the config file and profile and
+// override settings, and returns the options of config.Load they fill. Only flags
+// that are given override a setting.
+func configFlags(fs *flag.FlagSet) *config.LoadOptions {
+	opts := &config.LoadOptions{Overrides: make(map[string]string)}
+	fs.StringVar(&opts.ConfigFile, "config", "",
+		"config file (default $XDG_CONFIG_HOME/planton/mcp.yaml, or "+config.ConfigFileEnvVar+")")
+	fs.StringVar(&opts.Profile, "profile", "", "config file profile (or "+config.ProfileEnvVar+")")
+	for _, setting := range settingFlags {
+		fs.Func(setting.name, setting.usage+" (or "+setting.envVar+")", func(value string) error {
+			opts.Overrides[setting.envVar] = value
+			return nil
+		})
+	}
+	return opts
+}
+
+// parseFlags parses the arguments of a command and rejects positional arguments
+// beyond maxArgs (any number when negative). It returns false with the exit code
+// when the command must not run: after -h (exitOK) or on an invalid command line
+// (exitUsage).
+func parseFlags(fs *flag.FlagSet, args []string, maxArgs int) (int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK, false
+		}
+		return exitUsage, false
+	}
+	if maxArgs >= 0 && fs.NArg() > maxArgs {
+		fmt.Fprintf(fs.Output(), "unexpected arguments: %s\n", strings.Join(fs.Args()[maxArgs:], " "))
+		fs.Usage()
+		return exitUsage, false
+	}
+	return exitOK, true
+}
+
+// loadConfig loads the configuration and prints every problem when it is invalid
+func loadConfig(opts *config.LoadOptions) (*config.Config, bool) {
+	cfg, err := config.Load(*opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+		return nil, false
+	}
+	return cfg, true
 }
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/plantoncloud/mcp-server-planton/internal/common/grpcpool"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/logging"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/tracing"
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"github.com/plantoncloud/mcp-server-planton/internal/mcp"
+)
+
+// serveCommand runs the MCP server until a shutdown signal, or until stdin is closed
+// in STDIO mode, and returns the process exit code.
+func serveCommand(args []string) int {
+	fs := newFlagSet("serve", "mcp-server-planton [serve] [flags]")
+	opts := configFlags(fs)
+	if code, ok := parseFlags(fs, args, 0); !ok {
+		return code
+	}
+
+	// Load configuration from flags, environment and config file, in that order of precedence
+	cfg, ok := loadConfig(opts)
+	if !ok {
+		return exitError
+	}
+
+	// Set up structured logging (always to stderr; stdout carries the MCP protocol in STDIO mode)
+	logging.Setup(cfg)
+
+	// Set up OpenTelemetry tracing before any gRPC client is created
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg)
+	if err != nil {
+		slog.Error("Tracing setup error", "error", err)
+		return exitError
+	}
+
+	// Share one gRPC connection per Planton APIs endpoint across all tool calls
+	if err := grpcpool.Setup(cfg); err != nil {
+		slog.Error("gRPC connection setup error", "error", err)
+		return exitError
+	}
+
+	// Create MCP server
+	server := mcp.NewServer(cfg)
+
+	// Cancel the serve context on SIGINT/SIGTERM so every transport shuts down gracefully
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		slog.Info("Shutdown signal received, stopping servers...")
+	}()
+
+	code := run(ctx, cfg, server)
+	stop()
+
+	if err := grpcpool.Close(); err != nil {
+		slog.Warn("Error closing gRPC connections", "error", err)
+	}
+
+	// Flush pending spans
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Warn("Error flushing traces", "error", err)
+	}
+	cancel()
+
+	slog.Info("MCP server stopped", "exit_code", code)
+	return code
+}
+
+// run starts the transports selected by the configuration and blocks until they
+// have stopped. It returns the process exit code.
+func run(ctx context.Context, cfg *config.Config, server *mcp.Server) int {
+	var err error
+
+	switch cfg.Transport {
+	case config.TransportStdio:
+		// STDIO only mode
+		slog.Info("Starting in STDIO-only mode")
+		err = server.Serve(ctx)
+
+	case config.TransportHTTP:
+		// HTTP only mode
+		slog.Info("Starting in HTTP-only mode")
+		err = server.ServeHTTP(ctx, mcp.DefaultHTTPOptions(cfg))
+
+	case config.TransportBoth:
+		// Both transports - if one of them fails, stop the other one as well.
+		// STDIO returning cleanly (stdin closed) leaves HTTP running.
+		slog.Info("Starting in dual transport mode (STDIO + HTTP)")
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		errChan := make(chan error, 2)
+		go func() {
+			errChan <- server.ServeHTTP(ctx, mcp.DefaultHTTPOptions(cfg))
+		}()
+		go func() {
+			errChan <- server.Serve(ctx)
+		}()
+
+		// Wait for both servers to stop
+		for i := 0; i < 2; i++ {
+			if serveErr := <-errChan; serveErr != nil {
+				err = errors.Join(err, serveErr)
+				cancel()
+			}
+		}
+
+	default:
+		slog.Error("Invalid transport mode", "transport", cfg.Transport)
+		return exitError
+	}
+
+	return serveExitCode(err)
+}
+
+// serveExitCode returns the exit code for the error the transports stopped with:
+// exitShutdownTimeout if in-flight work was abandoned, even when joined with other errors.
+func serveExitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, mcp.ErrShutdownTimeout):
+		slog.Warn("Server did not shut down cleanly", "error", err)
+		return exitShutdownTimeout
+	default:
+		slog.Error("Server error", "error", err)
+		return exitError
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/plantoncloud/mcp-server-planton/internal/mcp"
+)
+
+func TestServeExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"clean shutdown", nil, exitOK},
+		{"shutdown timeout", mcp.ErrShutdownTimeout, exitShutdownTimeout},
+		{"timeout joined with another transport error", errors.Join(errors.New("stdio closed"), fmt.Errorf("http: %w", mcp.ErrShutdownTimeout)), exitShutdownTimeout},
+		{"transport error", errors.New("listen tcp :8080: address already in use"), exitError},
+	}
+	for _, tt := range tests {
+		if got := serveExitCode(tt.err); got != tt.want {
+			t.Errorf("%s: serveExitCode() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/grpcpool"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/logging"
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"github.com/plantoncloud/mcp-server-planton/internal/mcp"
+)
+
+// toolsUsage is printed for "tools" without a valid subcommand
+const toolsUsage = `Usage: mcp-server-planton tools <list|call> [flags]
+
+  list   List the tools with their input schemas
+  call   Call a tool against the configured Planton APIs
+`
+
+// toolsCommand runs a "tools" subcommand and returns the process exit code.
+func toolsCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, toolsUsage)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "list":
+		return toolsListCommand(args[1:])
+	case "call":
+		return toolsCallCommand(args[1:])
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(os.Stdout, toolsUsage)
+		return exitOK
+	default:
+		fmt.Fprintf(os.Stderr, "unknown tools command %q\n\n%s", args[0], toolsUsage)
+		return exitUsage
+	}
+}
+
+// toolsListCommand prints every registered tool with its description and input
+// schema.
+func toolsListCommand(args []string) int {
+	fs := newFlagSet("tools list", "mcp-server-planton tools list [--json] [flags]")
+	opts := configFlags(fs)
+	jsonOutput := fs.Bool("json", false, "print the tools as a JSON array, as returned by tools/list")
+	if code, ok := parseFlags(fs, args, 0); !ok {
+		return code
+	}
+
+	// Listing tools does not call Planton APIs
+	opts.APIKeyOptional = true
+	cfg, ok := loadConfig(opts)
+	if !ok {
+		return exitError
+	}
+	logging.Setup(cfg)
+
+	tools := mcp.NewServer(cfg).Tools()
+	if *jsonOutput {
+		return printJSON(os.Stdout, tools)
+	}
+	for _, tool := range tools {
+		printTool(os.Stdout, tool)
+	}
+	return exitOK
+}
+
+// printTool prints the name, description and input schema of a tool
+func printTool(w io.Writer, tool mcpgo.Tool) {
+	fmt.Fprintln(w, tool.Name)
+	if tool.Description != "" {
+		fmt.Fprintln(w, indent(strings.TrimSpace(tool.Description), "    "))
+	}
+	schema, err := json.MarshalIndent(tool.InputSchema, "", "  ")
+	if err != nil {
+		schema = []byte(err.Error())
+	}
+	fmt.Fprintf(w, "    Input schema:\n%s\n\n", indent(string(schema), "      "))
+}
+
+// toolsCallCommand calls a tool with the configured API key and prints its result.
+// It exits with exitError when the tool returns an error result.
+func toolsCallCommand(args []string) int {
+	fs := newFlagSet("tools call", "mcp-server-planton tools call <name> [--arg key=value]... [--json '{...}'] [flags]")
+	opts := configFlags(fs)
+	var pairs []string
+	fs.Func("arg", "tool argument as key=value (repeatable); values of non-string parameters are parsed as JSON",
+		func(value string) error {
+			pairs = append(pairs, value)
+			return nil
+		})
+	rawArguments := fs.String("json", "", "tool arguments as a JSON object; --arg values are applied on top")
+
+	// The tool name may come before, between or after the flags
+	name := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if code, ok := parseFlags(fs, args, -1); !ok {
+		return code
+	}
+	if rest := fs.Args(); name == "" && len(rest) > 0 {
+		name = rest[0]
+		if code, ok := parseFlags(fs, rest[1:], 0); !ok {
+			return code
+		}
+	} else if len(rest) > 0 {
+		fmt.Fprintf(os.Stderr, "unexpected arguments: %s\n", strings.Join(rest, " "))
+		fs.Usage()
+		return exitUsage
+	}
+	if name == "" {
+		fmt.Fprintln(os.Stderr, "missing tool name (run \"mcp-server-planton tools list\" for the available tools)")
+		fs.Usage()
+		return exitUsage
+	}
+
+	cfg, ok := loadConfig(opts)
+	if !ok {
+		return exitError
+	}
+	if cfg.PlantonAPIKey == "" {
+		fmt.Fprintf(os.Stderr, "tools call authenticates with %s: set it in the environment, or with api_key_env or api_key_file in a config file profile\n",
+			config.APIKeyEnvVar)
+		return exitError
+	}
+	logging.Setup(cfg)
+
+	if err := grpcpool.Setup(cfg); err != nil {
+		slog.Error("gRPC connection setup error", "error", err)
+		return exitError
+	}
+	defer func() {
+		if err := grpcpool.Close(); err != nil {
+			slog.Warn("Error closing gRPC connections", "error", err)
+		}
+	}()
+
+	server := mcp.NewServer(cfg)
+	tools := server.Tools()
+	index := slices.IndexFunc(tools, func(tool mcpgo.Tool) bool { return tool.Name == name })
+	if index < 0 {
+		fmt.Fprintf(os.Stderr, "unknown tool %q (run \"mcp-server-planton tools list\" for the available tools)\n", name)
+		return exitUsage
+	}
+
+	arguments, err := toolArguments(tools[index], *rawArguments, pairs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	// Ctrl-C cancels the call like a client's notifications/cancelled
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	result, err := server.CallTool(ctx, name, arguments)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+	printResult(os.Stdout, result)
+	if result.IsError {
+		return exitError
+	}
+	return exitOK
+}
+
+// toolArguments builds the arguments of a tool call from the --json object and the
+// --arg key=value pairs, which override keys of the object. Values of parameters
+// whose schema type is not "string" are parsed as JSON, so numbers, booleans, arrays
+// and objects can be passed with --arg.
+func toolArguments(tool mcpgo.Tool, rawArguments string, pairs []string) (map[string]any, error) {
+	arguments := make(map[string]any)
+	if rawArguments != "" {
+		if err := json.Unmarshal([]byte(rawArguments), &arguments); err != nil {
+			return nil, fmt.Errorf("invalid --json: expected a JSON object of tool arguments: %w", err)
+		}
+	}
+
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --arg %q: expected key=value", pair)
+		}
+
+		property, ok := tool.InputSchema.Properties[key].(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid --arg %q: %s has no parameter %q (parameters: %s)",
+				pair, tool.Name, key, strings.Join(parameterNames(tool), ", "))
+		}
+		kind, _ := property["type"].(string)
+		if kind == "" || kind == "string" {
+			arguments[key] = value
+			continue
+		}
+
+		var parsed any
+		if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+			return nil, fmt.Errorf("invalid --arg %q: %s has type %s, expected a JSON value", pair, key, kind)
+		}
+		arguments[key] = parsed
+	}
+	return arguments, nil
+}
+
+// parameterNames returns the sorted parameter names of a tool
+func parameterNames(tool mcpgo.Tool) []string {
+	return slices.Sorted(maps.Keys(tool.InputSchema.Properties))
+}
+
+// printResult prints the content of a tool result. Text that holds JSON, which is
+// what most tools return, is indented.
+func printResult(w io.Writer, result *mcpgo.CallToolResult) {
+	for _, content := range result.Content {
+		text, ok := content.(mcpgo.TextContent)
+		if !ok {
+			printJSON(w, content)
+			continue
+		}
+
+		var indented bytes.Buffer
+		if json.Indent(&indented, []byte(text.Text), "", "  ") == nil {
+			fmt.Fprintln(w, indented.String())
+		} else {
+			fmt.Fprintln(w, text.Text)
+		}
+	}
+}
+
+// printJSON prints a value as indented JSON and returns the exit code
+func printJSON(w io.Writer, value any) int {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+	return exitOK
+}
+
+// indent prefixes every line of text
+func indent(text, prefix string) string {
+	return prefix + strings.ReplaceAll(text, "\n", "\n"+prefix)
+}
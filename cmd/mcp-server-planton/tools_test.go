@@ -0,0 +1,71 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestToolArguments(t *testing.T) {
+	tool := mcpgo.Tool{
+		Name: "search",
+		InputSchema: mcpgo.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]any{
+				"org_id":    map[string]any{"type": "string"},
+				"env_names": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+				"limit":     map[string]any{"type": "integer"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		raw     string
+		pairs   []string
+		want    map[string]any
+		wantErr string
+	}{
+		{
+			name:  "strings are taken verbatim",
+			pairs: []string{"org_id=42", "env_names=[\"prod\"]"},
+			want:  map[string]any{"org_id": "42", "env_names": []any{"prod"}},
+		},
+		{
+			name:  "non-string values are parsed as JSON",
+			pairs: []string{"limit=10"},
+			want:  map[string]any{"limit": float64(10)},
+		},
+		{
+			name:  "--arg overrides --json",
+			raw:   `{"org_id": "acme", "limit": 5}`,
+			pairs: []string{"org_id=planton"},
+			want:  map[string]any{"org_id": "planton", "limit": float64(5)},
+		},
+		{name: "value with equals sign", pairs: []string{"org_id=a=b"}, want: map[string]any{"org_id": "a=b"}},
+		{name: "unknown parameter", pairs: []string{"org=acme"}, wantErr: "parameters: env_names, limit, org_id"},
+		{name: "missing value", pairs: []string{"org_id"}, wantErr: "expected key=value"},
+		{name: "invalid JSON value", pairs: []string{"limit=ten"}, wantErr: "limit has type integer"},
+		{name: "invalid --json", raw: `["acme"]`, wantErr: "invalid --json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toolArguments(tool, tt.raw, tt.pairs)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("toolArguments() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("toolArguments() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/plantoncloud/mcp-server-planton/internal/common/buildinfo"
+)
+
+// versionCommand prints the build info of the binary.
+func versionCommand(args []string) int {
+	fs := newFlagSet("version", "mcp-server-planton version [--json]")
+	jsonOutput := fs.Bool("json", false, "print the build info as JSON")
+	if code, ok := parseFlags(fs, args, 0); !ok {
+		return code
+	}
+
+	info := buildinfo.Get()
+	if *jsonOutput {
+		return printJSON(os.Stdout, map[string]string{
+			"version":  info.Version,
+			"commit":   info.Commit,
+			"date":     info.Date,
+			"go":       runtime.Version(),
+			"platform": runtime.GOOS + "/" + runtime.GOARCH,
+		})
+	}
+	fmt.Println("mcp-server-planton " + info.String())
+	return exitOK
+}
@@ -3,8 +3,6 @@ package auth
 import (
 	"context"
 	"errors"
-	"log"
-	"sync"
 )
 
 // tokenAuth implements credentials.PerRPCCredentials interface to attach
@@ -22,7 +20,7 @@ type tokenAuth struct {
 	token string
 }
 
-// NewTokenAuth creates a new tokenAuth instance for use with grpc.WithPerRPCCredentials.
+// NewTokenAuth creates a new tokenAuth instance for use with grpc.PerRPCCredentials.
 //
 // The token should be either a JWT token or an API key from Planton Cloud console.
 // It will be attached as "Bearer <token>" in the Authorization header.
@@ -54,10 +52,14 @@ const apiKeyContextKey contextKey = "planton-api-key"
 // This is used in HTTP transport mode to pass per-user API keys from HTTP headers
 // to gRPC clients.
 //
+// The HTTP server stores the key on each request's context. mcp-go hands that same
+// context to tool handlers, so every tool call runs with the key of the request that
+// triggered it. There is no process-wide key: concurrent users and sessions are isolated.
+//
 // Example:
 //
 //	ctx := auth.WithAPIKey(r.Context(), "user-api-key")
-//	client, err := NewClientFromContext(ctx, endpoint)
+//	apiKey, err := auth.ResolveAPIKey(ctx, serverKey) // under PolicyCallerKey
 func WithAPIKey(ctx context.Context, apiKey string) context.Context {
 	return context.WithValue(ctx, apiKeyContextKey, apiKey)
 }
@@ -81,56 +83,3 @@ func GetAPIKey(ctx context.Context) (string, error) {
 	}
 	return apiKey, nil
 }
-
-// apiKeyStore provides a simple storage for API keys from HTTP requests.
-// This is a workaround for mcp-go's AddTool not supporting context parameters.
-//
-// Since SSE connections are typically single-threaded (one request at a time per connection),
-// we store the API key when a request comes in and retrieve it in tool handlers.
-//
-// Limitations:
-//   - Race conditions possible with concurrent requests (rare in SSE)
-//   - Not suitable for high-concurrency scenarios
-//   - Better solution: upstream fix to mcp-go to support context in AddTool
-type apiKeyStore struct {
-	mu         sync.RWMutex
-	currentKey string
-}
-
-var globalAPIKeyStore = &apiKeyStore{}
-
-// SetCurrentAPIKey stores the API key for the current request context.
-// Called by HTTP proxy before forwarding requests to internal SSE server.
-func SetCurrentAPIKey(apiKey string) {
-	globalAPIKeyStore.mu.Lock()
-	defer globalAPIKeyStore.mu.Unlock()
-	globalAPIKeyStore.currentKey = apiKey
-	log.Printf("API key stored for current request")
-}
-
-// getCurrentAPIKey retrieves the API key for the current request context.
-// Called by tool handlers to get user's API key.
-func getCurrentAPIKey() string {
-	globalAPIKeyStore.mu.RLock()
-	defer globalAPIKeyStore.mu.RUnlock()
-	return globalAPIKeyStore.currentKey
-}
-
-// GetContextWithAPIKey creates a context with the API key from the current request.
-// This is used in tool handlers to create authenticated gRPC clients.
-//
-// This is a workaround for mcp-go's AddTool not supporting context parameters.
-// The API key is stored when HTTP requests arrive and retrieved here for use
-// in gRPC client creation.
-//
-// Usage in tool handlers:
-//
-//	ctx := auth.GetContextWithAPIKey(context.Background())
-//	client, err := clients.NewClientFromContext(ctx, endpoint)
-func GetContextWithAPIKey(baseContext context.Context) context.Context {
-	apiKey := getCurrentAPIKey()
-	if apiKey != "" {
-		return WithAPIKey(baseContext, apiKey)
-	}
-	return baseContext
-}
@@ -0,0 +1,187 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/MicahParks/keyfunc/v3"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// jwtLeeway tolerates clock skew between this server and the token issuer
+	jwtLeeway = 30 * time.Second
+
+	// jwksHTTPTimeout bounds each request to the JWKS endpoint
+	jwksHTTPTimeout = 10 * time.Second
+
+	// jwksRefreshInterval is how often the JWKS is refreshed in the background
+	jwksRefreshInterval = 15 * time.Minute
+
+	// jwksUnknownKIDWaitMax bounds how long a request waits for an on-demand JWKS
+	// refresh when its token is signed with a key ID that is not cached yet
+	jwksUnknownKIDWaitMax = 2 * time.Second
+)
+
+// jwtSigningMethods are the accepted JWT signing algorithms. Only asymmetric
+// algorithms are accepted: a JWKS publishes public keys, and accepting HMAC or "none"
+// would let anyone forge tokens.
+var jwtSigningMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+// TokenError describes why a JWT bearer token was rejected.
+type TokenError struct {
+	// Reason is a short description that is safe to return to the client
+	Reason string
+	// Err is the underlying validation error
+	Err error
+}
+
+func (e *TokenError) Error() string {
+	return e.Reason + ": " + e.Err.Error()
+}
+
+func (e *TokenError) Unwrap() error {
+	return e.Err
+}
+
+// JWTConfig configures local validation of JWT bearer tokens.
+type JWTConfig struct {
+	// JWKSURL is the JSON Web Key Set holding the token signing keys
+	JWKSURL string
+	// Issuer is the required "iss" claim. Empty skips the check.
+	Issuer string
+	// Audience lists the accepted "aud" claim values. Empty skips the check.
+	Audience []string
+}
+
+// JWTValidator validates JWT bearer tokens locally against a JWKS, so invalid or
+// expired tokens are rejected before a session is opened rather than on the first
+// Planton API call.
+//
+// Opaque API keys are not JWTs and are not validated here: use IsJWT to decide
+// whether a token should be validated or passed through to Planton APIs as-is.
+type JWTValidator struct {
+	keys     keyfunc.Keyfunc
+	parser   *jwt.Parser
+	audience []string
+}
+
+// NewJWTValidator creates a JWT validator.
+//
+// The JWKS is fetched once on creation and refreshed in the background until ctx is
+// cancelled. Keys are also refreshed on demand when a token carries an unknown key
+// ID, so issuer key rotations are picked up without a restart. An unreachable JWKS
+// does not fail creation; tokens are rejected until the keys can be fetched.
+//
+// Args:
+//   - ctx: Ends the background refresh when cancelled
+//   - cfg: The JWKS URL and the required issuer and audience
+//
+// Returns the validator or an error if the JWKS URL is invalid.
+func NewJWTValidator(ctx context.Context, cfg JWTConfig) (*JWTValidator, error) {
+	keys, err := keyfunc.NewDefaultOverrideCtx(ctx, []string{cfg.JWKSURL}, keyfunc.Override{
+		HTTPTimeout:      jwksHTTPTimeout,
+		RefreshInterval:  jwksRefreshInterval,
+		RateLimitWaitMax: jwksUnknownKIDWaitMax,
+		RefreshErrorHandlerFunc: func(url string) func(ctx context.Context, err error) {
+			return func(ctx context.Context, err error) {
+				slog.WarnContext(ctx, "Failed to refresh JWKS", "url", url, "error", err)
+			}
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWKS client: %w", err)
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(jwtSigningMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(jwtLeeway),
+	}
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+
+	return &JWTValidator{
+		keys:     keys,
+		parser:   jwt.NewParser(options...),
+		audience: cfg.Audience,
+	}, nil
+}
+
+// Validate checks a JWT's signature against the JWKS and its exp, nbf, iss and aud
+// claims. A token is accepted if its "aud" claim contains any configured audience.
+//
+// Returns nil if the token is valid, or a *TokenError describing why it was rejected.
+func (v *JWTValidator) Validate(ctx context.Context, token string) error {
+	claims := &jwt.RegisteredClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.keys.KeyfuncCtx(ctx)); err != nil {
+		return &TokenError{Reason: tokenErrorReason(err), Err: err}
+	}
+
+	if len(v.audience) > 0 && !slices.ContainsFunc(claims.Audience, func(aud string) bool {
+		return slices.Contains(v.audience, aud)
+	}) {
+		return &TokenError{
+			Reason: "invalid audience",
+			Err:    fmt.Errorf("%w: got %v", jwt.ErrTokenInvalidAudience, []string(claims.Audience)),
+		}
+	}
+
+	return nil
+}
+
+// tokenErrorReason maps a JWT validation error to a short description for clients.
+func tokenErrorReason(err error) string {
+	switch {
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return "malformed token"
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid):
+		return "invalid signature"
+	case errors.Is(err, jwt.ErrTokenUnverifiable):
+		return "unknown signing key or algorithm"
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return "token expired"
+	case errors.Is(err, jwt.ErrTokenRequiredClaimMissing):
+		return "missing required claim"
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+		return "token not valid yet"
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return "invalid issuer"
+	default:
+		return "invalid token"
+	}
+}
+
+// IsJWT reports whether a bearer token is a JWT rather than an opaque API key.
+// A JWT has three dot-separated base64url segments, the first of which is a JSON
+// header naming the signing algorithm.
+func IsJWT(token string) bool {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return false
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return false
+	}
+	return header.Alg != ""
+}
@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testKID      = "test-key"
+	testIssuer   = "https://auth.planton.ai/"
+	testAudience = "https://mcp.planton.ai"
+)
+
+// newTestJWKS serves a JWKS with the public half of a new RSA key and returns the
+// key and the JWKS URL.
+func newTestJWKS(t *testing.T) (*rsa.PrivateKey, string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": testKID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(jwks)
+	}))
+	t.Cleanup(server.Close)
+	return key, server.URL
+}
+
+// signToken signs claims with method and key, naming testKID in the header.
+func signToken(t *testing.T, method jwt.SigningMethod, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = testKID
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestJWTValidator(t *testing.T) {
+	key, jwksURL := newTestJWKS(t)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	validator, err := NewJWTValidator(ctx, JWTConfig{
+		JWKSURL:  jwksURL,
+		Issuer:   testIssuer,
+		Audience: []string{"https://other.planton.ai", testAudience},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := func(overrides jwt.MapClaims) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"sub": "user-123",
+			"iss": testIssuer,
+			"aud": []string{"https://api.planton.ai", testAudience},
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}
+		for name, value := range overrides {
+			if value == nil {
+				delete(c, name)
+				continue
+			}
+			c[name] = value
+		}
+		return c
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		wantReason string
+	}{
+		{name: "valid", token: signToken(t, jwt.SigningMethodRS256, key, claims(nil))},
+		{name: "single audience", token: signToken(t, jwt.SigningMethodRS256, key, claims(jwt.MapClaims{"aud": testAudience}))},
+		{name: "expired within leeway", token: signToken(t, jwt.SigningMethodRS256, key, claims(jwt.MapClaims{"exp": time.Now().Add(-10 * time.Second).Unix()}))},
+		{
+			name:       "hmac signed",
+			token:      signToken(t, jwt.SigningMethodHS256, []byte("shared-secret"), claims(nil)),
+			wantReason: "invalid signature",
+		},
+		{
+			name:       "unsigned",
+			token:      signToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, claims(nil)),
+			wantReason: "invalid signature",
+		},
+		{
+			name:       "signed by another key",
+			token:      signToken(t, jwt.SigningMethodRS256, otherKey, claims(nil)),
+			wantReason: "invalid signature",
+		},
+		{
+			name:       "missing exp",
+			token:      signToken(t, jwt.SigningMethodRS256, key, claims(jwt.MapClaims{"exp": nil})),
+			wantReason: "missing required claim",
+		},
+		{
+			name:       "expired",
+			token:      signToken(t, jwt.SigningMethodRS256, key, claims(jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()})),
+			wantReason: "token expired",
+		},
+		{
+			name:       "not valid yet",
+			token:      signToken(t, jwt.SigningMethodRS256, key, claims(jwt.MapClaims{"nbf": time.Now().Add(time.Hour).Unix()})),
+			wantReason: "token not valid yet",
+		},
+		{
+			name:       "wrong issuer",
+			token:      signToken(t, jwt.SigningMethodRS256, key, claims(jwt.MapClaims{"iss": "https://evil.example/"})),
+			wantReason: "invalid issuer",
+		},
+		{
+			name:       "wrong audience",
+			token:      signToken(t, jwt.SigningMethodRS256, key, claims(jwt.MapClaims{"aud": []string{"https://api.planton.ai"}})),
+			wantReason: "invalid audience",
+		},
+		{name: "malformed", token: "eyJhbGciOiJSUzI1NiJ9.not-json.sig", wantReason: "malformed token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate(context.Background(), tt.token)
+			if tt.wantReason == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			tokenErr, ok := err.(*TokenError)
+			if !ok {
+				t.Fatalf("Validate() = %v, want a *TokenError", err)
+			}
+			if tokenErr.Reason != tt.wantReason {
+				t.Errorf("reason = %q (%v), want %q", tokenErr.Reason, tokenErr.Err, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestIsJWT(t *testing.T) {
+	tests := []struct {
+		token string
+		want  bool
+	}{
+		{"eyJhbGciOiJSUzI1NiJ9.eyJzdWIiOiJ1c2VyIn0.c2ln", true},
+		{"pck_9f8e7d6c5b4a39281706f5e4d3c2b1a0", false},
+		{"a.b.c", false},
+		// A JSON header without an algorithm
+		{"e30.eyJzdWIiOiJ1c2VyIn0.c2ln", false},
+	}
+	for _, tt := range tests {
+		if got := IsJWT(tt.token); got != tt.want {
+			t.Errorf("IsJWT(%q) = %t, want %t", tt.token, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// CredentialPolicy decides which API key the gRPC calls of a tool call are made with.
+//
+// Each transport sets the policy on the contexts of the requests it serves, so the
+// choice is explicit per request rather than inferred from whatever the context
+// happens to carry. In dual-transport mode STDIO and HTTP tool calls run in the same
+// process with different policies.
+type CredentialPolicy int
+
+const (
+	// PolicyUnset is the zero value: no transport declared a policy. API keys are never
+	// resolved for such a context, so a missing policy fails closed.
+	PolicyUnset CredentialPolicy = iota
+
+	// PolicyCallerKey requires the API key of the caller, stored in the context by the
+	// HTTP authentication middleware. The server's own key is never used, so a request
+	// that somehow lost its key cannot act with the server's permissions.
+	// Used by the HTTP transport with authentication enabled.
+	PolicyCallerKey
+
+	// PolicyServerKey uses the server's configured key (PLANTON_API_KEY).
+	// Used by the STDIO transport, whose only caller is the process that started the
+	// server, by the "tools call" command, and by the HTTP transport without
+	// authentication, which only listens on localhost.
+	PolicyServerKey
+)
+
+// String returns the policy name used in logs and error messages.
+func (p CredentialPolicy) String() string {
+	switch p {
+	case PolicyCallerKey:
+		return "caller-key"
+	case PolicyServerKey:
+		return "server-key"
+	default:
+		return "unset"
+	}
+}
+
+const credentialPolicyContextKey contextKey = "planton-credential-policy"
+
+// ErrNoCredentials is returned when no API key may be used for a tool call under its
+// credential policy.
+var ErrNoCredentials = errors.New("no API key available for this request")
+
+// WithCredentialPolicy returns a context carrying the credential policy for the tool
+// calls served with it.
+func WithCredentialPolicy(ctx context.Context, policy CredentialPolicy) context.Context {
+	return context.WithValue(ctx, credentialPolicyContextKey, policy)
+}
+
+// CredentialPolicyFromContext returns the credential policy set on the context, or
+// PolicyUnset if there is none.
+func CredentialPolicyFromContext(ctx context.Context) CredentialPolicy {
+	policy, _ := ctx.Value(credentialPolicyContextKey).(CredentialPolicy)
+	return policy
+}
+
+// ResolveAPIKey returns the API key to call Planton APIs with, following the
+// context's credential policy.
+//
+// Args:
+//   - ctx: Context of the tool call, carrying its credential policy and, for HTTP
+//     requests, the caller's API key
+//   - serverKey: The server's configured API key (PLANTON_API_KEY), possibly empty
+//
+// Returns the API key, or an error wrapping ErrNoCredentials if the policy allows no
+// key: the caller's key is missing under PolicyCallerKey, no server key is configured
+// under PolicyServerKey, or the context has no policy.
+func ResolveAPIKey(ctx context.Context, serverKey string) (string, error) {
+	switch CredentialPolicyFromContext(ctx) {
+	case PolicyCallerKey:
+		apiKey, err := GetAPIKey(ctx)
+		if err != nil {
+			// Never fall back to the server's key here
+			return "", fmt.Errorf("%w: the request carries no caller API key", ErrNoCredentials)
+		}
+		return apiKey, nil
+	case PolicyServerKey:
+		if serverKey == "" {
+			return "", fmt.Errorf("%w: PLANTON_API_KEY is not set", ErrNoCredentials)
+		}
+		return serverKey, nil
+	default:
+		return "", fmt.Errorf("%w: the request has no credential policy", ErrNoCredentials)
+	}
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestResolveAPIKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    CredentialPolicy
+		callerKey string
+		serverKey string
+		want      string
+		wantErr   bool
+	}{
+		{name: "caller key", policy: PolicyCallerKey, callerKey: "key-caller", serverKey: "key-server", want: "key-caller"},
+		{name: "caller key missing never falls back", policy: PolicyCallerKey, serverKey: "key-server", wantErr: true},
+		{name: "server key", policy: PolicyServerKey, serverKey: "key-server", want: "key-server"},
+		{name: "server key ignores caller key", policy: PolicyServerKey, callerKey: "key-caller", serverKey: "key-server", want: "key-server"},
+		{name: "server key not configured", policy: PolicyServerKey, wantErr: true},
+		{name: "no policy", callerKey: "key-caller", serverKey: "key-server", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.policy != PolicyUnset {
+				ctx = WithCredentialPolicy(ctx, tt.policy)
+			}
+			if tt.callerKey != "" {
+				ctx = WithAPIKey(ctx, tt.callerKey)
+			}
+
+			got, err := ResolveAPIKey(ctx, tt.serverKey)
+			if tt.wantErr {
+				if !errors.Is(err, ErrNoCredentials) {
+					t.Fatalf("ResolveAPIKey() error = %v, want ErrNoCredentials", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveAPIKey() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveAPIKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package auth
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"sync"
+	"time"
+)
+
+// tokenVerifyTimeout bounds each token check against Planton APIs.
+const tokenVerifyTimeout = 5 * time.Second
+
+// ErrTokenRejected is returned when Planton APIs reject a bearer token as
+// unauthenticated: the API key is unknown, expired or revoked.
+var ErrTokenRejected = errors.New("bearer token rejected by Planton APIs")
+
+// VerifyFunc checks a bearer token with an authenticated Planton API call.
+//
+// It returns nil if the token is accepted, an error wrapping ErrTokenRejected if it
+// is rejected, and any other error if the check itself failed (e.g. the API was
+// unreachable). Only the first two outcomes are cached.
+type VerifyFunc func(ctx context.Context, token string) error
+
+// TokenCacheOptions configures the result cache of a TokenVerifier.
+type TokenCacheOptions struct {
+	// TTL is how long an accepted token is trusted before it is checked again
+	TTL time.Duration
+	// RejectedTTL is how long a rejected token is rejected without a new check
+	RejectedTTL time.Duration
+	// Size is the maximum number of cached results; least recently used results
+	// are evicted first. Zero disables the cache.
+	Size int
+}
+
+// tokenCacheEntry is a cached token validation result.
+type tokenCacheEntry struct {
+	key       [sha256.Size]byte
+	err       error
+	expiresAt time.Time
+}
+
+// pendingVerification is a token check in progress. Concurrent requests with the
+// same token wait for it instead of starting their own.
+type pendingVerification struct {
+	done chan struct{}
+	err  error
+}
+
+// TokenVerifier pre-validates bearer tokens that cannot be checked locally, such as
+// opaque Planton API keys, so bad tokens are rejected when a client connects rather
+// than halfway through an agent's plan.
+//
+// Results are kept in a bounded LRU cache keyed by the SHA-256 hash of the token;
+// tokens themselves are never stored.
+type TokenVerifier struct {
+	verify VerifyFunc
+	opts   TokenCacheOptions
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]*list.Element
+	lru     *list.List // front is most recently used
+	pending map[[sha256.Size]byte]*pendingVerification
+}
+
+// NewTokenVerifier creates a token verifier.
+//
+// Args:
+//   - verify: Checks a token with Planton APIs
+//   - opts: Cache TTLs and size
+//
+// Returns the token verifier.
+func NewTokenVerifier(verify VerifyFunc, opts TokenCacheOptions) *TokenVerifier {
+	return &TokenVerifier{
+		verify:  verify,
+		opts:    opts,
+		entries: make(map[[sha256.Size]byte]*list.Element),
+		lru:     list.New(),
+		pending: make(map[[sha256.Size]byte]*pendingVerification),
+	}
+}
+
+// Verify checks a bearer token, using a cached result when one has not expired.
+//
+// The check runs detached from ctx's cancellation so that one client giving up does
+// not fail other requests waiting on the same token; ctx only bounds how long this
+// caller waits.
+//
+// Returns whether the result came from the cache, and nil if the token is accepted,
+// an error wrapping ErrTokenRejected if it is rejected, or the error of a check that
+// could not be completed.
+func (v *TokenVerifier) Verify(ctx context.Context, token string) (cached bool, err error) {
+	key := sha256.Sum256([]byte(token))
+
+	v.mu.Lock()
+	if elem, ok := v.entries[key]; ok {
+		entry := elem.Value.(*tokenCacheEntry)
+		if time.Now().Before(entry.expiresAt) {
+			v.lru.MoveToFront(elem)
+			v.mu.Unlock()
+			return true, entry.err
+		}
+		v.lru.Remove(elem)
+		delete(v.entries, key)
+	}
+
+	p, inFlight := v.pending[key]
+	if !inFlight {
+		p = &pendingVerification{done: make(chan struct{})}
+		v.pending[key] = p
+		go v.run(context.WithoutCancel(ctx), key, token, p)
+	}
+	v.mu.Unlock()
+
+	select {
+	case <-p.done:
+		return false, p.err
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// run performs a token check and caches its result.
+func (v *TokenVerifier) run(ctx context.Context, key [sha256.Size]byte, token string, p *pendingVerification) {
+	ctx, cancel := context.WithTimeout(ctx, tokenVerifyTimeout)
+	p.err = v.verify(ctx, token)
+	cancel()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	// Waiters are released once the result is cached, so their next call hits the cache
+	defer close(p.done)
+	delete(v.pending, key)
+
+	ttl := v.opts.TTL
+	if p.err != nil {
+		if !errors.Is(p.err, ErrTokenRejected) {
+			return
+		}
+		ttl = v.opts.RejectedTTL
+	}
+	if ttl <= 0 || v.opts.Size <= 0 {
+		return
+	}
+
+	v.entries[key] = v.lru.PushFront(&tokenCacheEntry{
+		key:       key,
+		err:       p.err,
+		expiresAt: time.Now().Add(ttl),
+	})
+	for v.lru.Len() > v.opts.Size {
+		oldest := v.lru.Back()
+		v.lru.Remove(oldest)
+		delete(v.entries, oldest.Value.(*tokenCacheEntry).key)
+	}
+}
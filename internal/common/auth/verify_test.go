@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingVerifier is a VerifyFunc that counts its calls per token and returns the
+// result configured for the token. Calls block while release is non-nil and open.
+type countingVerifier struct {
+	mu      sync.Mutex
+	calls   map[string]int
+	results map[string]error
+	release chan struct{}
+}
+
+func newCountingVerifier() *countingVerifier {
+	return &countingVerifier{calls: make(map[string]int), results: make(map[string]error)}
+}
+
+func (c *countingVerifier) verify(ctx context.Context, token string) error {
+	c.mu.Lock()
+	c.calls[token]++
+	release := c.release
+	result := c.results[token]
+	c.mu.Unlock()
+
+	if release != nil {
+		<-release
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return result
+}
+
+func (c *countingVerifier) count(token string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[token]
+}
+
+// verifyTwice verifies a token twice and returns whether each result was cached.
+func verifyTwice(t *testing.T, v *TokenVerifier, token string) (bool, bool) {
+	t.Helper()
+	first, _ := v.Verify(context.Background(), token)
+	second, _ := v.Verify(context.Background(), token)
+	return first, second
+}
+
+func TestTokenVerifierCachesAcceptedTokens(t *testing.T) {
+	c := newCountingVerifier()
+	v := NewTokenVerifier(c.verify, TokenCacheOptions{TTL: 50 * time.Millisecond, RejectedTTL: time.Hour, Size: 10})
+
+	if first, second := verifyTwice(t, v, "good"); first || !second {
+		t.Errorf("cached = %t, %t; want false, true", first, second)
+	}
+	if n := c.count("good"); n != 1 {
+		t.Errorf("checks within TTL = %d, want 1", n)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if cached, err := v.Verify(context.Background(), "good"); cached || err != nil {
+		t.Errorf("Verify() after TTL = %t, %v; want a new check", cached, err)
+	}
+	if n := c.count("good"); n != 2 {
+		t.Errorf("checks after TTL = %d, want 2", n)
+	}
+}
+
+func TestTokenVerifierCachesRejectedTokensSeparately(t *testing.T) {
+	c := newCountingVerifier()
+	c.results["revoked"] = fmt.Errorf("%w: key revoked", ErrTokenRejected)
+	v := NewTokenVerifier(c.verify, TokenCacheOptions{TTL: time.Hour, RejectedTTL: 30 * time.Millisecond, Size: 10})
+
+	for i, wantCached := range []bool{false, true} {
+		cached, err := v.Verify(context.Background(), "revoked")
+		if cached != wantCached || !errors.Is(err, ErrTokenRejected) {
+			t.Errorf("call %d: Verify() = %t, %v; want %t, ErrTokenRejected", i+1, cached, err, wantCached)
+		}
+	}
+
+	time.Sleep(40 * time.Millisecond)
+	v.Verify(context.Background(), "revoked")
+	if n := c.count("revoked"); n != 2 {
+		t.Errorf("checks after the rejected TTL = %d, want 2", n)
+	}
+}
+
+func TestTokenVerifierDoesNotCacheTransientErrors(t *testing.T) {
+	c := newCountingVerifier()
+	c.results["flaky"] = errors.New("rpc error: code = Unavailable")
+	v := NewTokenVerifier(c.verify, TokenCacheOptions{TTL: time.Hour, RejectedTTL: time.Hour, Size: 10})
+
+	if first, second := verifyTwice(t, v, "flaky"); first || second {
+		t.Errorf("cached = %t, %t; want false, false", first, second)
+	}
+	if n := c.count("flaky"); n != 2 {
+		t.Errorf("checks = %d, want 2", n)
+	}
+}
+
+func TestTokenVerifierEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newCountingVerifier()
+	v := NewTokenVerifier(c.verify, TokenCacheOptions{TTL: time.Hour, RejectedTTL: time.Hour, Size: 2})
+
+	for _, token := range []string{"a", "b", "a", "c"} {
+		v.Verify(context.Background(), token)
+	}
+	// "b" was the least recently used when "c" was added
+	for _, tt := range []struct {
+		token      string
+		wantCached bool
+	}{{"a", true}, {"c", true}, {"b", false}} {
+		if cached, _ := v.Verify(context.Background(), tt.token); cached != tt.wantCached {
+			t.Errorf("%s cached = %t, want %t", tt.token, cached, tt.wantCached)
+		}
+	}
+
+	zero := NewTokenVerifier(c.verify, TokenCacheOptions{TTL: time.Hour, RejectedTTL: time.Hour})
+	if first, second := verifyTwice(t, zero, "d"); first || second {
+		t.Errorf("cache of size 0: cached = %t, %t; want false, false", first, second)
+	}
+}
+
+func TestTokenVerifierSharesConcurrentChecks(t *testing.T) {
+	c := newCountingVerifier()
+	c.release = make(chan struct{})
+	v := NewTokenVerifier(c.verify, TokenCacheOptions{TTL: time.Hour, RejectedTTL: time.Hour, Size: 10})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := v.Verify(context.Background(), "good")
+			errs <- err
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(c.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Verify() = %v", err)
+		}
+	}
+	if n := c.count("good"); n != 1 {
+		t.Errorf("checks = %d, want 1", n)
+	}
+}
+
+func TestTokenVerifierCanceledWaiterDoesNotFailOthers(t *testing.T) {
+	c := newCountingVerifier()
+	c.release = make(chan struct{})
+	v := NewTokenVerifier(c.verify, TokenCacheOptions{TTL: time.Hour, RejectedTTL: time.Hour, Size: 10})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error, 1)
+	go func() {
+		_, err := v.Verify(ctx, "good")
+		canceled <- err
+	}()
+	other := make(chan error, 1)
+	go func() {
+		_, err := v.Verify(context.Background(), "good")
+		other <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if err := <-canceled; !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled waiter: Verify() = %v, want context.Canceled", err)
+	}
+
+	close(c.release)
+	if err := <-other; err != nil {
+		t.Errorf("other waiter: Verify() = %v, want nil", err)
+	}
+	if cached, err := v.Verify(context.Background(), "good"); !cached || err != nil {
+		t.Errorf("Verify() after the check = %t, %v; want the cached result", cached, err)
+	}
+}
@@ -0,0 +1,88 @@
+// Package buildinfo describes the build of the running server binary.
+//
+// Release builds inject the version, commit and build date into the main package
+// with linker flags (see .goreleaser.yaml), and main hands them to Set. Builds
+// without linker flags (go build, go install) fall back to the module version and
+// VCS information that the Go toolchain embeds in the binary.
+package buildinfo
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// DevVersion is the version of builds without a release version.
+const DevVersion = "dev"
+
+// Info describes a build of the server.
+type Info struct {
+	// Version is the release version, e.g. "v0.4.0", or "dev"
+	Version string
+
+	// Commit is the VCS revision the binary was built from; empty when unknown
+	Commit string
+
+	// Date is the build date (RFC 3339) or, without linker flags, the commit date;
+	// empty when unknown
+	Date string
+}
+
+// current is the build info of the running binary
+var current = fromBinary(Info{Version: DevVersion})
+
+// Set records the build info injected with linker flags. Empty fields are filled
+// from the information embedded by the Go toolchain. Call it at startup, before
+// the server is created.
+//
+// Args:
+//   - info: Version, commit and date set on the main package at link time
+func Set(info Info) {
+	current = fromBinary(info)
+}
+
+// Get returns the build info of the running binary.
+func Get() Info {
+	return current
+}
+
+// String returns a one-line description, e.g.
+// "v0.4.0 (commit 1a2b3c4, built 2026-10-16T12:00:00Z, go1.24.0 linux/amd64)".
+func (i Info) String() string {
+	details := ""
+	if i.Commit != "" {
+		details += "commit " + i.Commit + ", "
+	}
+	if i.Date != "" {
+		details += "built " + i.Date + ", "
+	}
+	return fmt.Sprintf("%s (%s%s %s/%s)", i.Version, details, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}
+
+// fromBinary fills the empty fields of info from the build information embedded
+// in the binary by the Go toolchain
+func fromBinary(info Info) Info {
+	if info.Version == "" {
+		info.Version = DevVersion
+	}
+
+	embedded, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	// go install module@version records the module version; local builds record
+	// "(devel)" or a pseudo-version derived from VCS state
+	if info.Version == DevVersion && embedded.Main.Version != "" && embedded.Main.Version != "(devel)" {
+		info.Version = embedded.Main.Version
+	}
+	for _, setting := range embedded.Settings {
+		switch {
+		case setting.Key == "vcs.revision" && info.Commit == "":
+			info.Commit = setting.Value
+		case setting.Key == "vcs.time" && info.Date == "":
+			info.Date = setting.Value
+		}
+	}
+	return info
+}
@@ -0,0 +1,107 @@
+package clientfactory
+
+import (
+	"context"
+
+	pipelinev1grpc "buf.build/gen/go/blintora/apis/grpc/go/ai/planton/servicehub/pipeline/v1/pipelinev1grpc"
+	commonsapiresource "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/commons/apiresource"
+	"buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/commons/protobuf"
+	githubcredentialv1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/connect/githubcredential/v1"
+	cloudresourcev1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/infrahub/cloudresource/v1"
+	environmentv1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/resourcemanager/environment/v1"
+	organizationv1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/resourcemanager/organization/v1"
+	searchapiresource "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/search/v1/apiresource"
+	cloudresourcesearch "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/search/v1/infrahub/cloudresource"
+	pipelinev1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/servicehub/pipeline/v1"
+	servicev1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/servicehub/service/v1"
+	tektonpipelinev1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/servicehub/tektonpipeline/v1"
+	cloudresourcekind "buf.build/gen/go/project-planton/apis/protocolbuffers/go/org/project_planton/shared/cloudresourcekind"
+)
+
+// The interfaces below are the client methods tool handlers use. The gRPC clients in
+// internal/domains/*/clients implement them; tests implement them with fakes.
+// Every client must be closed after use.
+
+// OrganizationClient queries organizations.
+type OrganizationClient interface {
+	List(ctx context.Context) ([]*organizationv1.Organization, error)
+	Close() error
+}
+
+// EnvironmentClient queries environments.
+type EnvironmentClient interface {
+	FindByOrg(ctx context.Context, orgID string) ([]*environmentv1.Environment, error)
+	Close() error
+}
+
+// CloudResourceQueryClient queries cloud resources.
+type CloudResourceQueryClient interface {
+	GetById(ctx context.Context, resourceID string) (*cloudresourcev1.CloudResource, error)
+	Close() error
+}
+
+// CloudResourceSearchClient searches cloud resources.
+type CloudResourceSearchClient interface {
+	GetCloudResourcesCanvasView(
+		ctx context.Context,
+		orgID string,
+		envNames []string,
+		kinds []cloudresourcekind.CloudResourceKind,
+		searchText string,
+	) (*cloudresourcesearch.ExploreCloudResourcesCanvasViewResponse, error)
+	LookupCloudResource(
+		ctx context.Context,
+		orgID string,
+		envName string,
+		kind cloudresourcekind.CloudResourceKind,
+		name string,
+	) (*searchapiresource.ApiResourceSearchRecord, error)
+	Close() error
+}
+
+// CloudResourceCommandClient creates, updates and deletes cloud resources.
+type CloudResourceCommandClient interface {
+	Create(ctx context.Context, resource *cloudresourcev1.CloudResource) (*cloudresourcev1.CloudResource, error)
+	Update(ctx context.Context, resource *cloudresourcev1.CloudResource) (*cloudresourcev1.CloudResource, error)
+	Delete(ctx context.Context, resourceID string) (*cloudresourcev1.CloudResource, error)
+	Close() error
+}
+
+// ServiceClient queries services.
+type ServiceClient interface {
+	GetById(ctx context.Context, serviceID string) (*servicev1.Service, error)
+	GetByOrgBySlug(ctx context.Context, orgID, slug string) (*servicev1.Service, error)
+	ListBranches(ctx context.Context, serviceID string) (*protobuf.StringList, error)
+	Find(ctx context.Context, request *commonsapiresource.FindApiResourcesRequest) (*servicev1.ServiceList, error)
+	Close() error
+}
+
+// PipelineClient queries pipelines and streams their logs and status.
+type PipelineClient interface {
+	GetById(ctx context.Context, pipelineID string) (*pipelinev1.Pipeline, error)
+	GetLastPipelineByServiceId(ctx context.Context, serviceID string) (*pipelinev1.Pipeline, error)
+	GetLogStream(ctx context.Context, pipelineID string) (pipelinev1grpc.PipelineQueryController_GetLogStreamClient, error)
+	GetStatusStream(ctx context.Context, pipelineID string) (pipelinev1grpc.PipelineQueryController_GetStatusStreamClient, error)
+	Close() error
+}
+
+// TektonPipelineClient queries Tekton pipelines.
+type TektonPipelineClient interface {
+	GetById(ctx context.Context, pipelineID string) (*tektonpipelinev1.TektonPipeline, error)
+	GetByOrgAndName(ctx context.Context, orgID, name string) (*tektonpipelinev1.TektonPipeline, error)
+	Close() error
+}
+
+// GithubCredentialClient queries GitHub credentials.
+type GithubCredentialClient interface {
+	GetById(ctx context.Context, credentialID string) (*githubcredentialv1.GithubCredential, error)
+	GetByOrgBySlug(ctx context.Context, orgID, slug string) (*githubcredentialv1.GithubCredential, error)
+	Close() error
+}
+
+// GithubQueryClient queries GitHub through a GitHub credential.
+type GithubQueryClient interface {
+	FindGithubRepositories(ctx context.Context, credentialID string) (*githubcredentialv1.FindGithubRepositoriesResponse, error)
+	GetInstallationToken(ctx context.Context, credentialID string) (*githubcredentialv1.GithubInstallationToken, error)
+	Close() error
+}
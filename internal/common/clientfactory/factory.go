@@ -0,0 +1,148 @@
+// Package clientfactory creates the Planton API clients tool handlers use, with the
+// credentials the tool call is allowed to use.
+//
+// Handlers receive a Factory instead of constructing gRPC clients themselves. The
+// factory resolves the API key once, through the credential policy the transport set
+// on the request context (see auth.ResolveAPIKey), so no handler decides on its own
+// whether to fall back to the server's key. Tests pass a fake Factory to unit-test
+// handlers without Planton APIs.
+package clientfactory
+
+import (
+	"context"
+
+	"github.com/plantoncloud/mcp-server-planton/internal/common/auth"
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	connectclients "github.com/plantoncloud/mcp-server-planton/internal/domains/connect/clients"
+	infrahubclients "github.com/plantoncloud/mcp-server-planton/internal/domains/infrahub/clients"
+	resourcemanagerclients "github.com/plantoncloud/mcp-server-planton/internal/domains/resourcemanager/clients"
+	servicehubclients "github.com/plantoncloud/mcp-server-planton/internal/domains/servicehub/clients"
+)
+
+// Factory creates Planton API clients for a tool call.
+//
+// Every method resolves the API key from ctx and returns an error wrapping
+// auth.ErrNoCredentials when the call's credential policy allows none.
+type Factory interface {
+	OrganizationClient(ctx context.Context) (OrganizationClient, error)
+	EnvironmentClient(ctx context.Context) (EnvironmentClient, error)
+	CloudResourceQueryClient(ctx context.Context) (CloudResourceQueryClient, error)
+	CloudResourceSearchClient(ctx context.Context) (CloudResourceSearchClient, error)
+	CloudResourceCommandClient(ctx context.Context) (CloudResourceCommandClient, error)
+	ServiceClient(ctx context.Context) (ServiceClient, error)
+	PipelineClient(ctx context.Context) (PipelineClient, error)
+	TektonPipelineClient(ctx context.Context) (TektonPipelineClient, error)
+	GithubCredentialClient(ctx context.Context) (GithubCredentialClient, error)
+	GithubQueryClient(ctx context.Context) (GithubQueryClient, error)
+}
+
+// grpcFactory creates gRPC clients to a Planton APIs endpoint.
+type grpcFactory struct {
+	endpoint string
+	// serverKey is the server's configured API key, used only under auth.PolicyServerKey
+	serverKey string
+}
+
+// New creates the Factory the server's tool handlers use.
+//
+// Args:
+//   - cfg: Server configuration providing the Planton APIs endpoint and the server's
+//     own API key
+//
+// Returns a Factory creating gRPC clients to the configured endpoint.
+func New(cfg *config.Config) Factory {
+	return &grpcFactory{
+		endpoint:  cfg.PlantonAPIsGRPCEndpoint,
+		serverKey: cfg.PlantonAPIKey,
+	}
+}
+
+// OrganizationClient creates an organization client for the tool call in ctx.
+func (f *grpcFactory) OrganizationClient(ctx context.Context) (OrganizationClient, error) {
+	apiKey, err := auth.ResolveAPIKey(ctx, f.serverKey)
+	if err != nil {
+		return nil, err
+	}
+	return resourcemanagerclients.NewOrganizationClient(f.endpoint, apiKey)
+}
+
+// EnvironmentClient creates an environment client for the tool call in ctx.
+func (f *grpcFactory) EnvironmentClient(ctx context.Context) (EnvironmentClient, error) {
+	apiKey, err := auth.ResolveAPIKey(ctx, f.serverKey)
+	if err != nil {
+		return nil, err
+	}
+	return resourcemanagerclients.NewEnvironmentClient(f.endpoint, apiKey)
+}
+
+// CloudResourceQueryClient creates a cloud resource query client for the tool call in ctx.
+func (f *grpcFactory) CloudResourceQueryClient(ctx context.Context) (CloudResourceQueryClient, error) {
+	apiKey, err := auth.ResolveAPIKey(ctx, f.serverKey)
+	if err != nil {
+		return nil, err
+	}
+	return infrahubclients.NewCloudResourceQueryClient(f.endpoint, apiKey)
+}
+
+// CloudResourceSearchClient creates a cloud resource search client for the tool call in ctx.
+func (f *grpcFactory) CloudResourceSearchClient(ctx context.Context) (CloudResourceSearchClient, error) {
+	apiKey, err := auth.ResolveAPIKey(ctx, f.serverKey)
+	if err != nil {
+		return nil, err
+	}
+	return infrahubclients.NewCloudResourceSearchClient(f.endpoint, apiKey)
+}
+
+// CloudResourceCommandClient creates a cloud resource command client for the tool call in ctx.
+func (f *grpcFactory) CloudResourceCommandClient(ctx context.Context) (CloudResourceCommandClient, error) {
+	apiKey, err := auth.ResolveAPIKey(ctx, f.serverKey)
+	if err != nil {
+		return nil, err
+	}
+	return infrahubclients.NewCloudResourceCommandClient(f.endpoint, apiKey)
+}
+
+// ServiceClient creates a service client for the tool call in ctx.
+func (f *grpcFactory) ServiceClient(ctx context.Context) (ServiceClient, error) {
+	apiKey, err := auth.ResolveAPIKey(ctx, f.serverKey)
+	if err != nil {
+		return nil, err
+	}
+	return servicehubclients.NewServiceClient(f.endpoint, apiKey)
+}
+
+// PipelineClient creates a pipeline client for the tool call in ctx.
+func (f *grpcFactory) PipelineClient(ctx context.Context) (PipelineClient, error) {
+	apiKey, err := auth.ResolveAPIKey(ctx, f.serverKey)
+	if err != nil {
+		return nil, err
+	}
+	return servicehubclients.NewPipelineClient(f.endpoint, apiKey)
+}
+
+// TektonPipelineClient creates a Tekton pipeline client for the tool call in ctx.
+func (f *grpcFactory) TektonPipelineClient(ctx context.Context) (TektonPipelineClient, error) {
+	apiKey, err := auth.ResolveAPIKey(ctx, f.serverKey)
+	if err != nil {
+		return nil, err
+	}
+	return servicehubclients.NewTektonPipelineClient(f.endpoint, apiKey)
+}
+
+// GithubCredentialClient creates a GitHub credential client for the tool call in ctx.
+func (f *grpcFactory) GithubCredentialClient(ctx context.Context) (GithubCredentialClient, error) {
+	apiKey, err := auth.ResolveAPIKey(ctx, f.serverKey)
+	if err != nil {
+		return nil, err
+	}
+	return connectclients.NewGithubCredentialClient(f.endpoint, apiKey)
+}
+
+// GithubQueryClient creates a GitHub query client for the tool call in ctx.
+func (f *grpcFactory) GithubQueryClient(ctx context.Context) (GithubQueryClient, error) {
+	apiKey, err := auth.ResolveAPIKey(ctx, f.serverKey)
+	if err != nil {
+		return nil, err
+	}
+	return connectclients.NewGithubQueryClient(f.endpoint, apiKey)
+}
@@ -1,73 +1,225 @@
 package errors
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	"math"
 
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
 	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// Error codes of tool errors. They are part of the tool contract: clients may branch
+// on them, so renaming a code is a breaking change.
+const (
+	CodeInvalidArgument    = "INVALID_ARGUMENT"
+	CodeNotFound           = "NOT_FOUND"
+	CodeAlreadyExists      = "ALREADY_EXISTS"
+	CodePermissionDenied   = "PERMISSION_DENIED"
+	CodeUnauthenticated    = "UNAUTHENTICATED"
+	CodeFailedPrecondition = "FAILED_PRECONDITION"
+	CodeAborted            = "ABORTED"
+	CodeOutOfRange         = "OUT_OF_RANGE"
+	CodeUnimplemented      = "UNIMPLEMENTED"
+	CodeUnavailable        = "UNAVAILABLE"
+	CodeRateLimited        = "RATE_LIMITED"
+	CodeTimeout            = "TIMEOUT"
+	CodeCancelled          = "CANCELLED"
+	CodeInternal           = "INTERNAL_ERROR"
+	CodeUnknown            = "UNKNOWN_ERROR"
+	// CodeClientError means the server could not create a Planton API client
+	CodeClientError = "CLIENT_ERROR"
+	// CodeStreamError means a stream from Planton APIs failed part way
+	CodeStreamError = "STREAM_ERROR"
+)
+
+// grpcErrorCodes maps the status codes of Planton APIs to tool error codes
+var grpcErrorCodes = map[codes.Code]string{
+	codes.Canceled:           CodeCancelled,
+	codes.Unknown:            CodeUnknown,
+	codes.InvalidArgument:    CodeInvalidArgument,
+	codes.DeadlineExceeded:   CodeTimeout,
+	codes.NotFound:           CodeNotFound,
+	codes.AlreadyExists:      CodeAlreadyExists,
+	codes.PermissionDenied:   CodePermissionDenied,
+	codes.ResourceExhausted:  CodeRateLimited,
+	codes.FailedPrecondition: CodeFailedPrecondition,
+	codes.Aborted:            CodeAborted,
+	codes.OutOfRange:         CodeOutOfRange,
+	codes.Unimplemented:      CodeUnimplemented,
+	codes.Internal:           CodeInternal,
+	codes.Unavailable:        CodeUnavailable,
+	codes.DataLoss:           CodeInternal,
+	codes.Unauthenticated:    CodeUnauthenticated,
+}
+
+// Resource identifies what a failed tool call was about. Set the fields that apply:
+// an organization, the ID of a resource (cloud resource, service, credential) or of a
+// pipeline, or a name looked up in an organization.
+type Resource struct {
+	OrgID      string `json:"org_id,omitempty"`
+	ResourceID string `json:"resource_id,omitempty"`
+	PipelineID string `json:"pipeline_id,omitempty"`
+	// Name is a name or slug looked up in OrgID
+	Name string `json:"name,omitempty"`
+}
+
+// String describes the resource for error messages, e.g. "pipeline 'pipe-123'".
+// It returns "" for an empty Resource.
+func (r Resource) String() string {
+	var s string
+	switch {
+	case r.PipelineID != "":
+		s = fmt.Sprintf("pipeline '%s'", r.PipelineID)
+	case r.ResourceID != "":
+		s = fmt.Sprintf("resource '%s'", r.ResourceID)
+	case r.Name != "":
+		s = fmt.Sprintf("'%s'", r.Name)
+	case r.OrgID != "":
+		return fmt.Sprintf("organization '%s'", r.OrgID)
+	default:
+		return ""
+	}
+	if r.OrgID != "" {
+		s += fmt.Sprintf(" in organization '%s'", r.OrgID)
+	}
+	return s
+}
+
 // ErrorResponse represents an error response for MCP tool calls.
 type ErrorResponse struct {
+	// Error is the error code, one of the Code constants or a tool-specific code
 	Error   string `json:"error"`
 	Message string `json:"message"`
-	OrgID   string `json:"org_id,omitempty"`
+	// GRPCCode is the status code returned by Planton APIs, e.g. "NotFound". Empty
+	// when the error did not come from Planton APIs.
+	GRPCCode string `json:"grpc_code,omitempty"`
+	// Reason and Metadata come from the ErrorInfo detail of a Planton APIs error.
+	Reason   string            `json:"reason,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Resource
+	FieldViolations []FieldViolation `json:"field_violations,omitempty"`
+	// RetryAfterSeconds tells the caller how long to wait before retrying a
+	// rate-limited call. Zero means the error is not about rate limiting.
+	RetryAfterSeconds int `json:"retry_after_seconds,omitempty"`
+	// Hint suggests what to do next, e.g. which tool to call
+	Hint string `json:"hint,omitempty"`
+}
+
+// ToolError builds a tool result flagged as an error, whose text is the response
+// as JSON.
+//
+// Args:
+//   - resp: An ErrorResponse, or a struct embedding one to add tool-specific fields
+func ToolError(resp any) *mcp.CallToolResult {
+	errJSON, _ := json.MarshalIndent(resp, "", "  ")
+	return mcp.NewToolResultError(string(errJSON))
 }
 
 // HandleGRPCError converts gRPC errors to user-friendly error responses.
-// This is exported so it can be reused by all domains.
-func HandleGRPCError(err error, orgID string) *mcp.CallToolResult {
+// This is exported so it can be reused by all domains. ctx is only used to
+// correlate the log line with the tool call.
+//
+// The response carries the gRPC status code and the details Planton APIs attached
+// to it: field violations (protovalidate Violations or BadRequest), the ErrorInfo
+// reason and the retry delay.
+//
+// Args:
+//   - err: Error returned by a Planton API client
+//   - resource: What the call was about, named in the message and the response
+func HandleGRPCError(ctx context.Context, err error, resource Resource) *mcp.CallToolResult {
+	errResp := FromGRPCError(err, resource)
+	LogToolError(ctx, errResp)
+	return ToolError(errResp)
+}
+
+// LogToolError logs a tool error built from a Planton API error.
+func LogToolError(ctx context.Context, errResp ErrorResponse) {
+	slog.WarnContext(ctx, "Tool error",
+		"code", errResp.Error,
+		"grpc_code", errResp.GRPCCode,
+		"reason", errResp.Reason,
+		"resource", errResp.Resource.String(),
+		"message", errResp.Message,
+		"field_violations", len(errResp.FieldViolations),
+	)
+}
+
+// FromGRPCError builds the error response of a Planton API error without logging it.
+func FromGRPCError(err error, resource Resource) ErrorResponse {
 	st, ok := status.FromError(err)
 	if !ok {
-		errResp := ErrorResponse{
-			Error:   "UNKNOWN_ERROR",
-			Message: fmt.Sprintf("An unexpected error occurred: %v", err),
-			OrgID:   orgID,
+		return ErrorResponse{
+			Error:    CodeUnknown,
+			Message:  fmt.Sprintf("An unexpected error occurred: %v", err),
+			Resource: resource,
 		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON))
 	}
 
-	var errResp ErrorResponse
-	errResp.OrgID = orgID
+	errResp := ErrorResponse{
+		Error:    grpcErrorCodes[st.Code()],
+		GRPCCode: st.Code().String(),
+		Resource: resource,
+	}
+	if errResp.Error == "" {
+		errResp.Error = CodeUnknown
+	}
+	addDetails(&errResp, st)
 
+	subject := resource.String()
+	if subject == "" {
+		subject = "this resource"
+	}
 	switch st.Code() {
 	case codes.Unauthenticated:
-		errResp.Error = "UNAUTHENTICATED"
 		errResp.Message = "Authentication failed. Your session may have expired. Please refresh and try again."
 
 	case codes.PermissionDenied:
-		errResp.Error = "PERMISSION_DENIED"
 		errResp.Message = fmt.Sprintf(
-			"You don't have permission to access this resource for organization '%s'. "+
-				"Please contact your organization administrator.",
-			orgID,
+			"You don't have permission to access %s. Please contact your organization administrator.",
+			subject,
 		)
 
 	case codes.Unavailable:
-		errResp.Error = "UNAVAILABLE"
 		errResp.Message = "Planton Cloud APIs are currently unavailable. Please try again in a moment."
 
 	case codes.NotFound:
-		errResp.Error = "NOT_FOUND"
-		errResp.Message = fmt.Sprintf("Resource not found for organization '%s'.", orgID)
+		errResp.Message = fmt.Sprintf("Could not find %s.", subject)
 
 	default:
-		errResp.Error = st.Code().String()
 		errResp.Message = st.Message()
 		if errResp.Message == "" {
 			errResp.Message = "An unexpected error occurred."
 		}
 	}
+	return errResp
+}
 
-	log.Printf(
-		"Tool error: org_id=%s, code=%s, message=%s",
-		orgID, errResp.Error, errResp.Message,
-	)
-
-	errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-	return mcp.NewToolResultText(string(errJSON))
+// addDetails copies the error details of a status into the response. Details of
+// other types are ignored. The field violations of an INVALID_ARGUMENT status
+// without violation details are parsed from its message.
+func addDetails(errResp *ErrorResponse, st *status.Status) {
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *validate.Violations:
+			errResp.FieldViolations = append(errResp.FieldViolations, ProtovalidateViolations(detail)...)
+		case *errdetails.BadRequest:
+			errResp.FieldViolations = append(errResp.FieldViolations, badRequestViolations(detail)...)
+		case *errdetails.ErrorInfo:
+			errResp.Reason = detail.GetReason()
+			errResp.Metadata = detail.GetMetadata()
+		case *errdetails.RetryInfo:
+			if delay := detail.GetRetryDelay().AsDuration(); delay > 0 {
+				errResp.RetryAfterSeconds = int(math.Ceil(delay.Seconds()))
+			}
+		}
+	}
+	if st.Code() == codes.InvalidArgument && len(errResp.FieldViolations) == 0 {
+		errResp.FieldViolations = messageViolations(st.Message())
+	}
 }
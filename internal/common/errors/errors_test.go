@@ -0,0 +1,213 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"slices"
+	"testing"
+	"time"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestResourceString(t *testing.T) {
+	tests := []struct {
+		resource Resource
+		want     string
+	}{
+		{Resource{}, ""},
+		{Resource{OrgID: "acme"}, "organization 'acme'"},
+		{Resource{PipelineID: "pipe-123"}, "pipeline 'pipe-123'"},
+		{Resource{ResourceID: "svc-123"}, "resource 'svc-123'"},
+		{Resource{OrgID: "acme", ResourceID: "awsvpc-123"}, "resource 'awsvpc-123' in organization 'acme'"},
+		{Resource{OrgID: "acme", Name: "backend"}, "'backend' in organization 'acme'"},
+	}
+	for _, tt := range tests {
+		if got := tt.resource.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.resource, got, tt.want)
+		}
+	}
+}
+
+func TestFromGRPCError(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		resource    Resource
+		wantCode    string
+		wantGRPC    string
+		wantMessage string
+	}{
+		{
+			name:        "not found names the pipeline",
+			err:         status.Error(codes.NotFound, "pipeline not found"),
+			resource:    Resource{PipelineID: "pipe-123"},
+			wantCode:    CodeNotFound,
+			wantGRPC:    "NotFound",
+			wantMessage: "Could not find pipeline 'pipe-123'.",
+		},
+		{
+			name:        "permission denied without resource",
+			err:         status.Error(codes.PermissionDenied, "denied"),
+			wantCode:    CodePermissionDenied,
+			wantGRPC:    "PermissionDenied",
+			wantMessage: "You don't have permission to access this resource. Please contact your organization administrator.",
+		},
+		{
+			name:        "deadline exceeded keeps the backend message",
+			err:         status.Error(codes.DeadlineExceeded, "query took too long"),
+			wantCode:    CodeTimeout,
+			wantGRPC:    "DeadlineExceeded",
+			wantMessage: "query took too long",
+		},
+		{
+			name:        "not a gRPC status",
+			err:         stderrors.New("connection reset"),
+			wantCode:    CodeUnknown,
+			wantMessage: "An unexpected error occurred: connection reset",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromGRPCError(tt.err, tt.resource)
+			if got.Error != tt.wantCode || got.GRPCCode != tt.wantGRPC || got.Message != tt.wantMessage {
+				t.Errorf("FromGRPCError() = %q, %q, %q; want %q, %q, %q",
+					got.Error, got.GRPCCode, got.Message, tt.wantCode, tt.wantGRPC, tt.wantMessage)
+			}
+			if got.Resource != tt.resource {
+				t.Errorf("Resource = %+v, want %+v", got.Resource, tt.resource)
+			}
+		})
+	}
+}
+
+func TestFromGRPCErrorDetails(t *testing.T) {
+	st, err := status.New(codes.InvalidArgument, "validation failed").WithDetails(
+		&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "spec.region", Description: "value is required"},
+		}},
+		&errdetails.ErrorInfo{Reason: "SPEC_INVALID", Domain: "planton.ai", Metadata: map[string]string{"kind": "AwsVpc"}},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(1500 * time.Millisecond)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := FromGRPCError(st.Err(), Resource{OrgID: "acme"})
+	if got.Error != CodeInvalidArgument || got.Message != "validation failed" {
+		t.Errorf("error = %q %q", got.Error, got.Message)
+	}
+	if len(got.FieldViolations) != 1 || got.FieldViolations[0] != (FieldViolation{FieldPath: "spec.region", Message: "value is required"}) {
+		t.Errorf("FieldViolations = %+v", got.FieldViolations)
+	}
+	if got.Reason != "SPEC_INVALID" || got.Metadata["kind"] != "AwsVpc" {
+		t.Errorf("Reason = %q, Metadata = %v", got.Reason, got.Metadata)
+	}
+	if got.RetryAfterSeconds != 2 {
+		t.Errorf("RetryAfterSeconds = %d, want 2", got.RetryAfterSeconds)
+	}
+}
+
+func TestHandleGRPCErrorResult(t *testing.T) {
+	result := HandleGRPCError(context.Background(), status.Error(codes.NotFound, ""), Resource{ResourceID: "svc-123"})
+	if !result.IsError {
+		t.Error("result is not flagged as an error")
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"error":       CodeNotFound,
+		"message":     "Could not find resource 'svc-123'.",
+		"grpc_code":   "NotFound",
+		"resource_id": "svc-123",
+	}
+	if len(got) != len(want) {
+		t.Errorf("response = %v, want %v", got, want)
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %v, want %v", key, got[key], value)
+		}
+	}
+}
+
+func TestFieldViolations(t *testing.T) {
+	element := func(name string) *validate.FieldPathElement {
+		return validate.FieldPathElement_builder{FieldName: proto.String(name)}.Build()
+	}
+	indexed := validate.FieldPathElement_builder{FieldName: proto.String("subnets"), Index: proto.Uint64(0)}.Build()
+	keyed := validate.FieldPathElement_builder{FieldName: proto.String("tags"), StringKey: proto.String("env")}.Build()
+	violations := validate.Violations_builder{Violations: []*validate.Violation{
+		validate.Violation_builder{
+			Field:   validate.FieldPath_builder{Elements: []*validate.FieldPathElement{element("spec"), indexed, element("cidr")}}.Build(),
+			RuleId:  proto.String("string.ip_prefix"),
+			Message: proto.String("value must be a valid IP prefix"),
+		}.Build(),
+		validate.Violation_builder{
+			Field:   validate.FieldPath_builder{Elements: []*validate.FieldPathElement{element("spec"), keyed}}.Build(),
+			RuleId:  proto.String("string.min_len"),
+			Message: proto.String("value length must be at least 1 characters"),
+		}.Build(),
+	}}.Build()
+
+	tests := []struct {
+		name    string
+		details []protoadapt.MessageV1
+		message string
+		want    []FieldViolation
+	}{
+		{
+			name:    "protovalidate violations",
+			details: []protoadapt.MessageV1{protoadapt.MessageV1Of(violations)},
+			message: "validation error",
+			want: []FieldViolation{
+				{FieldPath: "spec.subnets[0].cidr", Rule: "string.ip_prefix", Message: "value must be a valid IP prefix"},
+				{FieldPath: `spec.tags["env"]`, Rule: "string.min_len", Message: "value length must be at least 1 characters"},
+			},
+		},
+		{
+			name: "bad request with rule suffix",
+			details: []protoadapt.MessageV1{&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: "spec.region", Description: "value is required [required]"},
+			}}},
+			message: "invalid spec",
+			want:    []FieldViolation{{FieldPath: "spec.region", Rule: "required", Message: "value is required"}},
+		},
+		{
+			name:    "protovalidate message without details",
+			message: "validation error:\n - spec.region: value is required [required]\n - spec.cidr_block: value must be a valid CIDR [string.ip_prefix]\n - exactly one field is required in oneof [message.oneof]",
+			want: []FieldViolation{
+				{FieldPath: "spec.region", Rule: "required", Message: "value is required"},
+				{FieldPath: "spec.cidr_block", Rule: "string.ip_prefix", Message: "value must be a valid CIDR"},
+				{Rule: "message.oneof", Message: "exactly one field is required in oneof"},
+			},
+		},
+		{
+			name:    "other message",
+			message: "spec is invalid: region missing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, err := status.New(codes.InvalidArgument, tt.message).WithDetails(tt.details...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := FromGRPCError(st.Err(), Resource{}).FieldViolations
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("FieldViolations = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package errors
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// FieldViolation is a request field Planton APIs rejected.
+type FieldViolation struct {
+	// FieldPath is the path of the field, e.g. "spec.subnets[0].cidr"
+	FieldPath string `json:"field_path"`
+	// Rule is the ID of the failed validation rule, e.g. "string.min_len", if known
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message"`
+}
+
+// validationErrorLine matches a violation in the message of a protovalidate
+// ValidationError: " - spec.region: value is required [required]". The field path
+// is missing for message-level rules.
+var validationErrorLine = regexp.MustCompile(`^\s*-\s+(?:([\w.\[\]"]+): )?(.*?)(?: \[([\w.]+)\])?$`)
+
+// ruleSuffix matches the rule ID protovalidate appends to a violation message
+var ruleSuffix = regexp.MustCompile(`^(.*) \[([\w.]+)\]$`)
+
+// ProtovalidateViolations converts the Violations detail protovalidate attaches
+// to an INVALID_ARGUMENT status, or a local ValidationError converted with ToProto.
+func ProtovalidateViolations(detail *validate.Violations) []FieldViolation {
+	var violations []FieldViolation
+	for _, violation := range detail.GetViolations() {
+		violations = append(violations, FieldViolation{
+			FieldPath: fieldPathString(violation.GetField()),
+			Rule:      violation.GetRuleId(),
+			Message:   violation.GetMessage(),
+		})
+	}
+	return violations
+}
+
+// badRequestViolations converts a BadRequest detail. A rule ID appended to the
+// description in protovalidate style is split off into Rule.
+func badRequestViolations(detail *errdetails.BadRequest) []FieldViolation {
+	var violations []FieldViolation
+	for _, violation := range detail.GetFieldViolations() {
+		fieldViolation := FieldViolation{
+			FieldPath: violation.GetField(),
+			Message:   violation.GetDescription(),
+		}
+		if match := ruleSuffix.FindStringSubmatch(fieldViolation.Message); match != nil {
+			fieldViolation.Message, fieldViolation.Rule = match[1], match[2]
+		}
+		violations = append(violations, fieldViolation)
+	}
+	return violations
+}
+
+// messageViolations parses the violations out of a status message in the format
+// of a protovalidate ValidationError, for servers that attach no details:
+//
+//	validation error:
+//	 - spec.region: value is required [required]
+//	 - spec.cidr: value must be a valid CIDR [string.cidr]
+//
+// Returns nil if the message is not in that format.
+func messageViolations(message string) []FieldViolation {
+	header, body, ok := strings.Cut(message, "validation error:")
+	if !ok || strings.TrimSpace(header) != "" {
+		return nil
+	}
+
+	var violations []FieldViolation
+	for _, line := range strings.Split(body, "\n") {
+		match := validationErrorLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		violations = append(violations, FieldViolation{
+			FieldPath: match[1],
+			Rule:      match[3],
+			Message:   match[2],
+		})
+	}
+	return violations
+}
+
+// fieldPathString formats a protovalidate field path the way protovalidate does in
+// its error messages, e.g. `spec.subnets[0].tags["env"]`.
+func fieldPathString(path *validate.FieldPath) string {
+	var b strings.Builder
+	for i, element := range path.GetElements() {
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(element.GetFieldName())
+		switch subscript := element.GetSubscript().(type) {
+		case *validate.FieldPathElement_Index:
+			fmt.Fprintf(&b, "[%d]", subscript.Index)
+		case *validate.FieldPathElement_BoolKey:
+			fmt.Fprintf(&b, "[%t]", subscript.BoolKey)
+		case *validate.FieldPathElement_IntKey:
+			fmt.Fprintf(&b, "[%d]", subscript.IntKey)
+		case *validate.FieldPathElement_UintKey:
+			fmt.Fprintf(&b, "[%d]", subscript.UintKey)
+		case *validate.FieldPathElement_StringKey:
+			fmt.Fprintf(&b, "[%s]", strconv.Quote(subscript.StringKey))
+		}
+	}
+	return b.String()
+}
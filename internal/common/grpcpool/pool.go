@@ -0,0 +1,336 @@
+// Package grpcpool shares gRPC client connections to Planton APIs across all
+// domain clients.
+//
+// Dialing per tool call costs a fresh TCP and TLS handshake every time. A gRPC
+// connection multiplexes any number of concurrent RPCs, so the pool keeps one
+// long-lived connection per endpoint and every client borrows it. Credentials are
+// not part of the connection: each borrowed Conn attaches its caller's API key to
+// every RPC, so users sharing a connection never share credentials.
+package grpcpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	commonauth "github.com/plantoncloud/mcp-server-planton/internal/common/auth"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/metrics"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/retry"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/tracing"
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+)
+
+// maxEvictionInterval caps how often idle connections are looked for.
+const maxEvictionInterval = time.Minute
+
+// ErrPoolClosed is returned when a connection is requested after the pool was closed.
+var ErrPoolClosed = errors.New("gRPC connection pool is closed")
+
+// Options configures a connection pool.
+type Options struct {
+	// IdleTimeout is how long a connection with no borrowers stays open. Zero keeps
+	// connections open until the pool is closed.
+	IdleTimeout time.Duration
+	// KeepaliveTime is how often the connection is pinged when there is no other
+	// activity. Zero disables keepalive pings.
+	KeepaliveTime time.Duration
+	// KeepaliveTimeout is how long to wait for a ping acknowledgement before the
+	// connection is considered dead.
+	KeepaliveTimeout time.Duration
+	// Retry is the retry policy of unary query RPCs
+	Retry retry.Policy
+	// TransportSecurity selects TLS per endpoint; the zero value is auto mode
+	TransportSecurity TransportSecurity
+}
+
+// DefaultOptions returns the pool options used when none are configured.
+func DefaultOptions() Options {
+	return Options{
+		IdleTimeout:      config.DefaultGRPCIdleTimeout,
+		KeepaliveTime:    config.DefaultGRPCKeepaliveTime,
+		KeepaliveTimeout: config.DefaultGRPCKeepaliveTimeout,
+		Retry:            retry.DefaultPolicy(),
+	}
+}
+
+// OptionsFromConfig returns the pool options configured for the server.
+//
+// Returns an error if the configured TLS CA bundle or client certificate cannot be loaded.
+func OptionsFromConfig(cfg *config.Config) (Options, error) {
+	security, err := LoadTransportSecurity(cfg)
+	if err != nil {
+		return Options{}, err
+	}
+	return Options{
+		IdleTimeout:       cfg.GRPCIdleTimeout,
+		KeepaliveTime:     cfg.GRPCKeepaliveTime,
+		KeepaliveTimeout:  cfg.GRPCKeepaliveTimeout,
+		Retry:             retry.PolicyFromConfig(cfg),
+		TransportSecurity: security,
+	}, nil
+}
+
+// pooledConn is a shared connection and the number of Conns borrowing it.
+type pooledConn struct {
+	conn         *grpc.ClientConn
+	borrowers    int
+	lastReleased time.Time
+}
+
+// Pool holds one gRPC client connection per endpoint.
+type Pool struct {
+	opts Options
+
+	// transportCredentials selects the transport security for an endpoint
+	transportCredentials func(endpoint string) credentials.TransportCredentials
+
+	mu     sync.Mutex
+	conns  map[string]*pooledConn
+	closed bool
+
+	stopEviction context.CancelFunc
+}
+
+// New creates a connection pool. Connections are opened on first use.
+//
+// Args:
+//   - opts: Idle eviction, keepalive, retry and TLS settings
+//
+// Returns the pool. Close it to release its connections.
+func New(opts Options) *Pool {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool{
+		opts:                 opts,
+		transportCredentials: opts.TransportSecurity.Credentials,
+		conns:                make(map[string]*pooledConn),
+		stopEviction:         cancel,
+	}
+	if opts.IdleTimeout > 0 {
+		go p.evictIdle(ctx)
+	}
+	return p
+}
+
+// Get borrows the connection to an endpoint, opening it if needed.
+//
+// Args:
+//   - endpoint: Planton Cloud APIs endpoint (e.g., "localhost:8080" or "api.live.planton.cloud:443")
+//   - apiKey: API key attached to every RPC made through the returned Conn
+//
+// Returns a Conn, which must be closed to give the connection back to the pool.
+func (p *Pool) Get(endpoint, apiKey string) (*Conn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, ErrPoolClosed
+	}
+
+	pc, ok := p.conns[endpoint]
+	if !ok {
+		// grpc.NewClient does not connect; the first RPC does
+		conn, err := grpc.NewClient(endpoint, p.dialOptions(endpoint)...)
+		if err != nil {
+			return nil, err
+		}
+		pc = &pooledConn{conn: conn}
+		p.conns[endpoint] = pc
+		slog.Debug("Opened pooled gRPC connection", "endpoint", endpoint)
+	}
+	pc.borrowers++
+
+	return &Conn{
+		cc:    pc.conn,
+		creds: commonauth.NewTokenAuth(apiKey),
+		pool:  p,
+		entry: pc,
+	}, nil
+}
+
+// Close closes every pooled connection. Conns still borrowed fail their next RPC.
+func (p *Pool) Close() error {
+	p.stopEviction()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	var errs []error
+	for endpoint, pc := range p.conns {
+		if err := pc.conn.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close connection to %s: %w", endpoint, err))
+		}
+		delete(p.conns, endpoint)
+	}
+	return errors.Join(errs...)
+}
+
+// release gives a borrowed connection back.
+func (p *Pool) release(pc *pooledConn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pc.borrowers--
+	pc.lastReleased = time.Now()
+}
+
+// dialOptions returns the options for a new pooled connection. They carry no
+// credentials; those are attached per RPC by Conn.
+func (p *Pool) dialOptions(endpoint string) []grpc.DialOption {
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(p.transportCredentials(endpoint)),
+		// Retries wrap metrics so every attempt is recorded
+		grpc.WithChainUnaryInterceptor(retry.UnaryClientInterceptor(p.opts.Retry), metrics.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(metrics.StreamClientInterceptor()),
+		tracing.GRPCDialOption(),
+	}
+	if p.opts.KeepaliveTime > 0 {
+		// Keepalive detects dead connections, including during long-running
+		// streaming operations such as get_pipeline_build_logs
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                p.opts.KeepaliveTime,
+			Timeout:             p.opts.KeepaliveTimeout,
+			PermitWithoutStream: true,
+		}))
+	}
+	return opts
+}
+
+// evictIdle periodically closes connections that have had no borrowers for
+// IdleTimeout, until ctx is cancelled.
+func (p *Pool) evictIdle(ctx context.Context) {
+	ticker := time.NewTicker(min(p.opts.IdleTimeout, maxEvictionInterval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		p.mu.Lock()
+		for endpoint, pc := range p.conns {
+			if pc.borrowers > 0 || time.Since(pc.lastReleased) < p.opts.IdleTimeout {
+				continue
+			}
+			if err := pc.conn.Close(); err != nil {
+				slog.Debug("Error closing idle gRPC connection", "endpoint", endpoint, "error", err)
+			}
+			delete(p.conns, endpoint)
+			slog.Debug("Closed idle pooled gRPC connection", "endpoint", endpoint)
+		}
+		p.mu.Unlock()
+	}
+}
+
+// Conn is a borrowed pooled connection that attaches its caller's API key to every
+// RPC. It implements grpc.ClientConnInterface, so generated gRPC clients can be
+// created from it directly.
+type Conn struct {
+	cc    *grpc.ClientConn
+	creds credentials.PerRPCCredentials
+	pool  *Pool
+	entry *pooledConn
+	once  sync.Once
+}
+
+// Invoke performs a unary RPC with the caller's credentials.
+func (c *Conn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	return c.cc.Invoke(ctx, method, args, reply, c.withCredentials(opts)...)
+}
+
+// NewStream begins a streaming RPC with the caller's credentials.
+func (c *Conn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return c.cc.NewStream(ctx, desc, method, c.withCredentials(opts)...)
+}
+
+// withCredentials appends the caller's per-RPC credentials without modifying opts.
+func (c *Conn) withCredentials(opts []grpc.CallOption) []grpc.CallOption {
+	return append(opts[:len(opts):len(opts)], grpc.PerRPCCredentials(c.creds))
+}
+
+// Close gives the connection back to the pool. The shared connection stays open for
+// other callers; it is closed by idle eviction or when the pool is closed. Calling
+// Close more than once has no further effect.
+func (c *Conn) Close() error {
+	c.once.Do(func() {
+		c.pool.release(c.entry)
+	})
+	return nil
+}
+
+var (
+	defaultMu   sync.Mutex
+	defaultPool *Pool
+)
+
+// Setup replaces the process-wide pool with one configured from cfg. Call it once at
+// startup, before any client is created.
+//
+// Returns an error if the configured TLS CA bundle or client certificate cannot be
+// loaded; the process-wide pool is left unchanged then.
+func Setup(cfg *config.Config) error {
+	opts, err := OptionsFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	defaultMu.Lock()
+	old := defaultPool
+	defaultPool = New(opts)
+	defaultMu.Unlock()
+
+	if old != nil {
+		_ = old.Close()
+	}
+	return nil
+}
+
+// Default returns the process-wide pool, creating it with DefaultOptions if Setup was
+// not called.
+func Default() *Pool {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	if defaultPool == nil {
+		defaultPool = New(DefaultOptions())
+	}
+	return defaultPool
+}
+
+// Dial borrows the process-wide pool's connection to an endpoint for a caller.
+//
+// Args:
+//   - endpoint: Planton Cloud APIs endpoint
+//   - apiKey: User's API key (can be JWT token or API key), attached to every RPC
+//
+// Returns a Conn, which must be closed to give the connection back.
+func Dial(endpoint, apiKey string) (*Conn, error) {
+	return Default().Get(endpoint, apiKey)
+}
+
+// TransportCredentials returns the transport credentials the process-wide pool uses
+// for an endpoint, for connections that cannot be pooled such as readiness probes.
+func TransportCredentials(endpoint string) credentials.TransportCredentials {
+	return Default().transportCredentials(endpoint)
+}
+
+// Close closes the process-wide pool's connections.
+func Close() error {
+	defaultMu.Lock()
+	p := defaultPool
+	defaultPool = nil
+	defaultMu.Unlock()
+
+	if p == nil {
+		return nil
+	}
+	return p.Close()
+}
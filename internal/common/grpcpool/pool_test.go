@@ -0,0 +1,183 @@
+package grpcpool
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+// startTLSServer starts a gRPC health server on a loopback port with a self-signed
+// certificate, so benchmarks include the TLS handshake a production endpoint costs.
+//
+// Returns the server address and client transport credentials that trust it.
+// Authorization headers received by the server are sent to authHeaders, if non-nil.
+func startTLSServer(tb testing.TB, authHeaders chan<- string) (string, credentials.TransportCredentials) {
+	tb.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	serverCreds := credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	})
+	server := grpc.NewServer(
+		grpc.Creds(serverCreds),
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if authHeaders != nil {
+				md, _ := metadata.FromIncomingContext(ctx)
+				authHeaders <- md.Get("authorization")[0]
+			}
+			return handler(ctx, req)
+		}),
+	)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() { _ = server.Serve(listener) }()
+	tb.Cleanup(server.Stop)
+
+	return listener.Addr().String(), credentials.NewTLS(&tls.Config{RootCAs: roots})
+}
+
+// newTestPool returns a pool that connects with the given transport credentials.
+func newTestPool(tb testing.TB, creds credentials.TransportCredentials) *Pool {
+	tb.Helper()
+	p := New(DefaultOptions())
+	p.transportCredentials = func(string) credentials.TransportCredentials { return creds }
+	tb.Cleanup(func() { _ = p.Close() })
+	return p
+}
+
+func TestConnsShareConnectionButNotCredentials(t *testing.T) {
+	authHeaders := make(chan string, 2)
+	endpoint, creds := startTLSServer(t, authHeaders)
+	p := newTestPool(t, creds)
+
+	alice, err := p.Get(endpoint, "key-alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer alice.Close()
+	bob, err := p.Get(endpoint, "key-bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bob.Close()
+
+	if alice.cc != bob.cc {
+		t.Fatal("expected both callers to share one connection")
+	}
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		conn *Conn
+		want string
+	}{
+		{alice, "Bearer key-alice"},
+		{bob, "Bearer key-bob"},
+	} {
+		if _, err := healthpb.NewHealthClient(tc.conn).Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+			t.Fatal(err)
+		}
+		if got := <-authHeaders; got != tc.want {
+			t.Errorf("authorization = %q, want %q", got, tc.want)
+		}
+	}
+}
+
+// BenchmarkDialPerCall measures the previous behavior: every tool call dials a new
+// connection, makes one RPC and closes the connection again.
+func BenchmarkDialPerCall(b *testing.B) {
+	endpoint, creds := startTLSServer(b, nil)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		conn, err := grpc.NewClient(endpoint, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+			b.Fatal(err)
+		}
+		_ = conn.Close()
+	}
+}
+
+// BenchmarkPooledConn measures a tool call borrowing the pooled connection.
+func BenchmarkPooledConn(b *testing.B) {
+	endpoint, creds := startTLSServer(b, nil)
+	p := newTestPool(b, creds)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		conn, err := p.Get(endpoint, "key")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+			b.Fatal(err)
+		}
+		_ = conn.Close()
+	}
+}
+
+// BenchmarkPooledConnParallel measures concurrent tool calls multiplexed over the
+// pooled connection.
+func BenchmarkPooledConnParallel(b *testing.B) {
+	endpoint, creds := startTLSServer(b, nil)
+	p := newTestPool(b, creds)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			conn, err := p.Get(endpoint, "key")
+			if err != nil {
+				b.Error(err)
+				return
+			}
+			if _, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+				b.Error(err)
+			}
+			_ = conn.Close()
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package grpcpool
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// TransportSecurity selects the transport credentials of connections to Planton
+// APIs. The zero value is auto mode with the system roots: TLS for endpoints on port
+// 443, plaintext otherwise (local development).
+type TransportSecurity struct {
+	mode config.GRPCTLSMode
+	// tlsConfig carries the configured CA bundle, client certificate and server
+	// name; nil when none is configured
+	tlsConfig *tls.Config
+}
+
+// LoadTransportSecurity reads the CA bundle and client certificate configured for
+// Planton APIs connections.
+//
+// Args:
+//   - cfg: Server configuration with the PLANTON_MCP_GRPC_TLS* settings
+//
+// Returns the transport security, or an error if a file cannot be read or parsed.
+func LoadTransportSecurity(cfg *config.Config) (TransportSecurity, error) {
+	security := TransportSecurity{mode: cfg.GRPCTLSMode}
+	if cfg.GRPCTLSCAFile == "" && cfg.GRPCTLSCertFile == "" && cfg.GRPCTLSServerName == "" {
+		return security, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: cfg.GRPCTLSServerName,
+	}
+
+	if cfg.GRPCTLSCAFile != "" {
+		pem, err := os.ReadFile(cfg.GRPCTLSCAFile)
+		if err != nil {
+			return TransportSecurity{}, fmt.Errorf("read %s: %w", config.GRPCTLSCAFileEnvVar, err)
+		}
+		// The bundle adds to the system roots, so public endpoints keep working
+		// behind a TLS-intercepting proxy
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(pem) {
+			return TransportSecurity{}, fmt.Errorf("read %s: no PEM certificates found in %s",
+				config.GRPCTLSCAFileEnvVar, cfg.GRPCTLSCAFile)
+		}
+		tlsConfig.RootCAs = roots
+	}
+
+	if cfg.GRPCTLSCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.GRPCTLSCertFile, cfg.GRPCTLSKeyFile)
+		if err != nil {
+			return TransportSecurity{}, fmt.Errorf("load TLS client certificate from %s and %s: %w",
+				config.GRPCTLSCertFileEnvVar, config.GRPCTLSKeyFileEnvVar, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	security.tlsConfig = tlsConfig
+	return security, nil
+}
+
+// UsesTLS reports whether connections to an endpoint use TLS.
+func (s TransportSecurity) UsesTLS(endpoint string) bool {
+	switch s.mode {
+	case config.GRPCTLSOn:
+		return true
+	case config.GRPCTLSOff:
+		return false
+	default:
+		return s.tlsConfig != nil || strings.HasSuffix(endpoint, ":443")
+	}
+}
+
+// Credentials returns the transport credentials of connections to an endpoint.
+func (s TransportSecurity) Credentials(endpoint string) credentials.TransportCredentials {
+	if !s.UsesTLS(endpoint) {
+		return insecure.NewCredentials()
+	}
+	return credentials.NewTLS(s.tlsConfig)
+}
+
+// TLSConfig returns the TLS configuration of connections to an endpoint, for
+// handshakes outside gRPC such as the doctor command's TLS check. Like the gRPC
+// transport credentials, it verifies the endpoint host unless a server name is
+// configured, and offers only HTTP/2.
+func (s TransportSecurity) TLSConfig(endpoint string) *tls.Config {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if s.tlsConfig != nil {
+		tlsConfig = s.tlsConfig.Clone()
+	}
+	if tlsConfig.ServerName == "" {
+		host, _, err := net.SplitHostPort(endpoint)
+		if err != nil {
+			host = endpoint
+		}
+		tlsConfig.ServerName = host
+	}
+	tlsConfig.NextProtos = []string{"h2"}
+	return tlsConfig
+}
@@ -0,0 +1,207 @@
+package grpcpool
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// testCA issues certificates for TLS tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, der: der}
+}
+
+// issue returns a certificate for dnsName signed by the CA.
+func (ca *testCA) issue(t *testing.T, dnsName string, usage x509.ExtKeyUsage) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: dnsName},
+		DNSNames:     []string{dnsName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// writePEM writes PEM blocks to a file in a temporary directory and returns its path.
+func writePEM(t *testing.T, name string, blocks ...*pem.Block) string {
+	t.Helper()
+	var data []byte
+	for _, block := range blocks {
+		data = append(data, pem.EncodeToMemory(block)...)
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// startMutualTLSServer starts a gRPC health server on a loopback port that presents a
+// certificate for serverName and requires a client certificate issued by ca.
+func startMutualTLSServer(t *testing.T, ca *testCA, serverName string) string {
+	t.Helper()
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{ca.issue(t, serverName, x509.ExtKeyUsageServerAuth)},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	})))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func TestTransportSecurityUsesTLS(t *testing.T) {
+	configured := TransportSecurity{mode: config.GRPCTLSAuto, tlsConfig: &tls.Config{}}
+
+	tests := []struct {
+		name     string
+		security TransportSecurity
+		endpoint string
+		want     bool
+	}{
+		{name: "auto on port 443", security: TransportSecurity{}, endpoint: "api.live.planton.ai:443", want: true},
+		{name: "auto on another port", security: TransportSecurity{}, endpoint: "localhost:8080", want: false},
+		{name: "auto with TLS options", security: configured, endpoint: "planton.internal:8443", want: true},
+		{name: "on", security: TransportSecurity{mode: config.GRPCTLSOn}, endpoint: "planton.internal:8443", want: true},
+		{name: "off", security: TransportSecurity{mode: config.GRPCTLSOff}, endpoint: "api.live.planton.ai:443", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.security.UsesTLS(tt.endpoint); got != tt.want {
+				t.Errorf("UsesTLS(%q) = %v, want %v", tt.endpoint, got, tt.want)
+			}
+			wantProtocol := "insecure"
+			if tt.want {
+				wantProtocol = "tls"
+			}
+			if got := tt.security.Credentials(tt.endpoint).Info().SecurityProtocol; got != wantProtocol {
+				t.Errorf("Credentials(%q) protocol = %q, want %q", tt.endpoint, got, wantProtocol)
+			}
+		})
+	}
+}
+
+func TestMutualTLSWithCustomCA(t *testing.T) {
+	ca := newTestCA(t)
+	endpoint := startMutualTLSServer(t, ca, "planton.internal")
+
+	client := ca.issue(t, "mcp-server", x509.ExtKeyUsageClientAuth)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(client.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{
+		GRPCTLSMode:       config.GRPCTLSAuto,
+		GRPCTLSCAFile:     writePEM(t, "ca.pem", &pem.Block{Type: "CERTIFICATE", Bytes: ca.der}),
+		GRPCTLSCertFile:   writePEM(t, "client.pem", &pem.Block{Type: "CERTIFICATE", Bytes: client.Certificate[0]}),
+		GRPCTLSKeyFile:    writePEM(t, "client-key.pem", &pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+		GRPCTLSServerName: "planton.internal",
+	}
+
+	opts, err := OptionsFromConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := New(opts)
+	defer p.Close()
+
+	conn, err := p.Get(endpoint, "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("health check over mutual TLS failed: %v", err)
+	}
+}
+
+func TestLoadTransportSecurityErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *config.Config
+	}{
+		{name: "missing CA file", cfg: &config.Config{GRPCTLSCAFile: filepath.Join(t.TempDir(), "missing.pem")}},
+		{name: "CA file without certificates", cfg: &config.Config{GRPCTLSCAFile: writePEM(t, "empty.pem")}},
+		{name: "missing client certificate", cfg: &config.Config{
+			GRPCTLSCertFile: filepath.Join(t.TempDir(), "client.pem"),
+			GRPCTLSKeyFile:  filepath.Join(t.TempDir(), "client-key.pem"),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadTransportSecurity(tt.cfg); err == nil {
+				t.Error("LoadTransportSecurity() succeeded, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Attribute keys for the correlation IDs added to every log line.
+const (
+	RequestIDKey  = "request_id"
+	SessionIDKey  = "session_id"
+	ToolKey       = "tool"
+	ToolCallIDKey = "tool_call_id"
+)
+
+// contextKey is the type for logging values stored in a context.
+type contextKey int
+
+const (
+	requestIDContextKey contextKey = iota
+	sessionIDContextKey
+	toolContextKey
+	toolCallIDContextKey
+)
+
+// WithRequestID returns a context carrying the ID of the HTTP request being served.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, requestID)
+}
+
+// WithSessionID returns a context carrying the MCP session ID. It is only needed
+// before mcp-go has attached the client session to the context (e.g. in HTTP
+// middleware); afterwards the session ID is read from the client session.
+func WithSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionIDContextKey, sessionID)
+}
+
+// WithToolCall returns a context carrying the tool name and the ID of one tool invocation.
+func WithToolCall(ctx context.Context, tool, toolCallID string) context.Context {
+	ctx = context.WithValue(ctx, toolContextKey, tool)
+	return context.WithValue(ctx, toolCallIDContextKey, toolCallID)
+}
+
+// NewID returns a random 16-character hex ID for correlating log lines.
+func NewID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// contextAttrs returns the correlation IDs found in ctx as log attributes.
+func contextAttrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+
+	var attrs []slog.Attr
+	if id, ok := ctx.Value(requestIDContextKey).(string); ok && id != "" {
+		attrs = append(attrs, slog.String(RequestIDKey, id))
+	}
+	if id := sessionID(ctx); id != "" {
+		attrs = append(attrs, slog.String(SessionIDKey, id))
+	}
+	if tool, ok := ctx.Value(toolContextKey).(string); ok && tool != "" {
+		attrs = append(attrs, slog.String(ToolKey, tool))
+	}
+	if id, ok := ctx.Value(toolCallIDContextKey).(string); ok && id != "" {
+		attrs = append(attrs, slog.String(ToolCallIDKey, id))
+	}
+	return attrs
+}
+
+// sessionID returns the MCP session ID from the client session mcp-go attached to
+// ctx, falling back to one stored with WithSessionID.
+func sessionID(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		if id := session.SessionID(); id != "" {
+			return id
+		}
+	}
+	id, _ := ctx.Value(sessionIDContextKey).(string)
+	return id
+}
+
+// contextHandler adds the correlation IDs found in the record's context to every line.
+type contextHandler struct {
+	next slog.Handler
+}
+
+// Enabled reports whether the wrapped handler handles records at the given level.
+func (h *contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle adds the context's correlation IDs to the record and passes it on.
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs := contextAttrs(ctx); len(attrs) > 0 {
+		record = record.Clone()
+		record.AddAttrs(attrs...)
+	}
+	return h.next.Handle(ctx, record)
+}
+
+// WithAttrs returns a handler whose lines include the given attributes.
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{next: h.next.WithAttrs(attrs)}
+}
+
+// WithGroup returns a handler that nests the record's attributes under name.
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{next: h.next.WithGroup(name)}
+}
@@ -0,0 +1,77 @@
+// Package logging configures structured, leveled logging for the MCP server.
+//
+// The server logs through log/slog. Setup installs a default logger that:
+//   - writes to stderr only, because stdout carries the MCP protocol in STDIO mode
+//   - filters by the configured level and writes text or JSON
+//   - adds request, session and tool call IDs found in the context to every line
+//   - redacts bearer tokens, GitHub tokens, JWTs and secret-looking fields
+//
+// Use the context-aware slog functions (slog.InfoContext, slog.DebugContext, ...)
+// in request paths so lines carry the correlation IDs.
+package logging
+
+import (
+	"io"
+	"log"
+	"log/slog"
+	"os"
+
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+)
+
+// Setup installs the default slog logger described by the configuration and routes
+// the standard log package (used by dependencies) through it.
+//
+// Args:
+//   - cfg: Server configuration with the log level and format
+//
+// Returns the installed logger.
+func Setup(cfg *config.Config) *slog.Logger {
+	logger := slog.New(NewHandler(os.Stderr, cfg.LogLevel, cfg.LogFormat))
+
+	// slog.SetDefault also redirects the standard log package to this logger,
+	// so log.Printf calls in dependencies are formatted and redacted the same way.
+	// Lshortfile must be set first for those lines to carry their source location.
+	log.SetFlags(log.Lshortfile)
+	slog.SetDefault(logger)
+
+	return logger
+}
+
+// NewHandler creates the slog handler used by the server.
+//
+// Args:
+//   - w: Destination for log lines (os.Stderr in the server; never os.Stdout)
+//   - level: Minimum level (debug, info, warn, error); unknown values mean info
+//   - format: Output format (text or json)
+//
+// Returns a handler that adds context IDs and redacts secrets before writing.
+func NewHandler(w io.Writer, level string, format config.LogFormat) slog.Handler {
+	opts := &slog.HandlerOptions{
+		Level:     parseLevel(level),
+		AddSource: true,
+	}
+
+	var base slog.Handler
+	if format == config.LogFormatJSON {
+		base = slog.NewJSONHandler(w, opts)
+	} else {
+		base = slog.NewTextHandler(w, opts)
+	}
+
+	return &redactingHandler{next: &contextHandler{next: base}}
+}
+
+// parseLevel converts a configured level name to a slog level.
+func parseLevel(level string) slog.Level {
+	switch level {
+	case config.LogLevelDebug:
+		return slog.LevelDebug
+	case config.LogLevelWarn:
+		return slog.LevelWarn
+	case config.LogLevelError:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
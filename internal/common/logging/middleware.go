@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// requestIDHeader is the header carrying a caller-provided request ID. When present
+// it is reused so log lines can be matched with the caller's own logs.
+const requestIDHeader = "X-Request-Id"
+
+// maxRequestIDLength caps caller-provided request IDs.
+const maxRequestIDLength = 128
+
+// HTTPMiddleware assigns every HTTP request a request ID, records the MCP session ID
+// sent by the client, and logs the request at debug level.
+//
+// The request ID is taken from the X-Request-Id header when present, generated
+// otherwise, and echoed back in the response header.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(requestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = NewID()
+		}
+		w.Header().Set(requestIDHeader, requestID)
+
+		ctx := WithRequestID(r.Context(), requestID)
+
+		// Streamable HTTP sends the session in a header, SSE in the sessionId query parameter
+		sessionID := r.Header.Get(server.HeaderKeySessionID)
+		if sessionID == "" {
+			sessionID = r.URL.Query().Get("sessionId")
+		}
+		if sessionID != "" {
+			ctx = WithSessionID(ctx, sessionID)
+		}
+
+		slog.DebugContext(ctx, "HTTP request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"remote_addr", r.RemoteAddr,
+		)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ToolHandlerMiddleware assigns every tool invocation a tool call ID, adds the tool
+// name and call ID to the context so the handler's log lines carry them, and logs
+// the outcome and duration of the call.
+func ToolHandlerMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = WithToolCall(ctx, request.Params.Name, NewID())
+
+		slog.DebugContext(ctx, "Tool call started")
+		start := time.Now()
+
+		result, err := next(ctx, request)
+
+		duration := time.Since(start)
+		switch {
+		case err != nil:
+			slog.ErrorContext(ctx, "Tool call failed", "duration", duration, "error", err)
+		case result != nil && result.IsError:
+			slog.WarnContext(ctx, "Tool call returned an error result", "duration", duration)
+		default:
+			slog.InfoContext(ctx, "Tool call completed", "duration", duration)
+		}
+		return result, err
+	}
+}
@@ -0,0 +1,142 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+)
+
+// redacted replaces secret values in log lines.
+const redacted = "[REDACTED]"
+
+var (
+	// bearerTokenPattern matches bearer tokens, e.g. in Authorization header values.
+	// The minimum length keeps prose such as "bearer token authentication" intact.
+	bearerTokenPattern = regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]{16,}=*`)
+
+	// githubTokenPattern matches GitHub tokens, including the installation tokens
+	// returned by get_github_installation_token (ghs_...).
+	githubTokenPattern = regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{16,}|github_pat_[A-Za-z0-9_]{20,})`)
+
+	// jwtPattern matches JSON Web Tokens, which Planton API keys can be.
+	jwtPattern = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+
+	// secretFieldPattern matches secret-looking key/value pairs inside text, such as
+	// fields of a JSON or YAML resource spec ("password": "x", client_secret: x).
+	secretFieldPattern = regexp.MustCompile(`(?i)("?[A-Za-z0-9_.-]*(?:password|passwd|secret|token|api_?key|private_?key|access_?key)[A-Za-z0-9_.-]*"?\s*[:=]\s*"?)([^"\s,}]+)`)
+
+	// secretKeyPattern matches attribute and map keys whose values are secret.
+	secretKeyPattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|api_?key|private_?key|access_?key|authorization|credentials$)`)
+)
+
+// RedactString removes bearer tokens, GitHub tokens, JWTs and secret-looking
+// key/value pairs from s.
+func RedactString(s string) string {
+	s = bearerTokenPattern.ReplaceAllString(s, "Bearer "+redacted)
+	s = githubTokenPattern.ReplaceAllString(s, redacted)
+	s = jwtPattern.ReplaceAllString(s, redacted)
+	s = secretFieldPattern.ReplaceAllString(s, "${1}"+redacted)
+	return s
+}
+
+// isSecretKey reports whether values stored under key must never be logged.
+func isSecretKey(key string) bool {
+	return secretKeyPattern.MatchString(key)
+}
+
+// redactAttr returns a copy of attr with secret values redacted.
+func redactAttr(attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+	if isSecretKey(attr.Key) {
+		return slog.String(attr.Key, redacted)
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, RedactString(attr.Value.String()))
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, a := range group {
+			attrs[i] = redactAttr(a)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(attrs...)}
+	case slog.KindAny:
+		switch v := attr.Value.Any().(type) {
+		case error:
+			return slog.String(attr.Key, RedactString(v.Error()))
+		case map[string]interface{}:
+			return slog.Any(attr.Key, redactValue(v))
+		case []interface{}:
+			return slog.Any(attr.Key, redactValue(v))
+		case []string:
+			values := make([]string, len(v))
+			for i, s := range v {
+				values[i] = RedactString(s)
+			}
+			return slog.Any(attr.Key, values)
+		}
+	}
+	return attr
+}
+
+// redactValue returns a copy of a decoded JSON/YAML value (such as a resource spec)
+// with the values of secret-looking fields redacted.
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if isSecretKey(key) {
+				out[key] = redacted
+				continue
+			}
+			out[key] = redactValue(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = redactValue(item)
+		}
+		return out
+	case string:
+		return RedactString(v)
+	default:
+		return v
+	}
+}
+
+// redactingHandler redacts secrets from the message and attributes of every record.
+type redactingHandler struct {
+	next slog.Handler
+}
+
+// Enabled reports whether the wrapped handler handles records at the given level.
+func (h *redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle redacts the record and passes it on.
+func (h *redactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	clean := slog.NewRecord(record.Time, record.Level, RedactString(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		clean.AddAttrs(redactAttr(attr))
+		return true
+	})
+	return h.next.Handle(ctx, clean)
+}
+
+// WithAttrs returns a handler whose lines include the given attributes, redacted.
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clean := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		clean[i] = redactAttr(attr)
+	}
+	return &redactingHandler{next: h.next.WithAttrs(clean)}
+}
+
+// WithGroup returns a handler that nests the record's attributes under name.
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	return &redactingHandler{next: h.next.WithGroup(name)}
+}
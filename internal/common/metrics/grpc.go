@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryClientInterceptor returns a gRPC client interceptor that records unary calls
+// in GRPCClientCallsTotal and GRPCClientCallDuration.
+//
+// The shared gRPC connection pool (internal/common/grpcpool) installs it on every connection.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		observeGRPCCall(method, err, time.Since(start))
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a gRPC client interceptor that records streaming
+// calls once the stream has finished, whether it ran to the end, failed, or was
+// abandoned by cancelling its context.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		opts = append(opts, grpc.OnFinish(func(err error) {
+			observeGRPCCall(method, err, time.Since(start))
+		}))
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+// observeGRPCCall records a finished gRPC call.
+//
+// Args:
+//   - fullMethod: Full gRPC method name (e.g., "/ai.planton.servicehub.pipeline.v1.PipelineQueryController/getById")
+//   - err: Final error of the call, nil on success
+//   - duration: Time from the start of the call until it finished
+func observeGRPCCall(fullMethod string, err error, duration time.Duration) {
+	service, method := splitMethodName(fullMethod)
+	GRPCClientCallsTotal.WithLabelValues(service, method, status.Code(err).String()).Inc()
+	GRPCClientCallDuration.WithLabelValues(service, method).Observe(duration.Seconds())
+}
+
+// ObserveGRPCRetry records that a failed gRPC call is retried.
+//
+// Args:
+//   - fullMethod: Full gRPC method name
+//   - err: Error of the attempt being retried
+func ObserveGRPCRetry(fullMethod string, err error) {
+	service, method := splitMethodName(fullMethod)
+	GRPCClientRetriesTotal.WithLabelValues(service, method, status.Code(err).String()).Inc()
+}
+
+// splitMethodName splits a full gRPC method name ("/package.Service/Method")
+// into its service and method parts.
+func splitMethodName(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "unknown", fullMethod
+}
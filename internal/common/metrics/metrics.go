@@ -0,0 +1,165 @@
+// Package metrics defines the Prometheus metrics exported by the MCP server.
+//
+// All metrics are registered with the default Prometheus registry and served by
+// the HTTP transport at /metrics, alongside the Go runtime and process metrics
+// that the default registry already collects.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes every metric exported by this server.
+const namespace = "planton_mcp"
+
+// Tool call outcomes used as the "outcome" label value.
+const (
+	// OutcomeSuccess means the tool returned a regular result
+	OutcomeSuccess = "success"
+
+	// OutcomeToolError means the tool returned a result flagged with IsError
+	OutcomeToolError = "tool_error"
+
+	// OutcomeError means the tool handler returned a Go error
+	OutcomeError = "error"
+)
+
+// Limits that can reject a request, used as the "limit" label value of RateLimitedTotal.
+const (
+	// LimitRequestRate means the API key exceeded its HTTP request rate
+	LimitRequestRate = "request_rate"
+
+	// LimitToolCalls means the API key had too many tool calls running
+	LimitToolCalls = "concurrent_tool_calls"
+
+	// LimitStreamingCalls means the API key had too many streaming tool calls running
+	LimitStreamingCalls = "concurrent_streaming_calls"
+)
+
+// Outcomes of bearer token pre-validation, used as the "result" label value of
+// TokenValidationsTotal.
+const (
+	// TokenValid means Planton APIs accepted the token
+	TokenValid = "valid"
+
+	// TokenRejected means Planton APIs rejected the token as unauthenticated
+	TokenRejected = "rejected"
+
+	// TokenUnverified means the token could not be checked, e.g. Planton APIs were unreachable
+	TokenUnverified = "unverified"
+)
+
+var (
+	// ToolCallsTotal counts tool invocations by tool name and outcome.
+	ToolCallsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tool_calls_total",
+		Help:      "Total number of MCP tool calls by tool name and outcome.",
+	}, []string{"tool", "outcome"})
+
+	// ToolCallDuration observes tool handler latency by tool name.
+	ToolCallDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "tool_call_duration_seconds",
+		Help:      "MCP tool handler latency in seconds.",
+		// Most tools are a single gRPC round trip; get_pipeline_build_logs
+		// streams for up to 45 seconds.
+		Buckets: []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 25, 45, 60},
+	}, []string{"tool"})
+
+	// GRPCClientCallsTotal counts gRPC calls to Planton APIs by service, method and status code.
+	GRPCClientCallsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_client_calls_total",
+		Help:      "Total number of gRPC calls to Planton APIs by service, method and status code.",
+	}, []string{"service", "method", "code"})
+
+	// GRPCClientCallDuration observes gRPC call latency by service and method.
+	// For streaming calls it covers the whole stream.
+	GRPCClientCallDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_client_call_duration_seconds",
+		Help:      "Latency of gRPC calls to Planton APIs in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "method"})
+
+	// GRPCClientRetriesTotal counts retries of failed gRPC query calls to Planton APIs,
+	// by service, method and the status code of the attempt that was retried.
+	GRPCClientRetriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_client_retries_total",
+		Help:      "Total number of retried gRPC query calls to Planton APIs by service, method and status code of the failed attempt.",
+	}, []string{"service", "method", "code"})
+
+	// SSESessionsActive tracks the number of open SSE streams.
+	SSESessionsActive = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "sse_sessions_active",
+		Help:      "Number of currently open SSE sessions.",
+	})
+
+	// PipelineLogEntriesDelivered counts log entries returned by get_pipeline_build_logs.
+	PipelineLogEntriesDelivered = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pipeline_log_entries_delivered_total",
+		Help:      "Total number of pipeline log entries delivered by get_pipeline_build_logs.",
+	})
+
+	// RateLimitedTotal counts requests and tool calls rejected by the per-API-key limits.
+	RateLimitedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_total",
+		Help:      "Total number of HTTP requests and tool calls rejected by per-API-key limits, by limit.",
+	}, []string{"limit"})
+
+	// TokenValidationsTotal counts bearer token pre-validations, by result and by
+	// whether the result came from the token cache.
+	TokenValidationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "token_validations_total",
+		Help:      "Total number of bearer token pre-validations, by result and cache hit.",
+	}, []string{"result", "cached"})
+)
+
+// Handler returns the HTTP handler serving metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ToolHandlerMiddleware records the call count, outcome and latency of every tool call.
+func ToolHandlerMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := next(ctx, request)
+
+		outcome := OutcomeSuccess
+		switch {
+		case err != nil:
+			outcome = OutcomeError
+		case result != nil && result.IsError:
+			outcome = OutcomeToolError
+		}
+
+		ToolCallsTotal.WithLabelValues(request.Params.Name, outcome).Inc()
+		ToolCallDuration.WithLabelValues(request.Params.Name).Observe(time.Since(start).Seconds())
+		return result, err
+	}
+}
+
+// TrackSSESession wraps an SSE stream handler so SSESessionsActive counts the
+// streams that are currently open.
+func TrackSSESession(next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		SSESessionsActive.Inc()
+		defer SSESessionsActive.Dec()
+		next.ServeHTTP(w, r)
+	}
+}
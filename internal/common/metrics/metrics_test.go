@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestToolHandlerMiddleware(t *testing.T) {
+	ToolCallsTotal.Reset()
+
+	results := map[string]func() (*mcp.CallToolResult, error){
+		"list_organizations": func() (*mcp.CallToolResult, error) { return mcp.NewToolResultText("[]"), nil },
+		"get_cloud_resource": func() (*mcp.CallToolResult, error) { return mcp.NewToolResultError("not found"), nil },
+		"get_environment":    func() (*mcp.CallToolResult, error) { return nil, errors.New("boom") },
+	}
+	for name, result := range results {
+		handler := ToolHandlerMiddleware(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return result()
+		})
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		handler(context.Background(), request)
+	}
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "list_organizations"
+	ToolHandlerMiddleware(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("[]"), nil
+	})(context.Background(), request)
+
+	want := `
+# HELP planton_mcp_tool_calls_total Total number of MCP tool calls by tool name and outcome.
+# TYPE planton_mcp_tool_calls_total counter
+planton_mcp_tool_calls_total{outcome="error",tool="get_environment"} 1
+planton_mcp_tool_calls_total{outcome="success",tool="list_organizations"} 2
+planton_mcp_tool_calls_total{outcome="tool_error",tool="get_cloud_resource"} 1
+`
+	if err := testutil.CollectAndCompare(ToolCallsTotal, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
+
+// dialHealthServer serves the standard health service over an in-memory listener
+// and returns a client connection with the metrics interceptors installed.
+func dialHealthServer(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("planton", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, healthServer)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(StreamClientInterceptor()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestGRPCClientInterceptors(t *testing.T) {
+	GRPCClientCallsTotal.Reset()
+	client := healthpb.NewHealthClient(dialHealthServer(t))
+	ctx := context.Background()
+
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "planton"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "planton"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "unknown"}); err == nil {
+		t.Fatal("Check of an unknown service succeeded")
+	}
+
+	// A stream abandoned by cancelling its context is recorded once it finishes
+	streamCtx, cancel := context.WithCancel(ctx)
+	stream, err := client.Watch(streamCtx, &healthpb.HealthCheckRequest{Service: "planton"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if _, err := stream.Recv(); err == nil {
+		t.Fatal("Recv after cancel succeeded")
+	}
+
+	want := `
+# HELP planton_mcp_grpc_client_calls_total Total number of gRPC calls to Planton APIs by service, method and status code.
+# TYPE planton_mcp_grpc_client_calls_total counter
+planton_mcp_grpc_client_calls_total{code="Canceled",method="Watch",service="grpc.health.v1.Health"} 1
+planton_mcp_grpc_client_calls_total{code="NotFound",method="Check",service="grpc.health.v1.Health"} 1
+planton_mcp_grpc_client_calls_total{code="OK",method="Check",service="grpc.health.v1.Health"} 2
+`
+	if err := testutil.CollectAndCompare(GRPCClientCallsTotal, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSplitMethodName(t *testing.T) {
+	tests := []struct {
+		fullMethod  string
+		wantService string
+		wantMethod  string
+	}{
+		{"/ai.planton.servicehub.pipeline.v1.PipelineQueryController/getById", "ai.planton.servicehub.pipeline.v1.PipelineQueryController", "getById"},
+		{"getById", "unknown", "getById"},
+	}
+	for _, tt := range tests {
+		service, method := splitMethodName(tt.fullMethod)
+		if service != tt.wantService || method != tt.wantMethod {
+			t.Errorf("splitMethodName(%q) = %q, %q, want %q, %q", tt.fullMethod, service, method, tt.wantService, tt.wantMethod)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+// Package ratelimit enforces per-API-key limits on the HTTP transport.
+//
+// Two kinds of limits keep one runaway client from saturating this server and the
+// Planton APIs behind it:
+//   - a token bucket on HTTP requests, enforced by HTTPMiddleware with HTTP 429
+//   - caps on concurrently running tool calls, with a separate cap for streaming
+//     tools, enforced by ToolHandlerMiddleware with a structured tool error
+//
+// Both are keyed by the caller's API key, so a client hitting its limits does not
+// affect other users. API keys are only held as SHA-256 hashes.
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/auth"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/metrics"
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// anonymousClient is the client key for requests without an API key, which
+	// happens when HTTP authentication is disabled. They all share one set of limits.
+	anonymousClient = "anonymous"
+
+	// idleClientTTL is how long a client's state is kept after its last request.
+	idleClientTTL = 10 * time.Minute
+
+	// sweepInterval is how often idle client state is evicted.
+	sweepInterval = time.Minute
+
+	// concurrencyRetryAfter is the wait suggested to callers rejected by a
+	// concurrency cap. Calls finish at unpredictable times, so it is a hint only.
+	concurrencyRetryAfter = time.Second
+)
+
+// Limits holds the per-API-key limits. Zero values disable the corresponding limit.
+type Limits struct {
+	// RequestsPerSecond is the sustained HTTP request rate per API key
+	RequestsPerSecond float64
+
+	// Burst is the number of HTTP requests allowed at once above the sustained rate
+	Burst int
+
+	// MaxConcurrentToolCalls caps the non-streaming tool calls running at once
+	MaxConcurrentToolCalls int
+
+	// MaxConcurrentStreamingCalls caps the streaming tool calls running at once
+	MaxConcurrentStreamingCalls int
+}
+
+// LimitsFromConfig returns the limits configured for the server.
+func LimitsFromConfig(cfg *config.Config) Limits {
+	return Limits{
+		RequestsPerSecond:           cfg.RateLimitRPS,
+		Burst:                       cfg.RateLimitBurst,
+		MaxConcurrentToolCalls:      cfg.MaxConcurrentToolCalls,
+		MaxConcurrentStreamingCalls: cfg.MaxConcurrentStreamingCalls,
+	}
+}
+
+// clientState is the rate limiting state of one API key.
+type clientState struct {
+	requests       *rate.Limiter
+	toolCalls      int
+	streamingCalls int
+	lastSeen       time.Time
+}
+
+// Limiter tracks request rates and running tool calls per API key.
+type Limiter struct {
+	limits         Limits
+	streamingTools map[string]bool
+
+	mu        sync.Mutex
+	clients   map[string]*clientState
+	lastSweep time.Time
+}
+
+// New creates a limiter enforcing limits per API key.
+//
+// Args:
+//   - limits: Per-API-key limits
+//   - streamingTools: Names of long-running streaming tools, which are counted
+//     against MaxConcurrentStreamingCalls instead of MaxConcurrentToolCalls
+//
+// Returns the limiter.
+func New(limits Limits, streamingTools ...string) *Limiter {
+	// A token bucket with no capacity would reject every request
+	if limits.RequestsPerSecond > 0 && limits.Burst < 1 {
+		limits.Burst = 1
+	}
+
+	streaming := make(map[string]bool, len(streamingTools))
+	for _, name := range streamingTools {
+		streaming[name] = true
+	}
+
+	return &Limiter{
+		limits:         limits,
+		streamingTools: streaming,
+		clients:        make(map[string]*clientState),
+		lastSweep:      time.Now(),
+	}
+}
+
+// contextKey is the type for the client key stored in a request context.
+type contextKey struct{}
+
+// clientKey returns the key identifying the caller of a request: a hash of the API
+// key stored in ctx by the authentication middleware, or anonymousClient.
+func clientKey(ctx context.Context) string {
+	apiKey, err := auth.GetAPIKey(ctx)
+	if err != nil {
+		return anonymousClient
+	}
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// clientID shortens a client key for log lines.
+func clientID(key string) string {
+	if len(key) > 12 {
+		return key[:12]
+	}
+	return key
+}
+
+// HTTPMiddleware enforces the request rate limit of the caller's API key.
+//
+// It must run after authentication, so the API key is in the request context.
+// Requests over the limit are rejected with HTTP 429 and a Retry-After header.
+// Accepted requests carry the caller's client key in their context, which enables
+// the tool call caps of ToolHandlerMiddleware for the tool calls they trigger.
+func (l *Limiter) HTTPMiddleware(next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := clientKey(r.Context())
+
+		if delay := l.reserveRequest(key); delay > 0 {
+			retryAfter := retryAfterSeconds(delay)
+			metrics.RateLimitedTotal.WithLabelValues(metrics.LimitRequestRate).Inc()
+			slog.WarnContext(r.Context(), "Rate limit exceeded, rejecting HTTP request",
+				"client_id", clientID(key),
+				"retry_after_seconds", retryAfter,
+			)
+
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			http.Error(w,
+				fmt.Sprintf("Rate limit exceeded. Retry after %d seconds.", retryAfter),
+				http.StatusTooManyRequests,
+			)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), contextKey{}, key)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+}
+
+// ToolHandlerMiddleware enforces the concurrent tool call caps of the caller's API key.
+//
+// Only tool calls that came through HTTPMiddleware are limited; STDIO tool calls
+// serve a single local client and pass through unchanged. A call over the cap is
+// not run: the caller receives a RATE_LIMITED tool error with a retry hint.
+func (l *Limiter) ToolHandlerMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		key, ok := ctx.Value(contextKey{}).(string)
+		if !ok {
+			return next(ctx, request)
+		}
+
+		streaming := l.streamingTools[request.Params.Name]
+		if !l.acquireToolCall(key, streaming) {
+			limit, maxCalls := metrics.LimitToolCalls, l.limits.MaxConcurrentToolCalls
+			if streaming {
+				limit, maxCalls = metrics.LimitStreamingCalls, l.limits.MaxConcurrentStreamingCalls
+			}
+			metrics.RateLimitedTotal.WithLabelValues(limit).Inc()
+			slog.WarnContext(ctx, "Concurrency limit reached, rejecting tool call",
+				"client_id", clientID(key),
+				"limit", limit,
+				"max", maxCalls,
+			)
+			return rateLimitedResult(streaming, maxCalls), nil
+		}
+		defer l.releaseToolCall(key, streaming)
+
+		return next(ctx, request)
+	}
+}
+
+// reserveRequest takes a token from the client's bucket. It returns zero when the
+// request may proceed, or how long the client has to wait for a token otherwise.
+func (l *Limiter) reserveRequest(key string) time.Duration {
+	if l.limits.RequestsPerSecond <= 0 {
+		return 0
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	requests := l.client(key, now).requests
+	l.mu.Unlock()
+
+	reservation := requests.ReserveN(now, 1)
+	delay := reservation.DelayFrom(now)
+	if delay > 0 {
+		// The request is rejected, so give the token back for a later request
+		reservation.CancelAt(now)
+	}
+	return delay
+}
+
+// acquireToolCall counts a new tool call for the client, or returns false when the
+// client is already at its cap.
+func (l *Limiter) acquireToolCall(key string, streaming bool) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	c := l.client(key, time.Now())
+	if streaming {
+		if l.limits.MaxConcurrentStreamingCalls > 0 && c.streamingCalls >= l.limits.MaxConcurrentStreamingCalls {
+			return false
+		}
+		c.streamingCalls++
+		return true
+	}
+
+	if l.limits.MaxConcurrentToolCalls > 0 && c.toolCalls >= l.limits.MaxConcurrentToolCalls {
+		return false
+	}
+	c.toolCalls++
+	return true
+}
+
+// releaseToolCall marks a tool call counted by acquireToolCall as finished.
+func (l *Limiter) releaseToolCall(key string, streaming bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	c := l.client(key, time.Now())
+	if streaming {
+		c.streamingCalls--
+	} else {
+		c.toolCalls--
+	}
+}
+
+// client returns the state of a client, creating it on first use, and evicts
+// clients that have been idle for idleClientTTL. l.mu must be held.
+func (l *Limiter) client(key string, now time.Time) *clientState {
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		for k, c := range l.clients {
+			if c.toolCalls == 0 && c.streamingCalls == 0 && now.Sub(c.lastSeen) >= idleClientTTL {
+				delete(l.clients, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	c, ok := l.clients[key]
+	if !ok {
+		c = &clientState{}
+		if l.limits.RequestsPerSecond > 0 {
+			c.requests = rate.NewLimiter(rate.Limit(l.limits.RequestsPerSecond), l.limits.Burst)
+		}
+		l.clients[key] = c
+	}
+	c.lastSeen = now
+	return c
+}
+
+// retryAfterSeconds rounds a wait up to whole seconds, as used by Retry-After.
+func retryAfterSeconds(delay time.Duration) int {
+	return int(math.Ceil(delay.Seconds()))
+}
+
+// rateLimitedResult builds the tool error returned for a call over a concurrency cap.
+func rateLimitedResult(streaming bool, maxCalls int) *mcp.CallToolResult {
+	kind := "tool calls"
+	if streaming {
+		kind = "streaming tool calls"
+	}
+
+	return errors.ToolError(errors.ErrorResponse{
+		Error: errors.CodeRateLimited,
+		Message: fmt.Sprintf(
+			"Too many concurrent %s for this API key (limit %d). "+
+				"Wait for running calls to finish, then retry.",
+			kind, maxCalls,
+		),
+		RetryAfterSeconds: retryAfterSeconds(concurrencyRetryAfter),
+	})
+}
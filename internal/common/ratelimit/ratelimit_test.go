@@ -0,0 +1,189 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/auth"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
+)
+
+const streamingTool = "get_pipeline_build_logs"
+
+// serve sends a request with an API key through the limiter's HTTP middleware.
+func serve(l *Limiter, apiKey string) *httptest.ResponseRecorder {
+	handler := l.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req = req.WithContext(auth.WithAPIKey(req.Context(), apiKey))
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func TestHTTPMiddlewareRateLimit(t *testing.T) {
+	l := New(Limits{RequestsPerSecond: 0.5, Burst: 2})
+
+	for i := 0; i < 2; i++ {
+		if rec := serve(l, "key-a"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d within burst: status %d", i+1, rec.Code)
+		}
+	}
+
+	rec := serve(l, "key-a")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("request over burst: status %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+
+	// Limits are per API key
+	if rec := serve(l, "key-b"); rec.Code != http.StatusOK {
+		t.Errorf("other API key: status %d, want 200", rec.Code)
+	}
+}
+
+func TestRejectedRequestsGiveTheirTokenBack(t *testing.T) {
+	l := New(Limits{RequestsPerSecond: 20, Burst: 1})
+
+	if rec := serve(l, "key-a"); rec.Code != http.StatusOK {
+		t.Fatalf("first request: status %d", rec.Code)
+	}
+	// Without the token given back, every rejection would push the next
+	// available token further out
+	for i := 0; i < 10; i++ {
+		if rec := serve(l, "key-a"); rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("request %d: status %d, want 429", i+2, rec.Code)
+		}
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if rec := serve(l, "key-a"); rec.Code != http.StatusOK {
+		t.Errorf("request after one token interval: status %d, want 200", rec.Code)
+	}
+}
+
+// blockingCall starts a tool call through the limiter that blocks until release is
+// closed, and returns the result once the middleware has decided.
+func blockingCall(l *Limiter, ctx context.Context, tool string, release <-chan struct{}) <-chan *mcp.CallToolResult {
+	started := make(chan struct{})
+	results := make(chan *mcp.CallToolResult, 1)
+	handler := l.ToolHandlerMiddleware(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		close(started)
+		<-release
+		return mcp.NewToolResultText("done"), nil
+	})
+	request := mcp.CallToolRequest{}
+	request.Params.Name = tool
+	go func() {
+		result, _ := handler(ctx, request)
+		results <- result
+	}()
+	select {
+	case <-started:
+	case result := <-results:
+		// Rejected without running
+		results <- result
+	}
+	return results
+}
+
+// callTool runs a tool call that returns immediately.
+func callTool(l *Limiter, ctx context.Context, tool string) *mcp.CallToolResult {
+	release := make(chan struct{})
+	close(release)
+	return <-blockingCall(l, ctx, tool, release)
+}
+
+// clientContext returns the context HTTPMiddleware gives requests of an API key.
+func clientContext(apiKey string) context.Context {
+	return context.WithValue(context.Background(), contextKey{}, clientKey(auth.WithAPIKey(context.Background(), apiKey)))
+}
+
+func TestToolHandlerMiddlewareConcurrency(t *testing.T) {
+	l := New(Limits{MaxConcurrentToolCalls: 1, MaxConcurrentStreamingCalls: 1}, streamingTool)
+	ctx := clientContext("key-a")
+
+	release := make(chan struct{})
+	running := blockingCall(l, ctx, "get_cloud_resource", release)
+	streaming := blockingCall(l, ctx, streamingTool, release)
+
+	// Each cap is full, independently of the other
+	result := callTool(l, ctx, "list_environments")
+	var errResp errors.ErrorResponse
+	if !result.IsError || json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &errResp) != nil {
+		t.Fatalf("call over the tool call cap: %+v", result)
+	}
+	if errResp.Error != errors.CodeRateLimited || errResp.RetryAfterSeconds != 1 {
+		t.Errorf("error = %s, retry after %d, want RATE_LIMITED, 1", errResp.Error, errResp.RetryAfterSeconds)
+	}
+	if result := callTool(l, ctx, streamingTool); !result.IsError {
+		t.Error("call over the streaming cap was run")
+	}
+
+	// Caps are per API key
+	if result := callTool(l, clientContext("key-b"), "list_environments"); result.IsError {
+		t.Error("call of another API key was rejected")
+	}
+
+	// Finished calls release their slot
+	close(release)
+	<-running
+	<-streaming
+	if result := callTool(l, ctx, "list_environments"); result.IsError {
+		t.Error("call after the running call finished was rejected")
+	}
+	if result := callTool(l, ctx, streamingTool); result.IsError {
+		t.Error("streaming call after the running call finished was rejected")
+	}
+}
+
+func TestToolHandlerMiddlewareSkipsSTDIO(t *testing.T) {
+	l := New(Limits{MaxConcurrentToolCalls: 1})
+	release := make(chan struct{})
+	defer close(release)
+
+	blockingCall(l, context.Background(), "get_cloud_resource", release)
+	if result := callTool(l, context.Background(), "get_cloud_resource"); result.IsError {
+		t.Error("STDIO tool call was limited")
+	}
+}
+
+func TestIdleClientsAreEvicted(t *testing.T) {
+	l := New(Limits{MaxConcurrentToolCalls: 5})
+	busy, idle := clientKey(auth.WithAPIKey(context.Background(), "busy")), clientKey(auth.WithAPIKey(context.Background(), "idle"))
+	if !l.acquireToolCall(busy, false) {
+		t.Fatal("acquireToolCall() = false")
+	}
+	l.acquireToolCall(idle, false)
+	l.releaseToolCall(idle, false)
+
+	later := time.Now().Add(idleClientTTL + sweepInterval)
+	l.mu.Lock()
+	l.client("other", later)
+	_, busyKept := l.clients[busy]
+	_, idleKept := l.clients[idle]
+	l.mu.Unlock()
+
+	if !busyKept {
+		t.Error("client with a running call was evicted")
+	}
+	if idleKept {
+		t.Error("idle client was not evicted")
+	}
+}
+
+func TestClientKey(t *testing.T) {
+	if got := clientKey(context.Background()); got != anonymousClient {
+		t.Errorf("clientKey() without API key = %q, want %q", got, anonymousClient)
+	}
+	key := clientKey(auth.WithAPIKey(context.Background(), "pck_secret"))
+	if key == "pck_secret" || len(key) != 64 {
+		t.Errorf("clientKey() = %q, want a SHA-256 hash", key)
+	}
+}
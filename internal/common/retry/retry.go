@@ -0,0 +1,152 @@
+// Package retry retries gRPC query calls to Planton APIs that failed transiently.
+//
+// Only query RPCs are retried: they read state, so repeating one cannot change
+// anything. Command RPCs (create, update, delete) are never retried automatically,
+// since a command that failed with UNAVAILABLE may still have been applied.
+package retry
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/plantoncloud/mcp-server-planton/internal/common/metrics"
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// backoffMultiplier grows the wait between consecutive retries
+	backoffMultiplier = 2
+	// jitter randomizes each wait by up to ±20% so clients that failed together do
+	// not retry together
+	jitter = 0.2
+)
+
+// querySuffix marks the Planton API services that only read state, such as
+// CloudResourceQueryController or CloudResourceSearchQueryController.
+const querySuffix = "QueryController"
+
+// nonIdempotentQueries lists the full names of query RPCs that must not be retried
+// although their service only reads state: each call has a side effect, such as
+// minting a new credential.
+var nonIdempotentQueries = map[string]bool{
+	// Mints a new GitHub App installation token on every call
+	"/ai.planton.connect.githubcredential.v1.GithubQueryController/getInstallationToken": true,
+}
+
+// Policy configures retries of query RPCs.
+type Policy struct {
+	// MaxAttempts is the total number of attempts, including the first. One or less
+	// disables retries.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between retries
+	MaxBackoff time.Duration
+}
+
+// DefaultPolicy returns the retry policy used when none is configured.
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts:    config.DefaultGRPCRetryMaxAttempts,
+		InitialBackoff: config.DefaultGRPCRetryInitialBackoff,
+		MaxBackoff:     config.DefaultGRPCRetryMaxBackoff,
+	}
+}
+
+// PolicyFromConfig returns the retry policy configured for the server.
+func PolicyFromConfig(cfg *config.Config) Policy {
+	return Policy{
+		MaxAttempts:    cfg.GRPCRetryMaxAttempts,
+		InitialBackoff: cfg.GRPCRetryInitialBackoff,
+		MaxBackoff:     cfg.GRPCRetryMaxBackoff,
+	}
+}
+
+// enabled reports whether the policy retries at all.
+func (p Policy) enabled() bool {
+	return p.MaxAttempts > 1
+}
+
+// backoff returns the wait before the given retry (1 for the first retry):
+// exponential growth from InitialBackoff, capped at MaxBackoff, with jitter.
+func (p Policy) backoff(retry int) time.Duration {
+	wait := float64(p.InitialBackoff)
+	for i := 1; i < retry && wait < float64(p.MaxBackoff); i++ {
+		wait *= backoffMultiplier
+	}
+	wait = min(wait, float64(p.MaxBackoff))
+	return time.Duration(wait * (1 + jitter*(2*rand.Float64()-1)))
+}
+
+// IsQueryMethod reports whether a full gRPC method name
+// ("/package.Service/Method") belongs to a query service.
+func IsQueryMethod(fullMethod string) bool {
+	service, _, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return ok && strings.HasSuffix(service, querySuffix)
+}
+
+// isRetryableMethod reports whether calls to a full gRPC method name may be
+// repeated: it is a query RPC without side effects.
+func isRetryableMethod(fullMethod string) bool {
+	return IsQueryMethod(fullMethod) && !nonIdempotentQueries[fullMethod]
+}
+
+// isRetryable reports whether a failed attempt may succeed when repeated.
+// Only UNAVAILABLE is: the request did not reach a healthy server.
+func isRetryable(err error) bool {
+	return status.Code(err) == codes.Unavailable
+}
+
+// UnaryClientInterceptor returns a gRPC client interceptor that retries unary query
+// RPCs failing with UNAVAILABLE, with exponential backoff and jitter. Other RPCs,
+// and the query RPCs listed in nonIdempotentQueries, pass through unchanged.
+// Streaming RPCs are not intercepted.
+//
+// Retries stop early when the call's context is done or its deadline would expire
+// during the next wait; the error of the last attempt is returned. Every retry is
+// logged and counted in metrics.GRPCClientRetriesTotal.
+//
+// The shared gRPC connection pool (internal/common/grpcpool) installs it on every
+// connection, outside the metrics interceptor so each attempt is recorded.
+func UnaryClientInterceptor(p Policy) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !p.enabled() || !isRetryableMethod(method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		for attempt := 1; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil || attempt >= p.MaxAttempts || !isRetryable(err) {
+				return err
+			}
+
+			wait := p.backoff(attempt)
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+				return err
+			}
+
+			slog.WarnContext(ctx, "Retrying Planton API query",
+				"method", method,
+				"attempt", attempt+1,
+				"max_attempts", p.MaxAttempts,
+				"backoff", wait,
+				"code", status.Code(err).String(),
+			)
+			metrics.ObserveGRPCRetry(method, err)
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+		}
+	}
+}
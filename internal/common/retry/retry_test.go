@@ -0,0 +1,141 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	queryMethod   = "/ai.planton.infrahub.cloudresource.v1.CloudResourceQueryController/get"
+	commandMethod = "/ai.planton.infrahub.cloudresource.v1.CloudResourceCommandController/create"
+	tokenMethod   = "/ai.planton.connect.githubcredential.v1.GithubQueryController/getInstallationToken"
+)
+
+// failingInvoker fails with the given codes in order, then succeeds, and counts calls.
+func failingInvoker(calls *int, failures ...codes.Code) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		*calls++
+		if *calls <= len(failures) {
+			return status.Error(failures[*calls-1], "failed")
+		}
+		return nil
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	policy := Policy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+	tests := []struct {
+		name      string
+		method    string
+		failures  []codes.Code
+		wantCalls int
+		wantCode  codes.Code
+	}{
+		{
+			name:      "query recovers from unavailable",
+			method:    queryMethod,
+			failures:  []codes.Code{codes.Unavailable, codes.Unavailable},
+			wantCalls: 3,
+			wantCode:  codes.OK,
+		},
+		{
+			name:      "query gives up after max attempts",
+			method:    queryMethod,
+			failures:  []codes.Code{codes.Unavailable, codes.Unavailable, codes.Unavailable},
+			wantCalls: 3,
+			wantCode:  codes.Unavailable,
+		},
+		{
+			name:      "query is not retried on other codes",
+			method:    queryMethod,
+			failures:  []codes.Code{codes.NotFound},
+			wantCalls: 1,
+			wantCode:  codes.NotFound,
+		},
+		{
+			name:      "query minting a token is never retried",
+			method:    tokenMethod,
+			failures:  []codes.Code{codes.Unavailable},
+			wantCalls: 1,
+			wantCode:  codes.Unavailable,
+		},
+		{
+			name:      "command is never retried",
+			method:    commandMethod,
+			failures:  []codes.Code{codes.Unavailable},
+			wantCalls: 1,
+			wantCode:  codes.Unavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := UnaryClientInterceptor(policy)(context.Background(), tt.method, nil, nil, nil,
+				failingInvoker(&calls, tt.failures...))
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("code = %v, want %v", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestUnaryClientInterceptorStopsBeforeDeadline(t *testing.T) {
+	policy := Policy{MaxAttempts: 5, InitialBackoff: time.Second, MaxBackoff: time.Second}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	start := time.Now()
+	err := UnaryClientInterceptor(policy)(ctx, queryMethod, nil, nil, nil,
+		failingInvoker(&calls, codes.Unavailable, codes.Unavailable))
+	if calls != 1 || status.Code(err) != codes.Unavailable {
+		t.Errorf("calls = %d, code = %v; want 1 call failing with Unavailable", calls, status.Code(err))
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("waited %v for a retry that could not finish before the deadline", elapsed)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := Policy{MaxAttempts: 10, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for retry, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		5: time.Second, // capped
+	} {
+		for range 100 {
+			got := policy.backoff(retry)
+			lo := time.Duration(float64(want) * (1 - jitter))
+			hi := time.Duration(float64(want) * (1 + jitter))
+			if got < lo || got > hi {
+				t.Fatalf("backoff(%d) = %v, want within [%v, %v]", retry, got, lo, hi)
+			}
+		}
+	}
+}
+
+func TestIsQueryMethod(t *testing.T) {
+	for method, want := range map[string]bool{
+		queryMethod:   true,
+		commandMethod: false,
+		"/ai.planton.search.v1.infrahub.cloudresource.CloudResourceSearchQueryController/lookupCloudResource": true,
+		"/grpc.health.v1.Health/Check": false,
+		"malformed":                    false,
+	} {
+		if got := IsQueryMethod(method); got != want {
+			t.Errorf("IsQueryMethod(%q) = %v, want %v", method, got, want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxArgumentValueLength caps the length of string arguments recorded on tool spans.
+const maxArgumentValueLength = 256
+
+// argumentAttributePrefix prefixes the span attributes holding tool arguments.
+const argumentAttributePrefix = "mcp.tool.argument."
+
+// secretArgumentKeys lists argument names that are never recorded on spans.
+// Resource specs can embed credentials, so they are treated as secret as well.
+var secretArgumentKeys = []string{"key", "token", "secret", "password", "credential_data", "spec"}
+
+// ToolHandlerMiddleware wraps every tool invocation in a span named "tools/call <tool>".
+//
+// The span records the tool name and its non-secret scalar arguments, and is marked
+// as failed when the handler returns an error or a result flagged with IsError.
+// gRPC calls made by the tool become child spans through GRPCDialOption.
+func ToolHandlerMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	tracer := otel.Tracer(instrumentationName)
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		toolName := request.Params.Name
+
+		attrs := []attribute.KeyValue{
+			attribute.String("mcp.method.name", "tools/call"),
+			attribute.String("gen_ai.tool.name", toolName),
+		}
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			attrs = append(attrs, attribute.String("mcp.session.id", session.SessionID()))
+		}
+		attrs = append(attrs, argumentAttributes(request.GetArguments())...)
+
+		ctx, span := tracer.Start(ctx, "tools/call "+toolName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attrs...),
+		)
+		defer span.End()
+
+		result, err := next(ctx, request)
+		switch {
+		case err != nil:
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		case result != nil && result.IsError:
+			span.SetStatus(codes.Error, "tool returned an error result")
+		}
+		return result, err
+	}
+}
+
+// argumentAttributes converts tool arguments into span attributes.
+//
+// Only scalar values and lists of strings are recorded. Objects (such as resource
+// specs) and arguments whose name looks secret are skipped, and long strings are
+// truncated to maxArgumentValueLength.
+func argumentAttributes(arguments map[string]interface{}) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(arguments))
+	for name, value := range arguments {
+		if isSecretArgument(name) {
+			continue
+		}
+		key := argumentAttributePrefix + name
+
+		switch v := value.(type) {
+		case string:
+			attrs = append(attrs, attribute.String(key, truncate(v)))
+		case bool:
+			attrs = append(attrs, attribute.Bool(key, v))
+		case float64:
+			attrs = append(attrs, attribute.Float64(key, v))
+		case []interface{}:
+			values := make([]string, 0, len(v))
+			for _, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					values = nil
+					break
+				}
+				values = append(values, truncate(s))
+			}
+			if values != nil {
+				attrs = append(attrs, attribute.StringSlice(key, values))
+			}
+		default:
+			// Objects and other types are not recorded
+		}
+	}
+	return attrs
+}
+
+// isSecretArgument reports whether an argument must not be recorded on spans.
+func isSecretArgument(name string) bool {
+	lower := strings.ToLower(name)
+	for _, secret := range secretArgumentKeys {
+		if strings.Contains(lower, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// truncate shortens s to maxArgumentValueLength bytes, marking that it was cut.
+func truncate(s string) string {
+	if len(s) <= maxArgumentValueLength {
+		return s
+	}
+	return fmt.Sprintf("%s...(%d bytes)", s[:maxArgumentValueLength], len(s))
+}
@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestArgumentAttributes(t *testing.T) {
+	long := strings.Repeat("a", maxArgumentValueLength+10)
+	attrs := argumentAttributes(map[string]interface{}{
+		"org_id":              "acme",
+		"include_deleted":     true,
+		"page_size":           float64(20),
+		"tags":                []interface{}{"team:core", long},
+		"mixed":               []interface{}{"a", float64(1)},
+		"description":         long,
+		"spec":                map[string]interface{}{"region": "us-east-1"},
+		"metadata":            map[string]interface{}{"name": "vpc"},
+		"api_key":             "pck_123",
+		"github_access_token": "ghs_123",
+		"Client_Secret":       "s3cr3t",
+		"secret_value":        "s3cr3t",
+		"password":            "hunter2",
+	})
+
+	got := make(map[attribute.Key]attribute.Value, len(attrs))
+	for _, attr := range attrs {
+		got[attr.Key] = attr.Value
+	}
+
+	truncated := strings.Repeat("a", maxArgumentValueLength) + "...(266 bytes)"
+	want := map[attribute.Key]attribute.Value{
+		"mcp.tool.argument.org_id":          attribute.StringValue("acme"),
+		"mcp.tool.argument.include_deleted": attribute.BoolValue(true),
+		"mcp.tool.argument.page_size":       attribute.Float64Value(20),
+		"mcp.tool.argument.tags":            attribute.StringSliceValue([]string{"team:core", truncated}),
+		"mcp.tool.argument.description":     attribute.StringValue(truncated),
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d attributes %v, want %d", len(got), got, len(want))
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %v, want %v", key, got[key].Emit(), value.Emit())
+		}
+	}
+}
+
+func TestToolSpanJoinsIncomingTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer provider.Shutdown(context.Background())
+
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	}()
+
+	tool := ToolHandlerMiddleware(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("not found"), nil
+	})
+	handler := HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "get_cloud_resource"
+		request.Params.Arguments = map[string]interface{}{"id": "awsvpc-123"}
+		tool(r.Context(), request)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "tools/call get_cloud_resource" {
+		t.Errorf("name = %q", span.Name())
+	}
+	if got := span.SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace ID = %s, want the incoming trace", got)
+	}
+	if got := span.Parent().SpanID().String(); got != "00f067aa0ba902b7" || !span.Parent().IsRemote() {
+		t.Errorf("parent = %s (remote %t), want the incoming remote span", got, span.Parent().IsRemote())
+	}
+	if span.Status().Description != "tool returned an error result" {
+		t.Errorf("status = %+v", span.Status())
+	}
+}
@@ -0,0 +1,153 @@
+// Package tracing sets up OpenTelemetry tracing for the MCP server.
+//
+// A trace starts at the incoming HTTP request (continuing the caller's W3C
+// traceparent when present), gets one span per tool invocation, and continues
+// into child spans for every gRPC call the tool makes to Planton APIs.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"google.golang.org/grpc"
+)
+
+// serviceName is the default OpenTelemetry service name. OTEL_SERVICE_NAME overrides it.
+const serviceName = "mcp-server-planton"
+
+// instrumentationName identifies the tracer used for tool spans.
+const instrumentationName = "github.com/plantoncloud/mcp-server-planton/internal/common/tracing"
+
+// Setup installs the global OpenTelemetry tracer provider and W3C propagators.
+//
+// Propagators are always installed, so a traceparent received over HTTP is passed
+// on to Planton APIs even when span export is disabled. A tracer provider is only
+// installed when cfg.TracingExporter is not "none".
+//
+// Args:
+//   - ctx: Context for creating the exporter
+//   - cfg: Server configuration with the tracing settings
+//
+// Returns a shutdown function that flushes pending spans, and any error encountered
+// while creating the exporter.
+func Setup(ctx context.Context, cfg *config.Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if cfg.TracingExporter == config.TracingExporterNone {
+		slog.Info("Tracing: span export disabled (trace context is still propagated)")
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", cfg.TracingExporter, err)
+	}
+
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewSchemaless(semconv.ServiceName(serviceName)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+	// Let OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the defaults
+	envRes, err := resource.New(ctx, resource.WithFromEnv())
+	if err == nil {
+		if merged, mergeErr := resource.Merge(res, envRes); mergeErr == nil {
+			res = merged
+		}
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TracingSampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	endpoint := cfg.TracingEndpoint
+	if endpoint == "" {
+		endpoint = "exporter default"
+	}
+	slog.Info("Tracing: exporting spans",
+		"exporter", cfg.TracingExporter,
+		"endpoint", endpoint,
+		"sample_ratio", cfg.TracingSampleRatio,
+	)
+
+	return provider.Shutdown, nil
+}
+
+// newExporter creates the OTLP span exporter selected by the configuration.
+func newExporter(ctx context.Context, cfg *config.Config) (sdktrace.SpanExporter, error) {
+	switch cfg.TracingExporter {
+	case config.TracingExporterOTLPGRPC:
+		var opts []otlptracegrpc.Option
+		if cfg.TracingEndpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(cfg.TracingEndpoint))
+		}
+		if cfg.TracingInsecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, opts...)
+
+	case config.TracingExporterOTLPHTTP:
+		var opts []otlptracehttp.Option
+		if cfg.TracingEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(endpointURL(cfg)))
+		}
+		if cfg.TracingInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+
+	default:
+		return nil, fmt.Errorf("unsupported trace exporter %q", cfg.TracingExporter)
+	}
+}
+
+// endpointURL returns the OTLP/HTTP endpoint as a URL. A bare host:port gets the
+// scheme implied by TracingInsecure and the standard /v1/traces path.
+func endpointURL(cfg *config.Config) string {
+	endpoint := cfg.TracingEndpoint
+	if strings.HasPrefix(endpoint, "http://") || strings.HasPrefix(endpoint, "https://") {
+		return endpoint
+	}
+	scheme := "https"
+	if cfg.TracingInsecure {
+		scheme = "http"
+	}
+	return scheme + "://" + endpoint + "/v1/traces"
+}
+
+// GRPCDialOption returns the dial option that creates a child span for every gRPC
+// call and propagates the trace context to Planton APIs.
+//
+// The shared gRPC connection pool (internal/common/grpcpool) adds it to every connection.
+func GRPCDialOption() grpc.DialOption {
+	return grpc.WithStatsHandler(otelgrpc.NewClientHandler())
+}
+
+// HTTPMiddleware extracts the W3C trace context (traceparent/tracestate headers)
+// from incoming requests, so tool spans join the caller's trace.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -2,7 +2,12 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Environment represents the Planton Cloud environment
@@ -17,6 +22,43 @@ const (
 	TransportBoth  TransportMode = "both"
 )
 
+// LogFormat represents the log output format
+type LogFormat string
+
+const (
+	LogFormatText LogFormat = "text"
+	LogFormatJSON LogFormat = "json"
+)
+
+// GRPCTLSMode selects whether connections to Planton APIs use TLS
+type GRPCTLSMode string
+
+const (
+	// GRPCTLSAuto uses TLS for endpoints on port 443 and whenever a TLS option is set
+	GRPCTLSAuto GRPCTLSMode = "auto"
+	// GRPCTLSOn always uses TLS
+	GRPCTLSOn GRPCTLSMode = "on"
+	// GRPCTLSOff never uses TLS
+	GRPCTLSOff GRPCTLSMode = "off"
+)
+
+// Log levels
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+// TracingExporter represents the OpenTelemetry trace exporter
+type TracingExporter string
+
+const (
+	TracingExporterNone     TracingExporter = "none"
+	TracingExporterOTLPGRPC TracingExporter = "otlp-grpc"
+	TracingExporterOTLPHTTP TracingExporter = "otlp-http"
+)
+
 const (
 	// EnvironmentEnvVar is the environment variable to set the target environment
 	EnvironmentEnvVar = "PLANTON_CLOUD_ENVIRONMENT"
@@ -36,6 +78,140 @@ const (
 	// HTTPAuthEnabledEnvVar enables bearer token authentication for HTTP transport
 	HTTPAuthEnabledEnvVar = "PLANTON_MCP_HTTP_AUTH_ENABLED"
 
+	// HTTPExternalURLEnvVar specifies the public base URL clients use to reach the HTTP server
+	HTTPExternalURLEnvVar = "PLANTON_MCP_HTTP_EXTERNAL_URL"
+
+	// HTTPBasePathEnvVar specifies the path prefix under which MCP endpoints are served
+	HTTPBasePathEnvVar = "PLANTON_MCP_HTTP_BASE_PATH"
+
+	// HTTPAllowedOriginsEnvVar specifies the browser origins allowed to call the HTTP server
+	HTTPAllowedOriginsEnvVar = "PLANTON_MCP_HTTP_ALLOWED_ORIGINS"
+
+	// HTTPBindAddressEnvVar specifies the interface address the HTTP server listens on
+	HTTPBindAddressEnvVar = "PLANTON_MCP_HTTP_BIND_ADDRESS"
+
+	// OAuthAuthorizationServersEnvVar lists the OAuth authorization servers advertised in
+	// the protected resource metadata
+	OAuthAuthorizationServersEnvVar = "PLANTON_MCP_OAUTH_AUTHORIZATION_SERVERS"
+
+	// JWTJWKSURLEnvVar specifies the JWKS used to validate JWT bearer tokens locally
+	JWTJWKSURLEnvVar = "PLANTON_MCP_JWT_JWKS_URL"
+
+	// JWTIssuerEnvVar specifies the required "iss" claim of JWT bearer tokens
+	JWTIssuerEnvVar = "PLANTON_MCP_JWT_ISSUER"
+
+	// JWTAudienceEnvVar specifies the accepted "aud" claim values of JWT bearer tokens
+	JWTAudienceEnvVar = "PLANTON_MCP_JWT_AUDIENCE"
+
+	// TokenValidationEnabledEnvVar enables pre-validation of opaque bearer tokens against Planton APIs
+	TokenValidationEnabledEnvVar = "PLANTON_MCP_TOKEN_VALIDATION_ENABLED"
+
+	// TokenCacheTTLEnvVar specifies how long an accepted bearer token is trusted before it is checked again
+	TokenCacheTTLEnvVar = "PLANTON_MCP_TOKEN_CACHE_TTL"
+
+	// TokenRejectedCacheTTLEnvVar specifies how long a rejected bearer token stays rejected without a new check
+	TokenRejectedCacheTTLEnvVar = "PLANTON_MCP_TOKEN_REJECTED_CACHE_TTL"
+
+	// TokenCacheSizeEnvVar specifies how many bearer token validation results are cached
+	TokenCacheSizeEnvVar = "PLANTON_MCP_TOKEN_CACHE_SIZE"
+
+	// GRPCIdleTimeoutEnvVar specifies how long an unused pooled gRPC connection stays open
+	GRPCIdleTimeoutEnvVar = "PLANTON_MCP_GRPC_IDLE_TIMEOUT"
+
+	// GRPCKeepaliveTimeEnvVar specifies how often pooled gRPC connections are pinged when idle
+	GRPCKeepaliveTimeEnvVar = "PLANTON_MCP_GRPC_KEEPALIVE_TIME"
+
+	// GRPCKeepaliveTimeoutEnvVar specifies how long to wait for a keepalive ping acknowledgement
+	GRPCKeepaliveTimeoutEnvVar = "PLANTON_MCP_GRPC_KEEPALIVE_TIMEOUT"
+
+	// GRPCRetryMaxAttemptsEnvVar specifies how many times a failed query RPC is attempted in total
+	GRPCRetryMaxAttemptsEnvVar = "PLANTON_MCP_GRPC_RETRY_MAX_ATTEMPTS"
+
+	// GRPCRetryInitialBackoffEnvVar specifies the wait before the first retry of a query RPC
+	GRPCRetryInitialBackoffEnvVar = "PLANTON_MCP_GRPC_RETRY_INITIAL_BACKOFF"
+
+	// GRPCRetryMaxBackoffEnvVar specifies the longest wait between retries of a query RPC
+	GRPCRetryMaxBackoffEnvVar = "PLANTON_MCP_GRPC_RETRY_MAX_BACKOFF"
+
+	// GRPCTLSEnvVar specifies whether connections to Planton APIs use TLS (auto, on, off)
+	GRPCTLSEnvVar = "PLANTON_MCP_GRPC_TLS"
+
+	// GRPCTLSCAFileEnvVar specifies a PEM bundle of additional CAs trusted for Planton APIs
+	GRPCTLSCAFileEnvVar = "PLANTON_MCP_GRPC_TLS_CA_FILE"
+
+	// GRPCTLSCertFileEnvVar specifies the PEM client certificate presented for mutual TLS
+	GRPCTLSCertFileEnvVar = "PLANTON_MCP_GRPC_TLS_CERT_FILE"
+
+	// GRPCTLSKeyFileEnvVar specifies the PEM private key of the client certificate
+	GRPCTLSKeyFileEnvVar = "PLANTON_MCP_GRPC_TLS_KEY_FILE"
+
+	// GRPCTLSServerNameEnvVar overrides the server name verified in the Planton APIs certificate
+	GRPCTLSServerNameEnvVar = "PLANTON_MCP_GRPC_TLS_SERVER_NAME"
+
+	// ToolTimeoutEnvVar specifies how long a tool call may run before it is stopped
+	ToolTimeoutEnvVar = "PLANTON_MCP_TOOL_TIMEOUT"
+
+	// ToolTimeoutsEnvVar specifies per-tool overrides of the tool call timeout
+	ToolTimeoutsEnvVar = "PLANTON_MCP_TOOL_TIMEOUTS"
+
+	// ShutdownTimeoutEnvVar specifies how long shutdown waits for in-flight tool calls
+	ShutdownTimeoutEnvVar = "PLANTON_MCP_SHUTDOWN_TIMEOUT"
+
+	// ShutdownDelayEnvVar specifies how long the HTTP server reports not ready before
+	// it stops accepting connections
+	ShutdownDelayEnvVar = "PLANTON_MCP_SHUTDOWN_DELAY"
+
+	// LogLevelEnvVar specifies the minimum log level (debug, info, warn, error)
+	LogLevelEnvVar = "PLANTON_MCP_LOG_LEVEL"
+
+	// LogFormatEnvVar specifies the log output format (text or json)
+	LogFormatEnvVar = "PLANTON_MCP_LOG_FORMAT"
+
+	// RateLimitRPSEnvVar specifies the sustained HTTP request rate allowed per API key
+	RateLimitRPSEnvVar = "PLANTON_MCP_RATE_LIMIT_RPS"
+
+	// RateLimitBurstEnvVar specifies how many HTTP requests per API key may exceed the sustained rate
+	RateLimitBurstEnvVar = "PLANTON_MCP_RATE_LIMIT_BURST"
+
+	// MaxConcurrentToolCallsEnvVar specifies how many tool calls per API key may run at once
+	MaxConcurrentToolCallsEnvVar = "PLANTON_MCP_MAX_CONCURRENT_TOOL_CALLS"
+
+	// MaxConcurrentStreamingCallsEnvVar specifies how many streaming tool calls per API key may run at once
+	MaxConcurrentStreamingCallsEnvVar = "PLANTON_MCP_MAX_CONCURRENT_STREAMING_CALLS"
+
+	// TracingExporterEnvVar selects the OpenTelemetry trace exporter (none, otlp-grpc, otlp-http)
+	TracingExporterEnvVar = "PLANTON_MCP_TRACING_EXPORTER"
+
+	// TracingEndpointEnvVar specifies the OTLP collector endpoint
+	TracingEndpointEnvVar = "PLANTON_MCP_TRACING_ENDPOINT"
+
+	// TracingInsecureEnvVar disables TLS for the connection to the OTLP collector
+	TracingInsecureEnvVar = "PLANTON_MCP_TRACING_INSECURE"
+
+	// TracingSampleRatioEnvVar specifies the fraction of new traces to sample (0.0 to 1.0)
+	TracingSampleRatioEnvVar = "PLANTON_MCP_TRACING_SAMPLE_RATIO"
+
+	// ConfigFileEnvVar specifies the config file to load instead of the default location
+	ConfigFileEnvVar = "PLANTON_MCP_CONFIG"
+
+	// ProfileEnvVar selects the profile of the config file
+	ProfileEnvVar = "PLANTON_MCP_PROFILE"
+
+	// DefaultOrgEnvVar specifies the organization used when a tool call omits it
+	DefaultOrgEnvVar = "PLANTON_MCP_DEFAULT_ORG"
+
+	// DefaultEnvEnvVar specifies the Planton environment (e.g. dev, prod) used when a tool call omits it
+	DefaultEnvEnvVar = "PLANTON_MCP_DEFAULT_ENV"
+
+	// ReadOnlyEnvVar restricts the server to tools that do not modify resources
+	ReadOnlyEnvVar = "PLANTON_MCP_READ_ONLY"
+
+	// ToolsAllowEnvVar lists the tools or domains that are registered
+	ToolsAllowEnvVar = "PLANTON_MCP_TOOLS_ALLOW"
+
+	// ToolsDenyEnvVar lists the tools or domains that are not registered
+	ToolsDenyEnvVar = "PLANTON_MCP_TOOLS_DENY"
+
 	// Environment values
 	EnvironmentLive  Environment = "live"
 	EnvironmentTest  Environment = "test"
@@ -47,11 +223,46 @@ const (
 	LiveEndpoint  = "api.live.planton.ai:443"
 
 	// Default values
-	DefaultTransport = "stdio"
-	DefaultHTTPPort  = "8080"
+	DefaultTransport       = "stdio"
+	DefaultHTTPPort        = "8080"
+	DefaultShutdownTimeout = 30 * time.Second
+	DefaultShutdownDelay   = 5 * time.Second
+	DefaultLogLevel        = LogLevelInfo
+	DefaultLogFormat       = LogFormatText
+	DefaultTracingExporter = TracingExporterNone
+	DefaultTracingSample   = 1.0
+
+	// Default per-API-key limits for the HTTP transport
+	DefaultRateLimitRPS                = 10.0
+	DefaultRateLimitBurst              = 20
+	DefaultMaxConcurrentToolCalls      = 10
+	DefaultMaxConcurrentStreamingCalls = 2
+
+	// Default bearer token pre-validation cache settings
+	DefaultTokenCacheTTL         = 5 * time.Minute
+	DefaultTokenRejectedCacheTTL = time.Minute
+	DefaultTokenCacheSize        = 10000
+
+	// Default pooled gRPC connection settings
+	DefaultGRPCIdleTimeout      = 5 * time.Minute
+	DefaultGRPCKeepaliveTime    = 30 * time.Second
+	DefaultGRPCKeepaliveTimeout = 10 * time.Second
+
+	// Default retry policy of query RPCs to Planton APIs
+	DefaultGRPCRetryMaxAttempts    = 3
+	DefaultGRPCRetryInitialBackoff = 200 * time.Millisecond
+	DefaultGRPCRetryMaxBackoff     = 2 * time.Second
+
+	// DefaultToolTimeout bounds tool calls without a per-tool timeout
+	DefaultToolTimeout = 30 * time.Second
+
+	// LocalhostBindAddress is the default HTTP bind address when authentication is
+	// disabled, so an unauthenticated server is not reachable from the network
+	LocalhostBindAddress = "127.0.0.1"
 )
 
-// Config holds the MCP server configuration loaded from environment variables.
+// Config holds the MCP server configuration loaded from command-line flags,
+// environment variables and the config file.
 //
 // Unlike agent-fleet-worker (which uses machine account), this server
 // expects PLANTON_API_KEY to be passed via environment by LangGraph or other MCP clients.
@@ -73,138 +284,937 @@ type Config struct {
 
 	// HTTPAuthEnabled determines if bearer token authentication is required for HTTP
 	HTTPAuthEnabled bool
+
+	// HTTPExternalURL is the public base URL (scheme and host, e.g. https://mcp.planton.ai)
+	// advertised to SSE clients in the message endpoint. Empty means a relative endpoint.
+	HTTPExternalURL string
+
+	// HTTPBasePath is the path prefix for MCP endpoints (e.g. /planton). Empty means root.
+	HTTPBasePath string
+
+	// HTTPAllowedOrigins lists the browser origins (e.g. https://inspector.example.com)
+	// allowed to call the HTTP server. Requests carrying any other Origin header are
+	// rejected. "*" allows every origin. Empty allows none besides HTTPExternalURL.
+	HTTPAllowedOrigins []string
+
+	// HTTPBindAddress is the interface address the HTTP server listens on. Empty means
+	// all interfaces. Defaults to 127.0.0.1 when HTTPAuthEnabled is false.
+	HTTPBindAddress string
+
+	// OAuthAuthorizationServers lists the issuer URLs of the OAuth authorization servers
+	// that issue tokens for this server. They are advertised to MCP clients in the
+	// protected resource metadata (/.well-known/oauth-protected-resource).
+	OAuthAuthorizationServers []string
+
+	// JWTJWKSURL is the JSON Web Key Set used to validate JWT bearer tokens before a
+	// request is served. Empty disables local validation: every bearer token is passed
+	// through to Planton APIs as-is. Opaque (non-JWT) API keys are always passed through.
+	JWTJWKSURL string
+
+	// JWTIssuer is the required "iss" claim of JWT bearer tokens. Empty skips the check.
+	JWTIssuer string
+
+	// JWTAudience lists the accepted "aud" claim values of JWT bearer tokens; a token
+	// must carry at least one of them. Empty skips the check.
+	JWTAudience []string
+
+	// TokenValidationEnabled makes the HTTP server check each new bearer token with a
+	// cheap authenticated Planton API call before serving it, so invalid API keys are
+	// rejected with 401 at connect time. JWTs validated locally are not checked again.
+	TokenValidationEnabled bool
+
+	// TokenCacheTTL is how long an accepted bearer token is trusted before it is
+	// checked again. It bounds how long a revoked API key can keep opening sessions.
+	// Zero checks every request.
+	TokenCacheTTL time.Duration
+
+	// TokenRejectedCacheTTL is how long a rejected bearer token is rejected without
+	// asking Planton APIs again. Zero checks rejected tokens on every request.
+	TokenRejectedCacheTTL time.Duration
+
+	// TokenCacheSize is the maximum number of cached token validation results. The
+	// least recently used results are evicted first. Zero disables the cache.
+	TokenCacheSize int
+
+	// GRPCIdleTimeout is how long a pooled gRPC connection to Planton APIs stays open
+	// after its last use. Zero keeps connections open for the life of the process.
+	GRPCIdleTimeout time.Duration
+
+	// GRPCKeepaliveTime is how often pooled gRPC connections are pinged when there is
+	// no other activity, to detect dead connections. Zero disables keepalive pings.
+	GRPCKeepaliveTime time.Duration
+
+	// GRPCKeepaliveTimeout is how long to wait for a keepalive ping acknowledgement
+	// before the connection is considered dead.
+	GRPCKeepaliveTimeout time.Duration
+
+	// GRPCRetryMaxAttempts is how many times a query RPC that failed with
+	// codes.Unavailable is attempted in total, including the first attempt. One or
+	// zero disables retries. Command RPCs are never retried.
+	GRPCRetryMaxAttempts int
+
+	// GRPCRetryInitialBackoff is the wait before the first retry. Each further retry
+	// waits twice as long, up to GRPCRetryMaxBackoff, with random jitter.
+	GRPCRetryInitialBackoff time.Duration
+
+	// GRPCRetryMaxBackoff caps the wait between retries.
+	GRPCRetryMaxBackoff time.Duration
+
+	// GRPCTLSMode selects whether connections to Planton APIs use TLS. In auto mode,
+	// TLS is used for endpoints on port 443 and whenever a TLS option below is set.
+	GRPCTLSMode GRPCTLSMode
+
+	// GRPCTLSCAFile is a PEM bundle of CAs trusted in addition to the system roots,
+	// e.g. for self-hosted deployments or TLS-intercepting corporate proxies.
+	GRPCTLSCAFile string
+
+	// GRPCTLSCertFile and GRPCTLSKeyFile are the PEM client certificate and key
+	// presented for mutual TLS. Both or neither are set.
+	GRPCTLSCertFile string
+	GRPCTLSKeyFile  string
+
+	// GRPCTLSServerName overrides the name verified in the server certificate, which
+	// defaults to the endpoint's host.
+	GRPCTLSServerName string
+
+	// ToolTimeout is how long a tool call may run before its context is cancelled,
+	// which stops its in-flight gRPC calls and streams. Zero disables the deadline.
+	ToolTimeout time.Duration
+
+	// ToolTimeouts overrides ToolTimeout for individual tools, keyed by tool name.
+	// A zero value disables the deadline of that tool.
+	ToolTimeouts map[string]time.Duration
+
+	// ShutdownTimeout bounds graceful shutdown: how long in-flight tool calls and
+	// open connections get to finish after SIGINT/SIGTERM.
+	ShutdownTimeout time.Duration
+
+	// ShutdownDelay is how long the HTTP server keeps serving, with /ready reporting
+	// it as shutting down, after SIGINT/SIGTERM and before it stops accepting
+	// connections. Zero closes the listener at once.
+	ShutdownDelay time.Duration
+
+	// LogLevel is the minimum level of log lines that are written (debug, info, warn, error).
+	LogLevel string
+
+	// LogFormat is the log output format. Logs always go to stderr, never stdout,
+	// because stdout carries the MCP protocol in STDIO mode.
+	LogFormat LogFormat
+
+	// RateLimitRPS is the sustained number of HTTP requests per second allowed for
+	// each API key. Zero disables request rate limiting.
+	RateLimitRPS float64
+
+	// RateLimitBurst is the number of HTTP requests an API key may send at once
+	// before RateLimitRPS applies.
+	RateLimitBurst int
+
+	// MaxConcurrentToolCalls caps the non-streaming tool calls running at once for
+	// each API key on the HTTP transport. Zero means unlimited.
+	MaxConcurrentToolCalls int
+
+	// MaxConcurrentStreamingCalls caps the streaming tool calls (get_pipeline_build_logs)
+	// running at once for each API key on the HTTP transport. Zero means unlimited.
+	MaxConcurrentStreamingCalls int
+
+	// TracingExporter selects where OpenTelemetry traces are exported. "none" disables
+	// span export; trace context from incoming requests is still propagated.
+	TracingExporter TracingExporter
+
+	// TracingEndpoint is the OTLP collector endpoint (host:port for otlp-grpc, host:port
+	// or URL for otlp-http). Empty means the exporter's default, which honors the
+	// standard OTEL_EXPORTER_OTLP_* environment variables.
+	TracingEndpoint string
+
+	// TracingInsecure disables TLS for the connection to the OTLP collector.
+	TracingInsecure bool
+
+	// TracingSampleRatio is the fraction of new traces that are sampled. Requests that
+	// arrive with a W3C traceparent follow the caller's sampling decision.
+	TracingSampleRatio float64
+
+	// ConfigFile is the config file the configuration was loaded from. Empty when no
+	// config file was used.
+	ConfigFile string
+
+	// Profile is the config file profile the configuration was loaded from. Empty when
+	// no profile was used.
+	Profile string
+
+	// DefaultOrg is the organization ID used when a tool call omits it and its session
+	// has not set one with set_context. Empty means tool calls must name the
+	// organization or set it first.
+	DefaultOrg string
+
+	// DefaultEnv is the Planton environment (e.g. "dev", "prod") used when a tool call
+	// omits it and its session has not set one. Not to be confused with PLANTON_CLOUD_ENVIRONMENT, which selects the
+	// Planton Cloud deployment (live, test, local).
+	DefaultEnv string
+
+	// ReadOnly restricts the server to tools annotated as read-only: no tool that
+	// creates, updates or deletes resources or issues credentials. It takes
+	// precedence over ToolsAllow.
+	ReadOnly bool
+
+	// ToolsAllow lists the tool names or domains (context, commons, infrahub,
+	// resourcemanager, servicehub, connect) that are registered. Empty allows every tool.
+	ToolsAllow []string
+
+	// ToolsDeny lists the tool names or domains that are not registered. It takes
+	// precedence over ToolsAllow.
+	ToolsDeny []string
+}
+
+// LoadOptions selects the config file and profile, and carries the settings given on
+// the command line.
+type LoadOptions struct {
+	// ConfigFile is the config file given with --config. Empty uses PLANTON_MCP_CONFIG,
+	// then the default location (see DefaultConfigFile) if a file exists there.
+	ConfigFile string
+
+	// Profile is the profile given with --profile. Empty uses PLANTON_MCP_PROFILE, then
+	// the file's default_profile, then a profile named "default".
+	Profile string
+
+	// Overrides holds the settings given on the command line, keyed by environment
+	// variable name (e.g. PLANTON_MCP_TRANSPORT). They take precedence over the
+	// environment and the config file.
+	Overrides map[string]string
+
+	// APIKeyOptional skips the API key requirement of the STDIO transport, for
+	// commands that do not call Planton APIs (e.g. "tools list")
+	APIKeyOptional bool
 }
 
-// LoadFromEnv loads configuration from environment variables.
+// ValidationError lists every invalid setting found while loading the configuration.
+type ValidationError struct {
+	Problems []error
+}
+
+// Error returns all problems, one per line.
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString("invalid configuration:")
+	for _, problem := range e.Problems {
+		b.WriteString("\n  - ")
+		b.WriteString(problem.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the individual problems.
+func (e *ValidationError) Unwrap() []error {
+	return e.Problems
+}
+
+// Load loads the server configuration. Each setting is taken from the first of:
+//
+//  1. The command line (opts.Overrides)
+//  2. The environment variable of the setting
+//  3. The selected profile of the config file
+//  4. The default value
 //
-// Required environment variables:
+// Invalid values are not replaced by defaults: Load returns a *ValidationError that
+// lists every invalid setting and where its value came from. Errors reading or
+// parsing the config file, or selecting its profile, are returned on their own.
+//
+// Required settings:
 //   - PLANTON_API_KEY: User's API key for authentication (can be JWT token or API key)
 //     Required for STDIO transport mode (used directly for authentication)
 //     Optional for HTTP transport mode (extracted from Authorization header per-request)
 //
-// Optional environment variables:
-//   - PLANTON_APIS_GRPC_ENDPOINT: Override endpoint (takes precedence)
+// Optional settings:
+//   - PLANTON_MCP_CONFIG: Config file - defaults to $XDG_CONFIG_HOME/planton/mcp.yaml
+//     (~/.config/planton/mcp.yaml) when that file exists
+//   - PLANTON_MCP_PROFILE: Config file profile - defaults to the file's default_profile
+//   - PLANTON_APIS_GRPC_ENDPOINT: Override endpoint (takes precedence over an environment
+//     set at the same or a lower precedence level)
 //   - PLANTON_CLOUD_ENVIRONMENT: Target environment (live, test, local)
 //     Defaults to "live" which uses api.live.planton.cloud:443
 //   - PLANTON_MCP_TRANSPORT: Transport mode (stdio, http, both) - defaults to "stdio"
 //   - PLANTON_MCP_HTTP_PORT: HTTP server port - defaults to "8080"
 //   - PLANTON_MCP_HTTP_AUTH_ENABLED: Enable bearer token auth - defaults to "true"
+//   - PLANTON_MCP_HTTP_EXTERNAL_URL: Public base URL of the HTTP server - defaults to
+//     a relative message endpoint resolved by clients against the SSE URL
+//   - PLANTON_MCP_HTTP_BASE_PATH: Path prefix for MCP endpoints - defaults to root
+//   - PLANTON_MCP_HTTP_ALLOWED_ORIGINS: Comma-separated browser origins allowed to call the
+//     HTTP server ("*" for any) - defaults to none
+//   - PLANTON_MCP_HTTP_BIND_ADDRESS: Interface address to listen on - defaults to all
+//     interfaces, or "127.0.0.1" when HTTP authentication is disabled
+//   - PLANTON_MCP_OAUTH_AUTHORIZATION_SERVERS: Comma-separated authorization server issuer URLs
+//     advertised in the protected resource metadata - defaults to none
+//   - PLANTON_MCP_JWT_JWKS_URL: JWKS URL for local JWT validation - defaults to none (pass-through)
+//   - PLANTON_MCP_JWT_ISSUER: Required JWT issuer - defaults to no check
+//   - PLANTON_MCP_JWT_AUDIENCE: Comma-separated accepted JWT audiences - defaults to no check
+//   - PLANTON_MCP_TOKEN_VALIDATION_ENABLED: Check bearer tokens against Planton APIs before
+//     serving them - defaults to "true"
+//   - PLANTON_MCP_TOKEN_CACHE_TTL: How long accepted tokens are trusted (Go duration) - defaults to "5m"
+//   - PLANTON_MCP_TOKEN_REJECTED_CACHE_TTL: How long rejected tokens stay rejected (Go duration) -
+//     defaults to "1m"
+//   - PLANTON_MCP_TOKEN_CACHE_SIZE: Maximum cached token validation results - defaults to "10000"
+//   - PLANTON_MCP_GRPC_IDLE_TIMEOUT: How long unused pooled gRPC connections stay open
+//     (Go duration, 0 never closes them) - defaults to "5m"
+//   - PLANTON_MCP_GRPC_KEEPALIVE_TIME: Keepalive ping interval (Go duration, 0 disables) - defaults to "30s"
+//   - PLANTON_MCP_GRPC_KEEPALIVE_TIMEOUT: Keepalive ping acknowledgement timeout (Go duration) -
+//     defaults to "10s"
+//   - PLANTON_MCP_GRPC_RETRY_MAX_ATTEMPTS: Total attempts of query RPCs failing with UNAVAILABLE
+//     (0 or 1 disables retries) - defaults to "3"
+//   - PLANTON_MCP_GRPC_RETRY_INITIAL_BACKOFF: Wait before the first retry (Go duration) - defaults to "200ms"
+//   - PLANTON_MCP_GRPC_RETRY_MAX_BACKOFF: Longest wait between retries (Go duration) - defaults to "2s"
+//   - PLANTON_MCP_GRPC_TLS: TLS to Planton APIs (auto, on, off) - defaults to "auto" (TLS for port 443
+//     or when a TLS option is set)
+//   - PLANTON_MCP_GRPC_TLS_CA_FILE: PEM bundle of additional trusted CAs - defaults to the system roots only
+//   - PLANTON_MCP_GRPC_TLS_CERT_FILE, PLANTON_MCP_GRPC_TLS_KEY_FILE: Client certificate and key for
+//     mutual TLS - defaults to none
+//   - PLANTON_MCP_GRPC_TLS_SERVER_NAME: Server name verified in the certificate - defaults to the endpoint host
+//   - PLANTON_MCP_TOOL_TIMEOUT: Deadline of a tool call (Go duration, 0 disables) - defaults to "30s"
+//   - PLANTON_MCP_TOOL_TIMEOUTS: Per-tool deadlines as comma-separated tool=duration entries,
+//     e.g. "get_pipeline_build_logs=2m" - defaults to none
+//   - PLANTON_MCP_SHUTDOWN_TIMEOUT: Graceful shutdown timeout (Go duration) - defaults to "30s"
+//   - PLANTON_MCP_SHUTDOWN_DELAY: How long /ready reports not ready before the HTTP listener
+//     closes (Go duration, 0 disables) - defaults to "5s"
+//   - PLANTON_MCP_LOG_LEVEL: Minimum log level (debug, info, warn, error) - defaults to "info"
+//   - PLANTON_MCP_LOG_FORMAT: Log format (text, json) - defaults to "text"
+//   - PLANTON_MCP_RATE_LIMIT_RPS: HTTP requests per second per API key (0 disables) - defaults to "10"
+//   - PLANTON_MCP_RATE_LIMIT_BURST: HTTP request burst per API key - defaults to "20"
+//   - PLANTON_MCP_MAX_CONCURRENT_TOOL_CALLS: Concurrent tool calls per API key (0 is unlimited) - defaults to "10"
+//   - PLANTON_MCP_MAX_CONCURRENT_STREAMING_CALLS: Concurrent streaming tool calls per API key
+//     (0 is unlimited) - defaults to "2"
+//   - PLANTON_MCP_TRACING_EXPORTER: Trace exporter (none, otlp-grpc, otlp-http) - defaults to "none"
+//   - PLANTON_MCP_TRACING_ENDPOINT: OTLP collector endpoint - defaults to the exporter's default
+//   - PLANTON_MCP_TRACING_INSECURE: Disable TLS to the OTLP collector - defaults to "false"
+//   - PLANTON_MCP_TRACING_SAMPLE_RATIO: Fraction of new traces sampled - defaults to "1.0"
+//   - PLANTON_MCP_DEFAULT_ORG, PLANTON_MCP_DEFAULT_ENV: Organization and environment used when a
+//     tool call omits them - defaults to none
+//   - PLANTON_MCP_READ_ONLY: Register only tools that do not modify resources - defaults to "false"
+//   - PLANTON_MCP_TOOLS_ALLOW, PLANTON_MCP_TOOLS_DENY: Comma-separated tool names or domains that
+//     are or are not registered - defaults to all tools
 //
-// For STDIO mode, PLANTON_API_KEY from environment is used for all gRPC calls.
+// For STDIO mode, PLANTON_API_KEY is used for all gRPC calls.
 // For HTTP mode, PLANTON_API_KEY from Authorization header is extracted per-request,
 // enabling proper multi-user support with Fine-Grained Authorization.
-func LoadFromEnv() (*Config, error) {
-	apiKey := os.Getenv(APIKeyEnvVar)
-	transport := getTransport()
+func Load(opts LoadOptions) (*Config, error) {
+	l := &loader{overrides: opts.Overrides, apiKeyOptional: opts.APIKeyOptional}
+	if err := l.loadFile(opts); err != nil {
+		return nil, err
+	}
 
-	// For STDIO mode, API key is required (used directly for authentication)
-	if transport == TransportStdio && apiKey == "" {
-		return nil, fmt.Errorf(
-			"%s environment variable required for STDIO transport. "+
-				"This should be set by LangGraph when spawning MCP server",
-			APIKeyEnvVar,
-		)
+	cfg := l.load()
+	if len(l.problems) > 0 {
+		return nil, &ValidationError{Problems: l.problems}
 	}
+	return cfg, nil
+}
+
+// loader resolves settings by precedence and collects every invalid value, so that
+// all of them are reported at once.
+type loader struct {
+	// overrides holds the settings given on the command line
+	overrides map[string]string
 
-	// For HTTP mode, API key is optional (extracted from Authorization header)
-	// If provided, it can be used as a fallback or default key
-	if transport == TransportHTTP && apiKey == "" {
-		// Log that we're in HTTP mode without default API key
-		// This is normal - API keys will come from HTTP Authorization headers
+	// apiKeyOptional skips the API key requirement of the STDIO transport
+	apiKeyOptional bool
+
+	// configFile and profileName identify the selected profile; profile holds its
+	// settings keyed by environment variable name
+	configFile  string
+	profileName string
+	profile     map[string]profileValue
+
+	problems []error
+
+	// invalidSettings holds the settings with a recorded problem, so that checks
+	// combining several settings skip them
+	invalidSettings map[string]bool
+}
+
+// Setting sources, from lowest to highest precedence
+const (
+	sourceDefault = iota
+	sourceProfile
+	sourceEnvironment
+	sourceCommandLine
+)
+
+// source returns where the value of a setting comes from
+func (l *loader) source(name string) int {
+	if _, ok := l.overrides[name]; ok {
+		return sourceCommandLine
+	}
+	if os.Getenv(name) != "" {
+		return sourceEnvironment
+	}
+	if _, ok := l.profile[name]; ok {
+		return sourceProfile
+	}
+	return sourceDefault
+}
+
+// get returns the raw value of a setting from its highest-precedence source, or an
+// empty string when it is not set
+func (l *loader) get(name string) string {
+	switch l.source(name) {
+	case sourceCommandLine:
+		return l.overrides[name]
+	case sourceEnvironment:
+		return os.Getenv(name)
+	case sourceProfile:
+		value := l.profile[name]
+		if value.err != nil {
+			l.problem(name, "%s: %v", l.origin(name), value.err)
+			return ""
+		}
+		return value.value
+	default:
+		return ""
+	}
+}
+
+// getTrimmed returns the value of a setting without surrounding whitespace
+func (l *loader) getTrimmed(name string) string {
+	return strings.TrimSpace(l.get(name))
+}
+
+// origin describes where the value of a setting comes from, for error messages
+func (l *loader) origin(name string) string {
+	switch l.source(name) {
+	case sourceCommandLine:
+		return "command line"
+	case sourceEnvironment:
+		return "environment"
+	case sourceProfile:
+		return fmt.Sprintf("%s in profile %q of %s", l.profile[name].key, l.profileName, l.configFile)
+	default:
+		return "default"
+	}
+}
+
+// problem records a problem with a setting
+func (l *loader) problem(name, format string, args ...any) {
+	if l.invalidSettings == nil {
+		l.invalidSettings = make(map[string]bool)
+	}
+	l.invalidSettings[name] = true
+	l.problems = append(l.problems, fmt.Errorf(format, args...))
+}
+
+// invalid records an invalid value of a setting, naming the setting and the source of
+// the value
+func (l *loader) invalid(name, raw, reason string) {
+	l.problem(name, "%s %q (from %s): %s", name, raw, l.origin(name), reason)
+}
+
+// valid reports whether no problem was recorded for any of the settings
+func (l *loader) valid(names ...string) bool {
+	for _, name := range names {
+		if l.invalidSettings[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// load resolves every setting into a Config
+func (l *loader) load() *Config {
+	transport := l.getTransport()
+	apiKey := l.get(APIKeyEnvVar)
+
+	// STDIO needs a key of its own; HTTP takes it from the Authorization header of
+	// each request, and uses a configured key only as a default
+	if apiKey == "" && transport != TransportHTTP && !l.apiKeyOptional && l.valid(TransportEnvVar, APIKeyEnvVar) {
+		l.problem(APIKeyEnvVar,
+			"%s is required for the %s transport: set it in the environment (MCP clients such as "+
+				"LangGraph pass it when spawning the server), or with api_key_env or api_key_file in a config file profile",
+			APIKeyEnvVar, transport)
+	}
+
+	httpAuthEnabled := l.getBool(HTTPAuthEnabledEnvVar, true) // Default to enabled for security
+
+	jwksURL := l.getJWTJWKSURL()
+	jwtIssuer := l.getTrimmed(JWTIssuerEnvVar)
+	jwtAudience := l.getList(JWTAudienceEnvVar)
+	if jwksURL == "" && (jwtIssuer != "" || len(jwtAudience) > 0) && l.valid(JWTJWKSURLEnvVar) {
+		l.problem(JWTJWKSURLEnvVar, "%s and %s require %s to be set", JWTIssuerEnvVar, JWTAudienceEnvVar, JWTJWKSURLEnvVar)
+	}
+
+	grpcKeepaliveTimeout := l.getNonNegativeDuration(GRPCKeepaliveTimeoutEnvVar, DefaultGRPCKeepaliveTimeout)
+	if grpcKeepaliveTimeout == 0 && l.valid(GRPCKeepaliveTimeoutEnvVar) {
+		l.invalid(GRPCKeepaliveTimeoutEnvVar, l.get(GRPCKeepaliveTimeoutEnvVar), "must be greater than zero")
 	}
 
-	// For both mode, API key is required for STDIO transport
-	if transport == TransportBoth && apiKey == "" {
-		return nil, fmt.Errorf(
-			"%s environment variable required for STDIO transport in dual-transport mode",
-			APIKeyEnvVar,
-		)
+	grpcRetryInitialBackoff := l.getNonNegativeDuration(GRPCRetryInitialBackoffEnvVar, DefaultGRPCRetryInitialBackoff)
+	grpcRetryMaxBackoff := l.getNonNegativeDuration(GRPCRetryMaxBackoffEnvVar, DefaultGRPCRetryMaxBackoff)
+	if grpcRetryMaxBackoff < grpcRetryInitialBackoff && l.valid(GRPCRetryInitialBackoffEnvVar, GRPCRetryMaxBackoffEnvVar) {
+		l.invalid(GRPCRetryMaxBackoffEnvVar, grpcRetryMaxBackoff.String(),
+			fmt.Sprintf("must not be less than %s (%s)", GRPCRetryInitialBackoffEnvVar, grpcRetryInitialBackoff))
 	}
 
-	endpoint := getEndpoint()
-	httpPort := getHTTPPort()
-	httpAuthEnabled := getHTTPAuthEnabled()
+	grpcTLS := l.getGRPCTLS()
 
 	return &Config{
-		PlantonAPIKey:           apiKey,
-		PlantonAPIsGRPCEndpoint: endpoint,
-		Transport:               transport,
-		HTTPPort:                httpPort,
-		HTTPAuthEnabled:         httpAuthEnabled,
-	}, nil
+		PlantonAPIKey:               apiKey,
+		PlantonAPIsGRPCEndpoint:     l.getEndpoint(),
+		Transport:                   transport,
+		HTTPPort:                    l.getHTTPPort(),
+		HTTPAuthEnabled:             httpAuthEnabled,
+		HTTPExternalURL:             l.getHTTPExternalURL(),
+		HTTPBasePath:                l.getHTTPBasePath(),
+		HTTPAllowedOrigins:          l.getHTTPAllowedOrigins(),
+		HTTPBindAddress:             l.getHTTPBindAddress(httpAuthEnabled),
+		OAuthAuthorizationServers:   l.getURLList(OAuthAuthorizationServersEnvVar),
+		JWTJWKSURL:                  jwksURL,
+		JWTIssuer:                   jwtIssuer,
+		JWTAudience:                 jwtAudience,
+		TokenValidationEnabled:      l.getBool(TokenValidationEnabledEnvVar, true),
+		TokenCacheTTL:               l.getNonNegativeDuration(TokenCacheTTLEnvVar, DefaultTokenCacheTTL),
+		TokenRejectedCacheTTL:       l.getNonNegativeDuration(TokenRejectedCacheTTLEnvVar, DefaultTokenRejectedCacheTTL),
+		TokenCacheSize:              l.getNonNegativeInt(TokenCacheSizeEnvVar, DefaultTokenCacheSize),
+		GRPCIdleTimeout:             l.getNonNegativeDuration(GRPCIdleTimeoutEnvVar, DefaultGRPCIdleTimeout),
+		GRPCKeepaliveTime:           l.getNonNegativeDuration(GRPCKeepaliveTimeEnvVar, DefaultGRPCKeepaliveTime),
+		GRPCKeepaliveTimeout:        grpcKeepaliveTimeout,
+		GRPCRetryMaxAttempts:        l.getNonNegativeInt(GRPCRetryMaxAttemptsEnvVar, DefaultGRPCRetryMaxAttempts),
+		GRPCRetryInitialBackoff:     grpcRetryInitialBackoff,
+		GRPCRetryMaxBackoff:         grpcRetryMaxBackoff,
+		GRPCTLSMode:                 grpcTLS.mode,
+		GRPCTLSCAFile:               grpcTLS.caFile,
+		GRPCTLSCertFile:             grpcTLS.certFile,
+		GRPCTLSKeyFile:              grpcTLS.keyFile,
+		GRPCTLSServerName:           grpcTLS.serverName,
+		ToolTimeout:                 l.getNonNegativeDuration(ToolTimeoutEnvVar, DefaultToolTimeout),
+		ToolTimeouts:                l.getToolTimeouts(),
+		ShutdownTimeout:             l.getShutdownTimeout(),
+		ShutdownDelay:               l.getNonNegativeDuration(ShutdownDelayEnvVar, DefaultShutdownDelay),
+		LogLevel:                    l.getLogLevel(),
+		LogFormat:                   l.getLogFormat(),
+		RateLimitRPS:                l.getRateLimitRPS(),
+		RateLimitBurst:              l.getNonNegativeInt(RateLimitBurstEnvVar, DefaultRateLimitBurst),
+		MaxConcurrentToolCalls:      l.getNonNegativeInt(MaxConcurrentToolCallsEnvVar, DefaultMaxConcurrentToolCalls),
+		MaxConcurrentStreamingCalls: l.getNonNegativeInt(MaxConcurrentStreamingCallsEnvVar, DefaultMaxConcurrentStreamingCalls),
+		TracingExporter:             l.getTracingExporter(),
+		TracingEndpoint:             l.getTrimmed(TracingEndpointEnvVar),
+		TracingInsecure:             l.getBool(TracingInsecureEnvVar, false),
+		TracingSampleRatio:          l.getTracingSampleRatio(),
+		ConfigFile:                  l.configFile,
+		Profile:                     l.profileName,
+		DefaultOrg:                  l.getTrimmed(DefaultOrgEnvVar),
+		DefaultEnv:                  l.getTrimmed(DefaultEnvEnvVar),
+		ReadOnly:                    l.getBool(ReadOnlyEnvVar, false),
+		ToolsAllow:                  l.getList(ToolsAllowEnvVar),
+		ToolsDeny:                   l.getList(ToolsDenyEnvVar),
+	}
 }
 
-// getEndpoint determines the gRPC endpoint to use based on environment variables.
+// getEndpoint determines the gRPC endpoint to use.
 // Priority:
-// 1. PLANTON_APIS_GRPC_ENDPOINT (explicit override)
+// 1. PLANTON_APIS_GRPC_ENDPOINT (explicit override), unless PLANTON_CLOUD_ENVIRONMENT
+// is set at a higher precedence level (e.g. the environment over a profile's endpoint)
 // 2. PLANTON_CLOUD_ENVIRONMENT (environment-based selection)
 // 3. Default to "live" environment (api.live.planton.cloud:443)
-func getEndpoint() string {
+func (l *loader) getEndpoint() string {
+	env := l.getEnvironment()
+
 	// Check for explicit endpoint override first
-	if endpoint := os.Getenv(EndpointOverrideEnvVar); endpoint != "" {
-		return endpoint
+	if l.source(EndpointOverrideEnvVar) >= l.source(EnvironmentEnvVar) {
+		if endpoint := l.getTrimmed(EndpointOverrideEnvVar); endpoint != "" {
+			return endpoint
+		}
 	}
 
-	// Determine environment and return corresponding endpoint
-	env := getEnvironment()
+	// Return the endpoint of the environment
 	switch env {
 	case EnvironmentTest:
 		return TestEndpoint
 	case EnvironmentLocal:
 		return LocalEndpoint
-	case EnvironmentLive:
-		fallthrough
 	default:
 		return LiveEndpoint
 	}
 }
 
 // getEnvironment returns the configured environment, defaulting to "live"
-func getEnvironment() Environment {
-	envStr := os.Getenv(EnvironmentEnvVar)
-	if envStr == "" {
+func (l *loader) getEnvironment() Environment {
+	raw := l.getTrimmed(EnvironmentEnvVar)
+	if raw == "" {
 		return EnvironmentLive
 	}
 
-	env := Environment(envStr)
+	env := Environment(strings.ToLower(raw))
 	switch env {
 	case EnvironmentLive, EnvironmentTest, EnvironmentLocal:
 		return env
 	default:
+		l.invalid(EnvironmentEnvVar, raw, "expected live, test or local")
 		return EnvironmentLive
 	}
 }
 
 // getTransport returns the configured transport mode, defaulting to "stdio"
-func getTransport() TransportMode {
-	transportStr := os.Getenv(TransportEnvVar)
-	if transportStr == "" {
+func (l *loader) getTransport() TransportMode {
+	raw := l.getTrimmed(TransportEnvVar)
+	if raw == "" {
 		return TransportStdio
 	}
 
-	transport := TransportMode(transportStr)
+	transport := TransportMode(strings.ToLower(raw))
 	switch transport {
 	case TransportStdio, TransportHTTP, TransportBoth:
 		return transport
 	default:
+		l.invalid(TransportEnvVar, raw, "expected stdio, http or both")
 		return TransportStdio
 	}
 }
 
 // getHTTPPort returns the configured HTTP port, defaulting to "8080"
-func getHTTPPort() string {
-	port := os.Getenv(HTTPPortEnvVar)
-	if port == "" {
+func (l *loader) getHTTPPort() string {
+	raw := l.getTrimmed(HTTPPortEnvVar)
+	if raw == "" {
+		return DefaultHTTPPort
+	}
+
+	port, err := strconv.Atoi(raw)
+	if err != nil || port < 1 || port > 65535 {
+		l.invalid(HTTPPortEnvVar, raw, "expected a port number between 1 and 65535")
 		return DefaultHTTPPort
 	}
-	return port
+	return raw
+}
+
+// getBool returns a boolean setting ("true"/"1" or "false"/"0"), or defaultValue when
+// it is not set
+func (l *loader) getBool(name string, defaultValue bool) bool {
+	raw := l.getTrimmed(name)
+	switch strings.ToLower(raw) {
+	case "":
+		return defaultValue
+	case "true", "1":
+		return true
+	case "false", "0":
+		return false
+	default:
+		l.invalid(name, raw, "expected true or false")
+		return defaultValue
+	}
+}
+
+// getHTTPExternalURL returns the configured external base URL without a trailing slash.
+// The URL must be absolute (http or https) and must not contain a query string; any
+// path prefix belongs in PLANTON_MCP_HTTP_BASE_PATH.
+func (l *loader) getHTTPExternalURL() string {
+	raw := l.getTrimmed(HTTPExternalURLEnvVar)
+	if raw == "" {
+		return ""
+	}
+
+	u, err := url.Parse(raw)
+	switch {
+	case err != nil:
+		l.invalid(HTTPExternalURLEnvVar, raw, err.Error())
+	case u.Scheme != "http" && u.Scheme != "https":
+		l.invalid(HTTPExternalURLEnvVar, raw, "scheme must be http or https")
+	case u.Host == "" || strings.HasPrefix(u.Host, ":"):
+		l.invalid(HTTPExternalURLEnvVar, raw, "host is required")
+	case u.RawQuery != "" || u.Fragment != "":
+		l.invalid(HTTPExternalURLEnvVar, raw, "query and fragment are not allowed")
+	case strings.Trim(u.Path, "/") != "":
+		l.invalid(HTTPExternalURLEnvVar, raw, "set the path prefix with "+HTTPBasePathEnvVar+" instead")
+	default:
+		return u.Scheme + "://" + u.Host
+	}
+	return ""
+}
+
+// getHTTPBasePath returns the configured path prefix normalized to "/prefix" form,
+// or an empty string when MCP endpoints are served at the root.
+func (l *loader) getHTTPBasePath() string {
+	basePath := strings.Trim(l.getTrimmed(HTTPBasePathEnvVar), "/")
+	if basePath == "" {
+		return ""
+	}
+	return "/" + basePath
+}
+
+// getHTTPAllowedOrigins returns the configured allowed browser origins, normalized to
+// scheme://host[:port] in lower case. Each entry must be "*" or an http(s) origin
+// without a path.
+func (l *loader) getHTTPAllowedOrigins() []string {
+	var origins []string
+	for _, entry := range l.getList(HTTPAllowedOriginsEnvVar) {
+		if entry == "*" {
+			origins = append(origins, entry)
+			continue
+		}
+
+		u, err := url.Parse(entry)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			l.invalid(HTTPAllowedOriginsEnvVar, entry, "expected an origin such as https://inspector.example.com, or *")
+			continue
+		}
+		if strings.Trim(u.Path, "/") != "" || u.RawQuery != "" || u.Fragment != "" {
+			l.invalid(HTTPAllowedOriginsEnvVar, entry, "an origin has no path, query or fragment")
+			continue
+		}
+		origins = append(origins, strings.ToLower(u.Scheme+"://"+u.Host))
+	}
+	return origins
+}
+
+// getHTTPBindAddress returns the configured HTTP bind address. When it is not set, the
+// server listens on all interfaces if authentication is enabled and on 127.0.0.1 only
+// if it is disabled.
+func (l *loader) getHTTPBindAddress(authEnabled bool) string {
+	raw := l.getTrimmed(HTTPBindAddressEnvVar)
+	if raw == "" {
+		if authEnabled {
+			return ""
+		}
+		return LocalhostBindAddress
+	}
+
+	if _, _, err := net.SplitHostPort(raw); err == nil {
+		l.invalid(HTTPBindAddressEnvVar, raw, "expected an address without a port (set the port with "+HTTPPortEnvVar+")")
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(raw, "["), "]")
+}
+
+// getList returns the non-empty entries of a comma-separated setting
+func (l *loader) getList(name string) []string {
+	var values []string
+	for _, entry := range strings.Split(l.get(name), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			values = append(values, entry)
+		}
+	}
+	return values
+}
+
+// getURLList returns the entries of a comma-separated list of absolute http(s) URLs
+func (l *loader) getURLList(name string) []string {
+	var urls []string
+	for _, entry := range l.getList(name) {
+		if err := validateAbsoluteURL(entry); err != nil {
+			l.invalid(name, entry, err.Error())
+			continue
+		}
+		urls = append(urls, entry)
+	}
+	return urls
+}
+
+// getJWTJWKSURL returns the configured JWKS URL, or an empty string when local JWT
+// validation is disabled
+func (l *loader) getJWTJWKSURL() string {
+	raw := l.getTrimmed(JWTJWKSURLEnvVar)
+	if raw == "" {
+		return ""
+	}
+	if err := validateAbsoluteURL(raw); err != nil {
+		l.invalid(JWTJWKSURLEnvVar, raw, err.Error())
+		return ""
+	}
+	return raw
+}
+
+// validateAbsoluteURL checks that raw is an absolute http or https URL
+func validateAbsoluteURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("scheme must be http or https")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("host is required")
+	}
+	return nil
+}
+
+// getNonNegativeDuration returns a non-negative Go duration setting, or defaultValue
+// when it is not set
+func (l *loader) getNonNegativeDuration(name string, defaultValue time.Duration) time.Duration {
+	raw := l.getTrimmed(name)
+	if raw == "" {
+		return defaultValue
+	}
+
+	value, err := time.ParseDuration(raw)
+	if err != nil || value < 0 {
+		l.invalid(name, raw, `expected a non-negative duration such as "5m"`)
+		return defaultValue
+	}
+	return value
+}
+
+// grpcTLSSettings is the TLS configuration of connections to Planton APIs
+type grpcTLSSettings struct {
+	mode       GRPCTLSMode
+	caFile     string
+	certFile   string
+	keyFile    string
+	serverName string
+}
+
+// getGRPCTLS returns the TLS configuration of connections to Planton APIs, defaulting
+// to auto mode with the system roots. Files are only read when the connection pool is
+// set up.
+func (l *loader) getGRPCTLS() grpcTLSSettings {
+	settings := grpcTLSSettings{
+		mode:       GRPCTLSAuto,
+		caFile:     l.getTrimmed(GRPCTLSCAFileEnvVar),
+		certFile:   l.getTrimmed(GRPCTLSCertFileEnvVar),
+		keyFile:    l.getTrimmed(GRPCTLSKeyFileEnvVar),
+		serverName: l.getTrimmed(GRPCTLSServerNameEnvVar),
+	}
+
+	if raw := l.getTrimmed(GRPCTLSEnvVar); raw != "" {
+		mode := GRPCTLSMode(strings.ToLower(raw))
+		switch mode {
+		case GRPCTLSAuto, GRPCTLSOn, GRPCTLSOff:
+			settings.mode = mode
+		default:
+			l.invalid(GRPCTLSEnvVar, raw, "expected auto, on or off")
+		}
+	}
+
+	if (settings.certFile == "") != (settings.keyFile == "") {
+		l.problem(GRPCTLSCertFileEnvVar, "invalid TLS client certificate: %s and %s must be set together",
+			GRPCTLSCertFileEnvVar, GRPCTLSKeyFileEnvVar)
+	}
+	if settings.mode == GRPCTLSOff && (settings.caFile != "" || settings.certFile != "" || settings.serverName != "") {
+		l.invalid(GRPCTLSEnvVar, string(settings.mode), "TLS options are set but TLS is disabled")
+	}
+	return settings
+}
+
+// getToolTimeouts returns the per-tool timeouts configured as comma-separated
+// tool=duration entries
+func (l *loader) getToolTimeouts() map[string]time.Duration {
+	timeouts := make(map[string]time.Duration)
+	for _, entry := range l.getList(ToolTimeoutsEnvVar) {
+		tool, raw, ok := strings.Cut(entry, "=")
+		tool = strings.TrimSpace(tool)
+		timeout, err := time.ParseDuration(strings.TrimSpace(raw))
+		if !ok || tool == "" || err != nil || timeout < 0 {
+			l.invalid(ToolTimeoutsEnvVar, entry, `expected tool=duration such as "get_pipeline_build_logs=2m"`)
+			continue
+		}
+		timeouts[tool] = timeout
+	}
+	return timeouts
+}
+
+// getShutdownTimeout returns the configured graceful shutdown timeout, defaulting to 30s
+func (l *loader) getShutdownTimeout() time.Duration {
+	raw := l.getTrimmed(ShutdownTimeoutEnvVar)
+	if raw == "" {
+		return DefaultShutdownTimeout
+	}
+
+	timeout, err := time.ParseDuration(raw)
+	if err != nil || timeout <= 0 {
+		l.invalid(ShutdownTimeoutEnvVar, raw, `expected a positive duration such as "30s"`)
+		return DefaultShutdownTimeout
+	}
+	return timeout
+}
+
+// getLogLevel returns the configured log level, defaulting to "info"
+func (l *loader) getLogLevel() string {
+	raw := l.getTrimmed(LogLevelEnvVar)
+	if raw == "" {
+		return DefaultLogLevel
+	}
+
+	level := strings.ToLower(raw)
+	switch level {
+	case LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError:
+		return level
+	case "warning":
+		return LogLevelWarn
+	default:
+		l.invalid(LogLevelEnvVar, raw, "expected one of debug, info, warn, error")
+		return DefaultLogLevel
+	}
+}
+
+// getLogFormat returns the configured log format, defaulting to "text"
+func (l *loader) getLogFormat() LogFormat {
+	raw := l.getTrimmed(LogFormatEnvVar)
+	if raw == "" {
+		return DefaultLogFormat
+	}
+
+	format := LogFormat(strings.ToLower(raw))
+	switch format {
+	case LogFormatText, LogFormatJSON:
+		return format
+	default:
+		l.invalid(LogFormatEnvVar, raw, "expected text or json")
+		return DefaultLogFormat
+	}
+}
+
+// getRateLimitRPS returns the configured per-API-key request rate, defaulting to 10
+func (l *loader) getRateLimitRPS() float64 {
+	raw := l.getTrimmed(RateLimitRPSEnvVar)
+	if raw == "" {
+		return DefaultRateLimitRPS
+	}
+
+	rps, err := strconv.ParseFloat(raw, 64)
+	if err != nil || rps < 0 {
+		l.invalid(RateLimitRPSEnvVar, raw, "expected a non-negative number (0 disables rate limiting)")
+		return DefaultRateLimitRPS
+	}
+	return rps
+}
+
+// getNonNegativeInt returns the integer value of a setting, or defaultValue when it is
+// not set
+func (l *loader) getNonNegativeInt(name string, defaultValue int) int {
+	raw := l.getTrimmed(name)
+	if raw == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		l.invalid(name, raw, "expected a non-negative integer")
+		return defaultValue
+	}
+	return value
 }
 
-// getHTTPAuthEnabled returns whether HTTP authentication is enabled, defaulting to true
-func getHTTPAuthEnabled() bool {
-	authStr := os.Getenv(HTTPAuthEnabledEnvVar)
-	if authStr == "" {
-		return true // Default to enabled for security
+// getTracingExporter returns the configured trace exporter, defaulting to "none"
+func (l *loader) getTracingExporter() TracingExporter {
+	raw := l.getTrimmed(TracingExporterEnvVar)
+	if raw == "" {
+		return DefaultTracingExporter
+	}
+
+	exporter := TracingExporter(strings.ToLower(raw))
+	switch exporter {
+	case TracingExporterNone, TracingExporterOTLPGRPC, TracingExporterOTLPHTTP:
+		return exporter
+	default:
+		l.invalid(TracingExporterEnvVar, raw, "expected one of none, otlp-grpc, otlp-http")
+		return DefaultTracingExporter
+	}
+}
+
+// getTracingSampleRatio returns the configured trace sample ratio, defaulting to 1.0
+func (l *loader) getTracingSampleRatio() float64 {
+	raw := l.getTrimmed(TracingSampleRatioEnvVar)
+	if raw == "" {
+		return DefaultTracingSample
+	}
+
+	ratio, err := strconv.ParseFloat(raw, 64)
+	if err != nil || ratio < 0 || ratio > 1 {
+		l.invalid(TracingSampleRatioEnvVar, raw, "expected a number between 0.0 and 1.0")
+		return DefaultTracingSample
 	}
-	return authStr == "true" || authStr == "1"
+	return ratio
 }
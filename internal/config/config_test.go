@@ -0,0 +1,311 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// isolateEnv clears every setting from the environment and points the default
+// config file location at an empty directory.
+func isolateEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range append(fileSettings, ConfigFileEnvVar, ProfileEnvVar) {
+		t.Setenv(name, "")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+}
+
+// writeConfig writes a config file to a temporary directory and returns its path.
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "mcp.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const testConfigFile = `
+default_profile: prod
+profiles:
+  prod:
+    environment: test
+    api_key: prod-key
+    transport: http
+    default_org: acme
+    tools:
+      read_only: true
+      deny: [connect]
+    settings:
+      PLANTON_MCP_LOG_LEVEL: debug
+      PLANTON_MCP_HTTP_PORT: 9090
+  local:
+    endpoint: localhost:9000
+    api_key_file: local.key
+`
+
+func TestLoadDefaults(t *testing.T) {
+	isolateEnv(t)
+	t.Setenv(APIKeyEnvVar, "env-key")
+
+	cfg, err := Load(LoadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.PlantonAPIsGRPCEndpoint != LiveEndpoint || cfg.Transport != TransportStdio || cfg.HTTPPort != DefaultHTTPPort {
+		t.Errorf("endpoint, transport, port = %q, %q, %q; want defaults", cfg.PlantonAPIsGRPCEndpoint, cfg.Transport, cfg.HTTPPort)
+	}
+	if cfg.ConfigFile != "" || cfg.Profile != "" {
+		t.Errorf("config file, profile = %q, %q; want none", cfg.ConfigFile, cfg.Profile)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	isolateEnv(t)
+	path := writeConfig(t, testConfigFile)
+	t.Setenv(LogLevelEnvVar, "warn")
+	t.Setenv(HTTPPortEnvVar, "7070")
+
+	cfg, err := Load(LoadOptions{
+		ConfigFile: path,
+		Overrides:  map[string]string{HTTPPortEnvVar: "6060"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Profile != "prod" || cfg.ConfigFile != path {
+		t.Errorf("profile = %q of %q, want prod of %q", cfg.Profile, cfg.ConfigFile, path)
+	}
+	if cfg.HTTPPort != "6060" {
+		t.Errorf("HTTPPort = %q, want the command-line value 6060", cfg.HTTPPort)
+	}
+	if cfg.LogLevel != LogLevelWarn {
+		t.Errorf("LogLevel = %q, want the environment value warn", cfg.LogLevel)
+	}
+	if cfg.Transport != TransportHTTP || cfg.PlantonAPIKey != "prod-key" || cfg.PlantonAPIsGRPCEndpoint != TestEndpoint {
+		t.Errorf("transport, key, endpoint = %q, %q, %q; want the profile values", cfg.Transport, cfg.PlantonAPIKey, cfg.PlantonAPIsGRPCEndpoint)
+	}
+	if cfg.DefaultOrg != "acme" || !cfg.ReadOnly || len(cfg.ToolsDeny) != 1 || cfg.ToolsDeny[0] != "connect" {
+		t.Errorf("default org, read-only, deny = %q, %v, %v; want the profile values", cfg.DefaultOrg, cfg.ReadOnly, cfg.ToolsDeny)
+	}
+}
+
+func TestLoadEnvironmentOverridesProfileEndpoint(t *testing.T) {
+	isolateEnv(t)
+	path := writeConfig(t, testConfigFile)
+	if err := os.WriteFile(filepath.Join(filepath.Dir(path), "local.key"), []byte("local-key\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(LoadOptions{ConfigFile: path, Profile: "local"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.PlantonAPIsGRPCEndpoint != "localhost:9000" || cfg.PlantonAPIKey != "local-key" {
+		t.Errorf("endpoint, key = %q, %q; want the profile values", cfg.PlantonAPIsGRPCEndpoint, cfg.PlantonAPIKey)
+	}
+
+	// An environment set in the environment wins over the profile's endpoint
+	t.Setenv(EnvironmentEnvVar, "test")
+	cfg, err = Load(LoadOptions{ConfigFile: path, Profile: "local"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.PlantonAPIsGRPCEndpoint != TestEndpoint {
+		t.Errorf("endpoint = %q, want %q", cfg.PlantonAPIsGRPCEndpoint, TestEndpoint)
+	}
+}
+
+func TestLoadProfileSelection(t *testing.T) {
+	twoProfiles := "profiles:\n  a:\n    api_key: a\n  b:\n    api_key: b\n"
+
+	tests := []struct {
+		name    string
+		content string
+		profile string
+		envVar  string
+		want    string
+		wantErr string
+	}{
+		{name: "default_profile", content: testConfigFile, want: "prod"},
+		{name: "flag", content: testConfigFile, profile: "local", want: "local"},
+		{name: "environment", content: twoProfiles, envVar: "b", want: "b"},
+		{name: "profile named default", content: "profiles:\n  default:\n    api_key: k\n  other: {}\n", want: DefaultProfileName},
+		{name: "unknown profile", content: twoProfiles, profile: "c", wantErr: `profile "c" not found`},
+		{name: "no profile selected", content: twoProfiles, wantErr: "select one of a, b"},
+		{name: "unknown key", content: "profiles:\n  a:\n    enviroment: test\n", wantErr: "field enviroment not found"},
+		{name: "empty file", content: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolateEnv(t)
+			t.Setenv(APIKeyEnvVar, "env-key")
+			t.Setenv(ProfileEnvVar, tt.envVar)
+			path := writeConfig(t, tt.content)
+
+			cfg, err := Load(LoadOptions{ConfigFile: path, Profile: tt.profile})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Profile != tt.want {
+				t.Errorf("profile = %q, want %q", cfg.Profile, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFileLocation(t *testing.T) {
+	isolateEnv(t)
+	t.Setenv(APIKeyEnvVar, "env-key")
+
+	// A missing file at the default location is not an error
+	if _, err := Load(LoadOptions{}); err != nil {
+		t.Fatalf("Load() without a config file: %v", err)
+	}
+
+	// A missing file given explicitly is
+	missing := filepath.Join(t.TempDir(), "missing.yaml")
+	if _, err := Load(LoadOptions{ConfigFile: missing}); err == nil {
+		t.Error("Load() with a missing --config file succeeded, want an error")
+	}
+	t.Setenv(ConfigFileEnvVar, missing)
+	if _, err := Load(LoadOptions{}); err == nil {
+		t.Errorf("Load() with a missing %s file succeeded, want an error", ConfigFileEnvVar)
+	}
+
+	// The default location is used when it exists
+	t.Setenv(ConfigFileEnvVar, "")
+	defaultFile := DefaultConfigFile()
+	if err := os.MkdirAll(filepath.Dir(defaultFile), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(defaultFile, []byte(testConfigFile), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(LoadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ConfigFile != defaultFile || cfg.Profile != "prod" {
+		t.Errorf("profile = %q of %q, want prod of %q", cfg.Profile, cfg.ConfigFile, defaultFile)
+	}
+}
+
+func TestLoadReportsAllProblems(t *testing.T) {
+	isolateEnv(t)
+	path := writeConfig(t, `
+profiles:
+  default:
+    api_key: k
+    api_key_env: PLANTON_TEST_KEY
+    tls:
+      mode: sometimes
+    settings:
+      PLANTON_MCP_LOG_LEVEL: loud
+      PLANTON_MCP_UNKNOWN: x
+`)
+	t.Setenv(TransportEnvVar, "htp")
+	t.Setenv(EnvironmentEnvVar, "staging")
+	t.Setenv(HTTPAuthEnabledEnvVar, "yes")
+
+	_, err := Load(LoadOptions{ConfigFile: path, Overrides: map[string]string{HTTPPortEnvVar: "99999"}})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Load() error = %v, want a *ValidationError", err)
+	}
+
+	want := []string{
+		"api_key, api_key_env and api_key_file are mutually exclusive",
+		"unknown setting settings.PLANTON_MCP_UNKNOWN",
+		`PLANTON_MCP_TRANSPORT "htp" (from environment)`,
+		`PLANTON_CLOUD_ENVIRONMENT "staging" (from environment)`,
+		`PLANTON_MCP_HTTP_AUTH_ENABLED "yes" (from environment)`,
+		`PLANTON_MCP_HTTP_PORT "99999" (from command line)`,
+		`PLANTON_MCP_GRPC_TLS "sometimes" (from tls.mode in profile "default" of ` + path + `)`,
+		`PLANTON_MCP_LOG_LEVEL "loud" (from settings.PLANTON_MCP_LOG_LEVEL in profile "default" of ` + path + `)`,
+	}
+	message := err.Error()
+	for _, problem := range want {
+		if !strings.Contains(message, problem) {
+			t.Errorf("error does not report %q:\n%s", problem, message)
+		}
+	}
+	if len(validationErr.Problems) != len(want) {
+		t.Errorf("%d problems reported, want %d:\n%s", len(validationErr.Problems), len(want), message)
+	}
+}
+
+func TestLoadAPIKeyRequired(t *testing.T) {
+	isolateEnv(t)
+	path := writeConfig(t, "profiles:\n  default:\n    api_key_env: PLANTON_TEST_KEY\n")
+	t.Setenv("PLANTON_TEST_KEY", "")
+
+	_, err := Load(LoadOptions{ConfigFile: path})
+	if err == nil || !strings.Contains(err.Error(), "environment variable PLANTON_TEST_KEY is not set") {
+		t.Errorf("Load() error = %v, want the unset api_key_env variable reported", err)
+	}
+
+	t.Setenv("PLANTON_TEST_KEY", "from-env")
+	cfg, err := Load(LoadOptions{ConfigFile: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.PlantonAPIKey != "from-env" {
+		t.Errorf("PlantonAPIKey = %q, want the value of PLANTON_TEST_KEY", cfg.PlantonAPIKey)
+	}
+
+	// The HTTP transport takes keys from requests and does not need one
+	if _, err := Load(LoadOptions{Overrides: map[string]string{TransportEnvVar: "http"}}); err != nil {
+		t.Errorf("Load() for the HTTP transport: %v", err)
+	}
+}
+
+func TestLoadHTTPBindAddress(t *testing.T) {
+	tests := []struct {
+		name        string
+		authEnabled string
+		bindAddress string
+		want        string
+		wantErr     string
+	}{
+		{name: "auth enabled listens on all interfaces", authEnabled: "true", want: ""},
+		{name: "auth disabled listens on localhost only", authEnabled: "false", want: LocalhostBindAddress},
+		{name: "explicit address with auth disabled", authEnabled: "false", bindAddress: "0.0.0.0", want: "0.0.0.0"},
+		{name: "bracketed IPv6 address", authEnabled: "true", bindAddress: "[::1]", want: "::1"},
+		{name: "address with port", authEnabled: "true", bindAddress: "127.0.0.1:8080", wantErr: `PLANTON_MCP_HTTP_BIND_ADDRESS "127.0.0.1:8080"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolateEnv(t)
+			overrides := map[string]string{TransportEnvVar: "http", HTTPAuthEnabledEnvVar: tt.authEnabled}
+			if tt.bindAddress != "" {
+				overrides[HTTPBindAddressEnvVar] = tt.bindAddress
+			}
+
+			cfg, err := Load(LoadOptions{Overrides: overrides})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Load() error = %v, want %s reported", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.HTTPBindAddress != tt.want {
+				t.Errorf("HTTPBindAddress = %q, want %q", cfg.HTTPBindAddress, tt.want)
+			}
+		})
+	}
+}
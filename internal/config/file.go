@@ -0,0 +1,319 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultProfileName is the profile used when neither --profile, PLANTON_MCP_PROFILE
+// nor the file's default_profile selects one
+const DefaultProfileName = "default"
+
+// fileConfig is the YAML config file, e.g.:
+//
+//	default_profile: prod
+//	profiles:
+//	  prod:
+//	    environment: live
+//	    api_key_file: ~/.config/planton/prod.key
+//	    default_org: acme
+//	    tools:
+//	      read_only: true
+//	  local:
+//	    endpoint: localhost:8080
+//	    api_key_env: PLANTON_LOCAL_API_KEY
+type fileConfig struct {
+	DefaultProfile string             `yaml:"default_profile"`
+	Profiles       map[string]profile `yaml:"profiles"`
+}
+
+// profile is a named set of settings in the config file. Every field maps to the
+// environment variable of the same setting, which takes precedence over it.
+type profile struct {
+	Environment string `yaml:"environment"`
+	Endpoint    string `yaml:"endpoint"`
+	Transport   string `yaml:"transport"`
+
+	// APIKey, APIKeyEnv and APIKeyFile are the key sources: the key itself, the name
+	// of an environment variable holding it, or a file holding it. At most one is set.
+	APIKey     string `yaml:"api_key"`
+	APIKeyEnv  string `yaml:"api_key_env"`
+	APIKeyFile string `yaml:"api_key_file"`
+
+	DefaultOrg string `yaml:"default_org"`
+	DefaultEnv string `yaml:"default_env"`
+
+	Tools profileTools `yaml:"tools"`
+	TLS   profileTLS   `yaml:"tls"`
+
+	// Settings holds any other setting, keyed by environment variable name
+	// (e.g. PLANTON_MCP_LOG_LEVEL)
+	Settings map[string]string `yaml:"settings"`
+}
+
+// profileTools is the tool policy of a profile
+type profileTools struct {
+	ReadOnly *bool    `yaml:"read_only"`
+	Allow    []string `yaml:"allow"`
+	Deny     []string `yaml:"deny"`
+}
+
+// profileTLS is the TLS configuration of connections to Planton APIs of a profile
+type profileTLS struct {
+	Mode       string `yaml:"mode"`
+	CAFile     string `yaml:"ca_file"`
+	CertFile   string `yaml:"cert_file"`
+	KeyFile    string `yaml:"key_file"`
+	ServerName string `yaml:"server_name"`
+}
+
+// profileValue is the value of a setting in the selected profile
+type profileValue struct {
+	value string
+
+	// key is the YAML key the value is set with, e.g. "tls.ca_file"
+	key string
+
+	// err is set when the value could not be resolved, e.g. an unreadable
+	// api_key_file. It is only reported when the value is used.
+	err error
+}
+
+// fileSettings lists the settings a profile may set under "settings"
+var fileSettings = []string{
+	EnvironmentEnvVar, EndpointOverrideEnvVar, APIKeyEnvVar, TransportEnvVar,
+	HTTPPortEnvVar, HTTPAuthEnabledEnvVar, HTTPExternalURLEnvVar, HTTPBasePathEnvVar,
+	HTTPAllowedOriginsEnvVar, HTTPBindAddressEnvVar, OAuthAuthorizationServersEnvVar,
+	JWTJWKSURLEnvVar, JWTIssuerEnvVar, JWTAudienceEnvVar,
+	TokenValidationEnabledEnvVar, TokenCacheTTLEnvVar, TokenRejectedCacheTTLEnvVar, TokenCacheSizeEnvVar,
+	GRPCIdleTimeoutEnvVar, GRPCKeepaliveTimeEnvVar, GRPCKeepaliveTimeoutEnvVar,
+	GRPCRetryMaxAttemptsEnvVar, GRPCRetryInitialBackoffEnvVar, GRPCRetryMaxBackoffEnvVar,
+	GRPCTLSEnvVar, GRPCTLSCAFileEnvVar, GRPCTLSCertFileEnvVar, GRPCTLSKeyFileEnvVar, GRPCTLSServerNameEnvVar,
+	ToolTimeoutEnvVar, ToolTimeoutsEnvVar, ShutdownTimeoutEnvVar, ShutdownDelayEnvVar,
+	LogLevelEnvVar, LogFormatEnvVar,
+	RateLimitRPSEnvVar, RateLimitBurstEnvVar, MaxConcurrentToolCallsEnvVar, MaxConcurrentStreamingCallsEnvVar,
+	TracingExporterEnvVar, TracingEndpointEnvVar, TracingInsecureEnvVar, TracingSampleRatioEnvVar,
+	DefaultOrgEnvVar, DefaultEnvEnvVar, ReadOnlyEnvVar, ToolsAllowEnvVar, ToolsDenyEnvVar,
+}
+
+// DefaultConfigFile returns the config file loaded when neither --config nor
+// PLANTON_MCP_CONFIG is set: $XDG_CONFIG_HOME/planton/mcp.yaml, or
+// ~/.config/planton/mcp.yaml when XDG_CONFIG_HOME is not set. It returns an empty
+// string when the home directory is unknown.
+func DefaultConfigFile() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "planton", "mcp.yaml")
+}
+
+// loadFile reads the config file and selects the profile whose values are used for
+// settings set neither on the command line nor in the environment.
+//
+// A missing file at the default location is not an error; a missing file given with
+// --config or PLANTON_MCP_CONFIG is. Problems with individual profile values are
+// recorded on the loader, so they are reported together with invalid settings.
+func (l *loader) loadFile(opts LoadOptions) error {
+	path, explicit := opts.ConfigFile, true
+	if path == "" {
+		path = strings.TrimSpace(os.Getenv(ConfigFileEnvVar))
+	}
+	if path == "" {
+		path, explicit = DefaultConfigFile(), false
+	}
+
+	profileName := opts.Profile
+	if profileName == "" {
+		profileName = strings.TrimSpace(os.Getenv(ProfileEnvVar))
+	}
+
+	file, err := readConfigFile(expandHome(path))
+	if err != nil {
+		if explicit || !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		if profileName != "" {
+			return fmt.Errorf("profile %q selected but config file %s does not exist", profileName, path)
+		}
+		return nil
+	}
+	l.configFile = expandHome(path)
+
+	if profileName == "" {
+		profileName = file.DefaultProfile
+	}
+	if _, ok := file.Profiles[DefaultProfileName]; ok && profileName == "" {
+		profileName = DefaultProfileName
+	}
+	if profileName == "" {
+		if len(file.Profiles) == 0 {
+			return nil
+		}
+		return fmt.Errorf("no profile selected in config file %s: select one of %s with --profile or %s, or set default_profile",
+			l.configFile, profileNames(file), ProfileEnvVar)
+	}
+
+	p, ok := file.Profiles[profileName]
+	if !ok {
+		return fmt.Errorf("profile %q not found in config file %s (available: %s)", profileName, l.configFile, profileNames(file))
+	}
+	l.profileName = profileName
+
+	var problems []error
+	l.profile, problems = p.values(filepath.Dir(l.configFile))
+	for _, problem := range problems {
+		l.problems = append(l.problems, fmt.Errorf("profile %q of %s: %w", profileName, l.configFile, problem))
+	}
+	return nil
+}
+
+// readConfigFile reads and strictly decodes a config file: unknown keys are errors,
+// so that misspelled settings are not silently ignored
+func readConfigFile(path string) (*fileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+
+	var file fileConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return &file, nil
+}
+
+// profileNames returns the sorted profile names of a config file, for error messages
+func profileNames(file *fileConfig) string {
+	names := slices.Sorted(maps.Keys(file.Profiles))
+	if len(names) == 0 {
+		return "(none)"
+	}
+	return strings.Join(names, ", ")
+}
+
+// values returns the settings of the profile keyed by environment variable name.
+// Relative file paths are resolved against dir, the directory of the config file.
+func (p profile) values(dir string) (map[string]profileValue, []error) {
+	values := make(map[string]profileValue)
+	set := func(name, key, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			values[name] = profileValue{value: value, key: key}
+		}
+	}
+
+	set(EnvironmentEnvVar, "environment", p.Environment)
+	set(EndpointOverrideEnvVar, "endpoint", p.Endpoint)
+	set(TransportEnvVar, "transport", p.Transport)
+	set(DefaultOrgEnvVar, "default_org", p.DefaultOrg)
+	set(DefaultEnvEnvVar, "default_env", p.DefaultEnv)
+	if p.Tools.ReadOnly != nil {
+		set(ReadOnlyEnvVar, "tools.read_only", strconv.FormatBool(*p.Tools.ReadOnly))
+	}
+	set(ToolsAllowEnvVar, "tools.allow", strings.Join(p.Tools.Allow, ","))
+	set(ToolsDenyEnvVar, "tools.deny", strings.Join(p.Tools.Deny, ","))
+	set(GRPCTLSEnvVar, "tls.mode", p.TLS.Mode)
+	set(GRPCTLSCAFileEnvVar, "tls.ca_file", resolvePath(dir, p.TLS.CAFile))
+	set(GRPCTLSCertFileEnvVar, "tls.cert_file", resolvePath(dir, p.TLS.CertFile))
+	set(GRPCTLSKeyFileEnvVar, "tls.key_file", resolvePath(dir, p.TLS.KeyFile))
+	set(GRPCTLSServerNameEnvVar, "tls.server_name", p.TLS.ServerName)
+
+	var problems []error
+	switch {
+	case countSet(p.APIKey, p.APIKeyEnv, p.APIKeyFile) > 1:
+		problems = append(problems, errors.New("api_key, api_key_env and api_key_file are mutually exclusive"))
+	case p.APIKey != "":
+		set(APIKeyEnvVar, "api_key", p.APIKey)
+	case p.APIKeyEnv != "":
+		values[APIKeyEnvVar] = apiKeyFromEnv(p.APIKeyEnv)
+	case p.APIKeyFile != "":
+		values[APIKeyEnvVar] = apiKeyFromFile(resolvePath(dir, p.APIKeyFile))
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(p.Settings)) {
+		key := "settings." + name
+		switch {
+		case !slices.Contains(fileSettings, name):
+			problems = append(problems, fmt.Errorf("unknown setting %s: expected an environment variable name such as %s", key, LogLevelEnvVar))
+		case values[name].key != "":
+			problems = append(problems, fmt.Errorf("%s conflicts with %s", key, values[name].key))
+		default:
+			set(name, key, p.Settings[name])
+		}
+	}
+	return values, problems
+}
+
+// apiKeyFromEnv resolves api_key_env, the name of an environment variable holding
+// the API key
+func apiKeyFromEnv(envVar string) profileValue {
+	value := profileValue{value: strings.TrimSpace(os.Getenv(envVar)), key: "api_key_env"}
+	if value.value == "" {
+		value.err = fmt.Errorf("environment variable %s is not set", envVar)
+	}
+	return value
+}
+
+// apiKeyFromFile resolves api_key_file, a file holding the API key
+func apiKeyFromFile(path string) profileValue {
+	value := profileValue{key: "api_key_file"}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		value.err = err
+		return value
+	}
+	if value.value = strings.TrimSpace(string(data)); value.value == "" {
+		value.err = fmt.Errorf("%s is empty", path)
+	}
+	return value
+}
+
+// countSet returns how many of the values are not empty
+func countSet(values ...string) int {
+	count := 0
+	for _, value := range values {
+		if value != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// resolvePath expands a leading "~/" and resolves a relative path against dir
+func resolvePath(dir, path string) string {
+	path = expandHome(strings.TrimSpace(path))
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// expandHome replaces a leading "~/" with the home directory
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, rest)
+}
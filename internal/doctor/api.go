@@ -0,0 +1,194 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/commons/apiresource"
+	"buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/commons/apiresource/apiresourcekind"
+	"buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/commons/rpc"
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxListed bounds the number of names listed in a result detail
+const maxListed = 10
+
+// checkAuthentication verifies the API key by listing the organizations it is a
+// member of, the RPC of the list_organizations tool.
+func (r *runner) checkAuthentication(ctx context.Context) Result {
+	client, err := r.factory.OrganizationClient(ctx)
+	if err != nil {
+		return Result{Status: StatusFail, Detail: "cannot create the client: " + err.Error()}
+	}
+	defer client.Close()
+
+	organizations, err := client.List(ctx)
+	if err != nil {
+		return apiFailure("list_organizations", err, "")
+	}
+
+	for _, org := range organizations {
+		r.memberships = append(r.memberships, organization{
+			id:   org.GetMetadata().GetId(),
+			slug: org.GetMetadata().GetSlug(),
+		})
+	}
+	if len(r.memberships) == 0 {
+		return Result{
+			Status: StatusWarn,
+			Detail: "the API key is accepted, but its user is not a member of any organization",
+			Fix:    "Ask an administrator of your organization to invite you, or create an organization in the Planton Cloud console.",
+		}
+	}
+	return Result{
+		Status: StatusPass,
+		Detail: fmt.Sprintf("the API key is accepted; member of %d organization(s): %s", len(r.memberships), r.membershipSlugs()),
+	}
+}
+
+// checkOrganization selects the organization to check access for and verifies the
+// key's user is a member of it.
+func (r *runner) checkOrganization(context.Context) Result {
+	org, source := r.opts.Org, "--org"
+	if org == "" {
+		org, source = r.cfg.DefaultOrg, config.DefaultOrgEnvVar
+	}
+	if org == "" {
+		if len(r.memberships) != 1 {
+			return Result{
+				Status: StatusSkip,
+				Detail: "no organization selected",
+				Fix: fmt.Sprintf("Run doctor with --org <slug>, or set %s, to check environment and service access.",
+					config.DefaultOrgEnvVar),
+			}
+		}
+		org, source = r.memberships[0].slug, "the only membership"
+	}
+
+	member := slices.ContainsFunc(r.memberships, func(m organization) bool {
+		return m.id == org || m.slug == org
+	})
+	if !member {
+		return Result{
+			Status: StatusFail,
+			Detail: fmt.Sprintf("not a member of organization %q (from %s); member of: %s", org, source, r.membershipSlugs()),
+			Fix:    fmt.Sprintf("Use one of the listed organizations, or ask an administrator of %q to invite you.", org),
+		}
+	}
+	r.org = org
+	return Result{Status: StatusPass, Detail: fmt.Sprintf("member of %q (from %s)", org, source)}
+}
+
+// checkEnvironments lists the environments of the organization, the RPC of the
+// list_environments_for_org tool, and verifies the default environment exists.
+func (r *runner) checkEnvironments(ctx context.Context) Result {
+	client, err := r.factory.EnvironmentClient(ctx)
+	if err != nil {
+		return Result{Status: StatusFail, Detail: "cannot create the client: " + err.Error()}
+	}
+	defer client.Close()
+
+	environments, err := client.FindByOrg(ctx, r.org)
+	if err != nil {
+		return apiFailure("list_environments_for_org", err, r.org)
+	}
+
+	slugs := make([]string, 0, len(environments))
+	for _, env := range environments {
+		slugs = append(slugs, env.GetMetadata().GetSlug())
+	}
+	if env := r.cfg.DefaultEnv; env != "" && !slices.Contains(slugs, env) {
+		return Result{
+			Status: StatusFail,
+			Detail: fmt.Sprintf("default environment %q is not an environment of %q; environments: %s",
+				env, r.org, listNames(slugs)),
+			Fix: fmt.Sprintf("Set %s to one of the listed environments.", config.DefaultEnvEnvVar),
+		}
+	}
+	if len(slugs) == 0 {
+		return Result{
+			Status: StatusWarn,
+			Detail: fmt.Sprintf("no environments visible in %q", r.org),
+			Fix:    fmt.Sprintf("Create an environment in %q, or ask an administrator to grant you access to one.", r.org),
+		}
+	}
+	return Result{Status: StatusPass, Detail: fmt.Sprintf("%d environment(s) in %q: %s", len(slugs), r.org, listNames(slugs))}
+}
+
+// checkServices lists the services of the organization, the RPC of the
+// list_services_for_org tool.
+func (r *runner) checkServices(ctx context.Context) Result {
+	client, err := r.factory.ServiceClient(ctx)
+	if err != nil {
+		return Result{Status: StatusFail, Detail: "cannot create the client: " + err.Error()}
+	}
+	defer client.Close()
+
+	services, err := client.Find(ctx, &apiresource.FindApiResourcesRequest{
+		Page: &rpc.PageInfo{Num: 0, Size: 1000},
+		Kind: apiresourcekind.ApiResourceKind_service,
+		Org:  r.org,
+	})
+	if err != nil {
+		return apiFailure("list_services_for_org", err, r.org)
+	}
+	return Result{Status: StatusPass, Detail: fmt.Sprintf("%d service(s) visible in %q", len(services.GetEntries()), r.org)}
+}
+
+// membershipSlugs lists the slugs of the key's organizations
+func (r *runner) membershipSlugs() string {
+	slugs := make([]string, 0, len(r.memberships))
+	for _, m := range r.memberships {
+		slugs = append(slugs, m.slug)
+	}
+	return listNames(slugs)
+}
+
+// listNames joins names, eliding all but the first maxListed
+func listNames(names []string) string {
+	if len(names) == 0 {
+		return "none"
+	}
+	if len(names) > maxListed {
+		return fmt.Sprintf("%s and %d more", strings.Join(names[:maxListed], ", "), len(names)-maxListed)
+	}
+	return strings.Join(names, ", ")
+}
+
+// apiFailure reports a failed RPC with a fix for its gRPC status code.
+//
+// Args:
+//   - tool: Tool whose RPC failed, named in the detail
+//   - err: RPC error
+//   - org: Organization the RPC was scoped to; empty for list_organizations
+func apiFailure(tool string, err error, org string) Result {
+	st := status.Convert(err)
+	result := Result{
+		Status: StatusFail,
+		Detail: fmt.Sprintf("%s failed with %s: %s", tool, st.Code(), st.Message()),
+	}
+
+	switch st.Code() {
+	case codes.Unauthenticated:
+		result.Fix = fmt.Sprintf("Planton APIs rejected the API key: it is invalid, revoked or expired, or belongs to another environment than %s. "+
+			"Create a new key in the Planton Cloud console and set %s.",
+			config.EnvironmentEnvVar, config.APIKeyEnvVar)
+	case codes.PermissionDenied:
+		result.Fix = fmt.Sprintf("The key's user lacks permission for this in %q. Ask an administrator of the organization to grant access.", org)
+	case codes.NotFound:
+		result.Fix = fmt.Sprintf("Organization %q was not found. Check the ID or slug.", org)
+	case codes.Unavailable:
+		result.Fix = fmt.Sprintf("Planton APIs did not respond over gRPC although the network checks passed. "+
+			"Check %s (TLS to a plaintext endpoint, or plaintext to a TLS endpoint, fails here), and retry later.",
+			config.GRPCTLSEnvVar)
+	case codes.DeadlineExceeded:
+		result.Fix = "Planton APIs did not answer in time. Retry, or run doctor with a longer --timeout."
+	default:
+		result.Fix = "Retry later. If the error persists, report it to Planton Cloud support with the detail above."
+	}
+	return result
+}
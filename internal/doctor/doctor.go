@@ -0,0 +1,214 @@
+// Package doctor diagnoses why the server cannot reach or use Planton APIs, for the
+// "doctor" command.
+//
+// Checks run in order, from the configuration to the network path (DNS, TCP, TLS)
+// to the API key and its permissions. Every check reports pass, warn, fail or skip
+// with a suggested fix. A check whose prerequisite failed is skipped, so the first
+// failure is the one to fix.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/plantoncloud/mcp-server-planton/internal/common/auth"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+)
+
+// DefaultTimeout bounds each check.
+const DefaultTimeout = 10 * time.Second
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	// StatusPass means the check succeeded
+	StatusPass Status = "pass"
+
+	// StatusWarn means the check succeeded, but something is likely to cause trouble
+	StatusWarn Status = "warn"
+
+	// StatusFail means the check failed
+	StatusFail Status = "fail"
+
+	// StatusSkip means the check did not run, because it does not apply or a check
+	// it depends on failed
+	StatusSkip Status = "skip"
+)
+
+// Result is the outcome of one check.
+type Result struct {
+	// Name identifies the check, e.g. "dns" or "authentication"
+	Name string `json:"name"`
+
+	// Status is the outcome
+	Status Status `json:"status"`
+
+	// Detail describes what was found
+	Detail string `json:"detail"`
+
+	// Fix suggests how to resolve a failure or warning
+	Fix string `json:"fix,omitempty"`
+}
+
+// Options tune the checks.
+type Options struct {
+	// Org is the organization (ID or slug) whose environment and service access is
+	// checked. When empty, PLANTON_MCP_DEFAULT_ORG is used, or the only organization
+	// the key is a member of.
+	Org string
+
+	// Timeout bounds each check; DefaultTimeout when zero
+	Timeout time.Duration
+}
+
+// Report is the outcome of all checks.
+type Report struct {
+	Results []Result `json:"checks"`
+}
+
+// Failed returns the number of failed checks.
+func (r Report) Failed() int {
+	failed := 0
+	for _, result := range r.Results {
+		if result.Status == StatusFail {
+			failed++
+		}
+	}
+	return failed
+}
+
+// ConfigurationFailure returns the report for a configuration that could not be
+// loaded; no other check can run then.
+//
+// Args:
+//   - err: Error returned by config.Load, usually a *config.ValidationError
+func ConfigurationFailure(err error) Report {
+	return Report{Results: []Result{{
+		Name:   "configuration",
+		Status: StatusFail,
+		Detail: err.Error(),
+		Fix:    "Correct the listed settings in the environment, on the command line or in the config file profile.",
+	}}}
+}
+
+// Run runs every check against the configured Planton APIs endpoint.
+//
+// Args:
+//   - ctx: Context of the run; cancelling it stops the remaining checks
+//   - cfg: Loaded configuration
+//   - factory: Creates the clients of the API checks; they authenticate with the
+//     configured API key
+//   - opts: Organization to check and per-check timeout
+//
+// Returns the result of every check, in order.
+func Run(ctx context.Context, cfg *config.Config, factory clientfactory.Factory, opts Options) Report {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	ctx = auth.WithCredentialPolicy(ctx, auth.PolicyServerKey)
+
+	r := &runner{cfg: cfg, factory: factory, opts: opts, blocked: make(map[string]bool)}
+	r.run(ctx, "configuration", nil, r.checkConfiguration)
+	r.run(ctx, "api key", nil, r.checkAPIKey)
+	r.run(ctx, "dns", nil, r.checkDNS)
+	r.run(ctx, "tcp", []string{"dns"}, r.checkTCP)
+	r.run(ctx, "tls", []string{"tcp"}, r.checkTLS)
+	r.run(ctx, "authentication", []string{"api key", "tls"}, r.checkAuthentication)
+	r.run(ctx, "organization", []string{"authentication"}, r.checkOrganization)
+	r.run(ctx, "environments", []string{"organization"}, r.checkEnvironments)
+	r.run(ctx, "services", []string{"organization"}, r.checkServices)
+	return Report{Results: r.results}
+}
+
+// runner carries the state shared by the checks of a run
+type runner struct {
+	cfg     *config.Config
+	factory clientfactory.Factory
+	opts    Options
+	results []Result
+
+	// blocked records the checks that failed or were skipped; the checks depending
+	// on them are skipped
+	blocked map[string]bool
+
+	// memberships are the organizations the key is a member of, set by the
+	// authentication check
+	memberships []organization
+
+	// org is the organization to check access for, set by the organization check
+	org string
+}
+
+// organization identifies an organization the key is a member of
+type organization struct {
+	id   string
+	slug string
+}
+
+// check runs one check and returns its status, detail and fix
+type check func(ctx context.Context) Result
+
+// run runs a check with the per-check timeout, unless one of the checks it depends
+// on failed.
+func (r *runner) run(ctx context.Context, name string, dependsOn []string, fn check) {
+	var result Result
+	for _, dependency := range dependsOn {
+		if r.blocked[dependency] {
+			result = Result{Status: StatusSkip, Detail: fmt.Sprintf("the %s check did not pass", dependency)}
+			break
+		}
+	}
+	if result.Status == "" {
+		if err := ctx.Err(); err != nil {
+			result = Result{Status: StatusSkip, Detail: err.Error()}
+		} else {
+			checkCtx, cancel := context.WithTimeout(ctx, r.opts.Timeout)
+			result = fn(checkCtx)
+			cancel()
+		}
+	}
+
+	result.Name = name
+	if result.Status == StatusFail || result.Status == StatusSkip {
+		r.blocked[name] = true
+	}
+	r.results = append(r.results, result)
+}
+
+// checkConfiguration reports where the configuration came from.
+func (r *runner) checkConfiguration(context.Context) Result {
+	if r.cfg.ConfigFile == "" {
+		return Result{Status: StatusPass, Detail: "loaded from the environment and command line; no config file"}
+	}
+	return Result{Status: StatusPass, Detail: fmt.Sprintf("loaded profile %q of %s", r.cfg.Profile, r.cfg.ConfigFile)}
+}
+
+// checkAPIKey reports whether an API key is configured. The key is verified by the
+// authentication check.
+func (r *runner) checkAPIKey(context.Context) Result {
+	key := r.cfg.PlantonAPIKey
+	if key == "" {
+		return Result{
+			Status: StatusFail,
+			Detail: "no API key configured",
+			Fix: fmt.Sprintf("Create an API key in the Planton Cloud console and set %s, or api_key_env or api_key_file in the config file profile.",
+				config.APIKeyEnvVar),
+		}
+	}
+	if strings.TrimSpace(key) != key {
+		return Result{
+			Status: StatusWarn,
+			Detail: "the API key has leading or trailing whitespace",
+			Fix:    "Remove the whitespace; it is sent as part of the key and will be rejected.",
+		}
+	}
+	kind := "API key"
+	if auth.IsJWT(key) {
+		kind = "JWT"
+	}
+	return Result{Status: StatusPass, Detail: fmt.Sprintf("%s configured: %s", kind, MaskSecret(key))}
+}
@@ -0,0 +1,333 @@
+package doctor
+
+import (
+	"context"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/commons/apiresource"
+	environmentv1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/resourcemanager/environment/v1"
+	organizationv1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/resourcemanager/organization/v1"
+	servicev1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/servicehub/service/v1"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeFactory hands out clients returning fixed results. Clients the checks do not
+// use are not implemented.
+type fakeFactory struct {
+	clientfactory.Factory
+	organizations []*organizationv1.Organization
+	orgErr        error
+	environments  []*environmentv1.Environment
+	envErr        error
+	serviceErr    error
+}
+
+func (f *fakeFactory) OrganizationClient(context.Context) (clientfactory.OrganizationClient, error) {
+	return fakeOrganizationClient{f}, nil
+}
+
+func (f *fakeFactory) EnvironmentClient(context.Context) (clientfactory.EnvironmentClient, error) {
+	return fakeEnvironmentClient{f}, nil
+}
+
+func (f *fakeFactory) ServiceClient(context.Context) (clientfactory.ServiceClient, error) {
+	return fakeServiceClient{fake: f}, nil
+}
+
+type fakeOrganizationClient struct{ fake *fakeFactory }
+
+func (c fakeOrganizationClient) List(context.Context) ([]*organizationv1.Organization, error) {
+	return c.fake.organizations, c.fake.orgErr
+}
+
+func (fakeOrganizationClient) Close() error { return nil }
+
+type fakeEnvironmentClient struct{ fake *fakeFactory }
+
+func (c fakeEnvironmentClient) FindByOrg(context.Context, string) ([]*environmentv1.Environment, error) {
+	return c.fake.environments, c.fake.envErr
+}
+
+func (fakeEnvironmentClient) Close() error { return nil }
+
+type fakeServiceClient struct {
+	clientfactory.ServiceClient
+	fake *fakeFactory
+}
+
+func (c fakeServiceClient) Find(context.Context, *apiresource.FindApiResourcesRequest) (*servicev1.ServiceList, error) {
+	if c.fake.serviceErr != nil {
+		return nil, c.fake.serviceErr
+	}
+	return &servicev1.ServiceList{Entries: []*servicev1.Service{{}}}, nil
+}
+
+func (fakeServiceClient) Close() error { return nil }
+
+// newFakeFactory returns a factory for a key that is a member of the given
+// organizations, each with a dev and a prod environment.
+func newFakeFactory(orgSlugs ...string) *fakeFactory {
+	f := &fakeFactory{}
+	for _, slug := range orgSlugs {
+		f.organizations = append(f.organizations, &organizationv1.Organization{
+			Metadata: &apiresource.ApiResourceMetadata{Id: "id-" + slug, Slug: slug},
+		})
+	}
+	for _, slug := range []string{"dev", "prod"} {
+		f.environments = append(f.environments, &environmentv1.Environment{
+			Metadata: &apiresource.ApiResourceMetadata{Slug: slug},
+		})
+	}
+	return f
+}
+
+// startTLSServer starts an HTTPS server and returns its address and the path of a
+// PEM file holding its certificate.
+func startTLSServer(t *testing.T, http2 bool) (string, string) {
+	t.Helper()
+	server := httptest.NewUnstartedServer(http.NotFoundHandler())
+	server.EnableHTTP2 = http2
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return server.Listener.Addr().String(), caFile
+}
+
+// startTCPServer accepts connections without speaking any protocol and returns its
+// address.
+func startTCPServer(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+// statuses maps check names to their status.
+func statuses(report Report) map[string]Status {
+	byName := make(map[string]Status)
+	for _, result := range report.Results {
+		byName[result.Name] = result.Status
+	}
+	return byName
+}
+
+// find returns the result of a check.
+func find(t *testing.T, report Report, name string) Result {
+	t.Helper()
+	for _, result := range report.Results {
+		if result.Name == name {
+			return result
+		}
+	}
+	t.Fatalf("no %s check in the report", name)
+	return Result{}
+}
+
+const testAPIKey = "pk_test_0123456789abcdef"
+
+func TestRunHealthy(t *testing.T) {
+	endpoint, caFile := startTLSServer(t, true)
+	cfg := &config.Config{
+		PlantonAPIKey:           testAPIKey,
+		PlantonAPIsGRPCEndpoint: endpoint,
+		GRPCTLSMode:             config.GRPCTLSOn,
+		GRPCTLSCAFile:           caFile,
+		DefaultEnv:              "prod",
+	}
+
+	report := Run(context.Background(), cfg, newFakeFactory("acme", "beta"), Options{Org: "acme"})
+
+	want := []string{"configuration", "api key", "dns", "tcp", "tls", "authentication", "organization", "environments", "services"}
+	if len(report.Results) != len(want) {
+		t.Fatalf("got %d checks, want %d: %+v", len(report.Results), len(want), report.Results)
+	}
+	for i, result := range report.Results {
+		if result.Name != want[i] || result.Status != StatusPass {
+			t.Errorf("check %d = %s %s (%s), want %s pass", i, result.Name, result.Status, result.Detail, want[i])
+		}
+	}
+	if detail := find(t, report, "tls").Detail; !strings.Contains(detail, "TLS 1.") {
+		t.Errorf("tls detail = %q, want the TLS version", detail)
+	}
+	if report.Failed() != 0 {
+		t.Errorf("Failed() = %d, want 0", report.Failed())
+	}
+}
+
+func TestRunTLSFailures(t *testing.T) {
+	h2Endpoint, caFile := startTLSServer(t, true)
+	http1Endpoint, http1CAFile := startTLSServer(t, false)
+	plaintext := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(plaintext.Close)
+
+	tests := []struct {
+		name     string
+		endpoint string
+		caFile   string
+		wantFix  string
+	}{
+		{name: "untrusted certificate", endpoint: h2Endpoint, wantFix: config.GRPCTLSCAFileEnvVar},
+		{name: "no HTTP/2", endpoint: http1Endpoint, caFile: http1CAFile, wantFix: "HTTP/2"},
+		{name: "plaintext endpoint", endpoint: plaintext.Listener.Addr().String(), caFile: caFile, wantFix: config.GRPCTLSEnvVar + "=off"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				PlantonAPIKey:           testAPIKey,
+				PlantonAPIsGRPCEndpoint: tt.endpoint,
+				GRPCTLSMode:             config.GRPCTLSOn,
+				GRPCTLSCAFile:           tt.caFile,
+			}
+
+			report := Run(context.Background(), cfg, newFakeFactory("acme"), Options{Timeout: 5 * time.Second})
+
+			tls := find(t, report, "tls")
+			if tls.Status != StatusFail || !strings.Contains(tls.Fix, tt.wantFix) {
+				t.Errorf("tls = %s %q (fix %q), want fail with a fix mentioning %q", tls.Status, tls.Detail, tls.Fix, tt.wantFix)
+			}
+			if got := statuses(report)["authentication"]; got != StatusSkip {
+				t.Errorf("authentication = %s, want skip after the tls failure", got)
+			}
+		})
+	}
+}
+
+func TestRunAPIChecks(t *testing.T) {
+	endpoint := startTCPServer(t)
+
+	tests := []struct {
+		name    string
+		apiKey  string
+		factory *fakeFactory
+		org     string
+		defEnv  string
+		want    map[string]Status
+		wantFix string
+	}{
+		{
+			name:    "no API key",
+			factory: newFakeFactory("acme"),
+			want:    map[string]Status{"api key": StatusFail, "authentication": StatusSkip},
+			wantFix: config.APIKeyEnvVar,
+		},
+		{
+			name:    "key rejected",
+			apiKey:  testAPIKey,
+			factory: &fakeFactory{orgErr: status.Error(codes.Unauthenticated, "invalid token")},
+			want:    map[string]Status{"authentication": StatusFail, "organization": StatusSkip, "services": StatusSkip},
+			wantFix: "Create a new key",
+		},
+		{
+			name:    "only membership is selected",
+			apiKey:  testAPIKey,
+			factory: newFakeFactory("acme"),
+			want:    map[string]Status{"organization": StatusPass, "environments": StatusPass, "services": StatusPass},
+		},
+		{
+			name:    "no organization selected",
+			apiKey:  testAPIKey,
+			factory: newFakeFactory("acme", "beta"),
+			want:    map[string]Status{"organization": StatusSkip, "environments": StatusSkip},
+			wantFix: "--org",
+		},
+		{
+			name:    "not a member",
+			apiKey:  testAPIKey,
+			factory: newFakeFactory("acme", "beta"),
+			org:     "gamma",
+			want:    map[string]Status{"organization": StatusFail, "environments": StatusSkip},
+			wantFix: "invite you",
+		},
+		{
+			name:    "unknown default environment",
+			apiKey:  testAPIKey,
+			factory: newFakeFactory("acme"),
+			defEnv:  "staging",
+			want:    map[string]Status{"environments": StatusFail, "services": StatusPass},
+			wantFix: config.DefaultEnvEnvVar,
+		},
+		{
+			name:    "services denied",
+			apiKey:  testAPIKey,
+			factory: &fakeFactory{organizations: newFakeFactory("acme").organizations, serviceErr: status.Error(codes.PermissionDenied, "denied")},
+			org:     "id-acme",
+			want:    map[string]Status{"organization": StatusPass, "environments": StatusWarn, "services": StatusFail},
+			wantFix: "grant access",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				PlantonAPIKey:           tt.apiKey,
+				PlantonAPIsGRPCEndpoint: endpoint,
+				GRPCTLSMode:             config.GRPCTLSOff,
+				DefaultEnv:              tt.defEnv,
+			}
+
+			report := Run(context.Background(), cfg, tt.factory, Options{Org: tt.org})
+
+			got := statuses(report)
+			for name, want := range tt.want {
+				if got[name] != want {
+					t.Errorf("%s = %s (%s), want %s", name, got[name], find(t, report, name).Detail, want)
+				}
+			}
+			if tt.wantFix == "" {
+				return
+			}
+			fixes := ""
+			for _, result := range report.Results {
+				fixes += result.Fix + "\n"
+			}
+			if !strings.Contains(fixes, tt.wantFix) {
+				t.Errorf("no fix mentions %q:\n%s", tt.wantFix, fixes)
+			}
+		})
+	}
+}
+
+func TestSettingsMaskAPIKey(t *testing.T) {
+	cfg := &config.Config{PlantonAPIKey: testAPIKey, Transport: config.TransportStdio}
+	for _, setting := range Settings(cfg) {
+		if strings.Contains(setting.Value, testAPIKey) {
+			t.Errorf("%s shows the API key: %q", setting.Name, setting.Value)
+		}
+		if setting.Name == config.APIKeyEnvVar && setting.Value != "****cdef (24 characters)" {
+			t.Errorf("%s = %q, want the masked key", setting.Name, setting.Value)
+		}
+	}
+
+	if got := MaskSecret("short"); strings.Contains(got, "short") {
+		t.Errorf("MaskSecret(short) = %q, want it hidden entirely", got)
+	}
+}
@@ -0,0 +1,152 @@
+package doctor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/plantoncloud/mcp-server-planton/internal/common/grpcpool"
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+)
+
+// endpointFix is the suggestion for an endpoint that cannot be resolved or reached
+var endpointFix = fmt.Sprintf("Check the endpoint, set with %s or derived from %s (live, test, local). "+
+	"For an internal endpoint, check that your VPN is connected.",
+	config.EndpointOverrideEnvVar, config.EnvironmentEnvVar)
+
+// h2Fix is the suggestion for a TLS server that does not offer HTTP/2
+const h2Fix = "Check that the endpoint is the gRPC endpoint of Planton APIs, and that no proxy in between terminates TLS without HTTP/2 support."
+
+// host returns the host of the configured endpoint
+func (r *runner) host() string {
+	host, _, err := net.SplitHostPort(r.cfg.PlantonAPIsGRPCEndpoint)
+	if err != nil {
+		return r.cfg.PlantonAPIsGRPCEndpoint
+	}
+	return host
+}
+
+// checkDNS resolves the host of the endpoint.
+func (r *runner) checkDNS(ctx context.Context) Result {
+	endpoint := r.cfg.PlantonAPIsGRPCEndpoint
+	if _, _, err := net.SplitHostPort(endpoint); err != nil {
+		return Result{
+			Status: StatusFail,
+			Detail: fmt.Sprintf("endpoint %q is not host:port: %v", endpoint, err),
+			Fix:    endpointFix,
+		}
+	}
+
+	host := r.host()
+	if net.ParseIP(host) != nil {
+		return Result{Status: StatusPass, Detail: host + " is an IP address"}
+	}
+	addresses, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return Result{Status: StatusFail, Detail: fmt.Sprintf("cannot resolve %s: %v", host, err), Fix: endpointFix}
+	}
+	return Result{Status: StatusPass, Detail: fmt.Sprintf("%s resolves to %s", host, strings.Join(addresses, ", "))}
+}
+
+// checkTCP opens a TCP connection to the endpoint.
+func (r *runner) checkTCP(ctx context.Context) Result {
+	endpoint := r.cfg.PlantonAPIsGRPCEndpoint
+	start := time.Now()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", endpoint)
+	if err != nil {
+		return Result{
+			Status: StatusFail,
+			Detail: fmt.Sprintf("cannot connect to %s: %v", endpoint, err),
+			Fix: "Check the endpoint port, and that no firewall or proxy blocks outgoing connections to it. " +
+				"For a local endpoint, check that Planton APIs is running.",
+		}
+	}
+	defer conn.Close()
+	return Result{
+		Status: StatusPass,
+		Detail: fmt.Sprintf("connected to %s in %s", conn.RemoteAddr(), time.Since(start).Round(time.Millisecond)),
+	}
+}
+
+// checkTLS performs the TLS handshake the gRPC connections perform, with the
+// configured CA bundle, client certificate and server name.
+func (r *runner) checkTLS(ctx context.Context) Result {
+	endpoint := r.cfg.PlantonAPIsGRPCEndpoint
+	security, err := grpcpool.LoadTransportSecurity(r.cfg)
+	if err != nil {
+		return Result{
+			Status: StatusFail,
+			Detail: err.Error(),
+			Fix: fmt.Sprintf("Check that the files set with %s, %s and %s exist and hold PEM data.",
+				config.GRPCTLSCAFileEnvVar, config.GRPCTLSCertFileEnvVar, config.GRPCTLSKeyFileEnvVar),
+		}
+	}
+	if !security.UsesTLS(endpoint) {
+		return Result{
+			Status: StatusPass,
+			Detail: fmt.Sprintf("plaintext connection (%s=%s); no TLS handshake to check", config.GRPCTLSEnvVar, r.cfg.GRPCTLSMode),
+		}
+	}
+
+	tlsConfig := security.TLSConfig(endpoint)
+	dialer := tls.Dialer{Config: tlsConfig}
+	conn, err := dialer.DialContext(ctx, "tcp", endpoint)
+	if err != nil {
+		return Result{Status: StatusFail, Detail: "TLS handshake failed: " + err.Error(), Fix: tlsFix(err, tlsConfig.ServerName)}
+	}
+	defer conn.Close()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	leaf := state.PeerCertificates[0]
+	detail := fmt.Sprintf("%s, certificate for %s issued by %s, valid until %s",
+		tls.VersionName(state.Version), tlsConfig.ServerName, leaf.Issuer.CommonName, leaf.NotAfter.Format(time.DateOnly))
+
+	if state.NegotiatedProtocol != "h2" {
+		return Result{
+			Status: StatusFail,
+			Detail: detail + "; the server did not negotiate HTTP/2 (ALPN h2), which gRPC requires",
+			Fix:    h2Fix,
+		}
+	}
+	if remaining := time.Until(leaf.NotAfter); remaining < 14*24*time.Hour {
+		return Result{
+			Status: StatusWarn,
+			Detail: detail + fmt.Sprintf("; the certificate expires in %s", remaining.Round(time.Hour)),
+			Fix:    "Renew the server certificate, or tell the operator of the endpoint.",
+		}
+	}
+	return Result{Status: StatusPass, Detail: detail}
+}
+
+// tlsFix suggests a fix for a failed TLS handshake.
+func tlsFix(err error, serverName string) string {
+	var (
+		unknownAuthority x509.UnknownAuthorityError
+		hostname         x509.HostnameError
+		invalid          x509.CertificateInvalidError
+		recordHeader     tls.RecordHeaderError
+	)
+	switch {
+	case errors.As(err, &unknownAuthority):
+		return fmt.Sprintf("The server certificate is not signed by a trusted CA. Behind a TLS-inspecting proxy, "+
+			"set %s to the PEM bundle of the proxy's CA.", config.GRPCTLSCAFileEnvVar)
+	case errors.As(err, &hostname):
+		return fmt.Sprintf("The server certificate is not valid for %s. Check the endpoint host, or set %s to a name the certificate is issued for.",
+			serverName, config.GRPCTLSServerNameEnvVar)
+	case errors.As(err, &invalid):
+		return "The server certificate is expired or not yet valid. Check the system clock, or tell the operator of the endpoint."
+	case strings.Contains(err.Error(), "no application protocol"):
+		return h2Fix
+	case errors.As(err, &recordHeader):
+		return fmt.Sprintf("The endpoint does not speak TLS. For a plaintext endpoint, set %s=off.", config.GRPCTLSEnvVar)
+	default:
+		return fmt.Sprintf("Check the %s settings. A server requiring mutual TLS needs %s and %s.",
+			config.GRPCTLSEnvVar+"*", config.GRPCTLSCertFileEnvVar, config.GRPCTLSKeyFileEnvVar)
+	}
+}
@@ -0,0 +1,69 @@
+package doctor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+)
+
+// Setting is a resolved setting, as shown by the doctor command.
+type Setting struct {
+	// Name is the environment variable of the setting
+	Name string `json:"name"`
+
+	// Value is the resolved value, with secrets masked; empty when unset
+	Value string `json:"value"`
+}
+
+// Settings returns the resolved settings that decide how the server reaches Planton
+// APIs and which tools it serves. The API key is masked.
+//
+// Args:
+//   - cfg: Loaded configuration
+//
+// Returns the settings in display order.
+func Settings(cfg *config.Config) []Setting {
+	settings := []Setting{
+		{config.ConfigFileEnvVar, cfg.ConfigFile},
+		{config.ProfileEnvVar, cfg.Profile},
+		{config.APIKeyEnvVar, MaskSecret(cfg.PlantonAPIKey)},
+		{config.EndpointOverrideEnvVar, cfg.PlantonAPIsGRPCEndpoint},
+		{config.GRPCTLSEnvVar, string(cfg.GRPCTLSMode)},
+		{config.GRPCTLSCAFileEnvVar, cfg.GRPCTLSCAFile},
+		{config.GRPCTLSCertFileEnvVar, cfg.GRPCTLSCertFile},
+		{config.GRPCTLSKeyFileEnvVar, cfg.GRPCTLSKeyFile},
+		{config.GRPCTLSServerNameEnvVar, cfg.GRPCTLSServerName},
+		{config.TransportEnvVar, string(cfg.Transport)},
+	}
+	if cfg.Transport != config.TransportStdio {
+		settings = append(settings,
+			Setting{config.HTTPPortEnvVar, cfg.HTTPPort},
+			Setting{config.HTTPAuthEnabledEnvVar, strconv.FormatBool(cfg.HTTPAuthEnabled)},
+		)
+	}
+	return append(settings,
+		Setting{config.DefaultOrgEnvVar, cfg.DefaultOrg},
+		Setting{config.DefaultEnvEnvVar, cfg.DefaultEnv},
+		Setting{config.ReadOnlyEnvVar, strconv.FormatBool(cfg.ReadOnly)},
+		Setting{config.ToolsAllowEnvVar, strings.Join(cfg.ToolsAllow, ",")},
+		Setting{config.ToolsDenyEnvVar, strings.Join(cfg.ToolsDeny, ",")},
+		Setting{config.ToolTimeoutEnvVar, cfg.ToolTimeout.String()},
+		Setting{config.LogLevelEnvVar, cfg.LogLevel},
+	)
+}
+
+// MaskSecret hides a secret but for its last four characters, which are enough to
+// tell keys apart, e.g. "****c3d4 (40 characters)". Short secrets are hidden
+// entirely.
+func MaskSecret(secret string) string {
+	switch {
+	case secret == "":
+		return ""
+	case len(secret) < 16:
+		return fmt.Sprintf("**** (%d characters)", len(secret))
+	default:
+		return fmt.Sprintf("****%s (%d characters)", secret[len(secret)-4:], len(secret))
+	}
+}
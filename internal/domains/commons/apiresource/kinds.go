@@ -4,14 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
 	"unicode"
 
 	apiresourcekind "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/commons/apiresource/apiresourcekind"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -40,6 +40,7 @@ func CreateListApiResourceKindsTool() mcp.Tool {
 			Properties: map[string]interface{}{},
 			Required:   []string{},
 		},
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
 	}
 }
 
@@ -53,9 +54,9 @@ func CreateListApiResourceKindsTool() mcp.Tool {
 func HandleListApiResourceKinds(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
-	log.Printf("Tool invoked: list_api_resource_kinds")
+	slog.DebugContext(ctx, "Tool invoked")
 
 	// Build list of API resource kinds from enum
 	kinds := make([]ApiResourceKindInfo, 0)
@@ -72,7 +73,7 @@ func HandleListApiResourceKinds(
 		// Get metadata for this kind
 		meta, err := getKindMeta(kind)
 		if err != nil {
-			log.Printf("Warning: Could not get metadata for %s: %v", name, err)
+			slog.WarnContext(ctx, "Could not get metadata for API resource kind", "kind", name, "error", err)
 			// Include it anyway with basic info
 			snakeCaseKind := pascalToSnakeCase(name)
 			kinds = append(kinds, ApiResourceKindInfo{
@@ -98,17 +99,15 @@ func HandleListApiResourceKinds(
 		})
 	}
 
-	log.Printf("Tool completed: list_api_resource_kinds, returned %d kinds", len(kinds))
+	slog.DebugContext(ctx, "Tool completed", "count", len(kinds))
 
 	// Return formatted JSON response
 	resultJSON, err := json.MarshalIndent(kinds, "", "  ")
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: "Failed to marshal API resource kinds",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	return mcp.NewToolResultText(string(resultJSON)), nil
@@ -198,8 +197,8 @@ func CreateApiResourceKindsResource() mcp.Resource {
 // HandleReadApiResourceKinds handles reading the API resource kinds MCP resource.
 // This provides the same information as list_api_resource_kinds tool but as a resource
 // that agents can access automatically.
-func HandleReadApiResourceKinds(request mcp.ReadResourceRequest) ([]interface{}, error) {
-	log.Printf("Resource read: api-resource-kinds")
+func HandleReadApiResourceKinds(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	slog.DebugContext(ctx, "Resource read", "resource", "api-resource-kinds")
 
 	// Build list of API resource kinds from enum
 	kinds := make([]ApiResourceKindInfo, 0)
@@ -216,7 +215,7 @@ func HandleReadApiResourceKinds(request mcp.ReadResourceRequest) ([]interface{},
 		// Get metadata for this kind
 		meta, err := getKindMeta(kind)
 		if err != nil {
-			log.Printf("Warning: Could not get metadata for %s: %v", name, err)
+			slog.WarnContext(ctx, "Could not get metadata for API resource kind", "kind", name, "error", err)
 			// Include it anyway with basic info
 			snakeCaseKind := pascalToSnakeCase(name)
 			kinds = append(kinds, ApiResourceKindInfo{
@@ -248,15 +247,13 @@ func HandleReadApiResourceKinds(request mcp.ReadResourceRequest) ([]interface{},
 		return nil, fmt.Errorf("failed to marshal kinds: %w", err)
 	}
 
-	log.Printf("Resource read completed: api-resource-kinds, returned %d kinds", len(kinds))
+	slog.DebugContext(ctx, "Resource read completed", "resource", "api-resource-kinds", "count", len(kinds))
 
-	return []interface{}{
+	return []mcp.ResourceContents{
 		mcp.TextResourceContents{
-			ResourceContents: mcp.ResourceContents{
-				URI:      request.Params.URI,
-				MIMEType: "application/json",
-			},
-			Text: string(jsonData),
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(jsonData),
 		},
 	}, nil
 }
@@ -2,23 +2,22 @@ package apiresource
 
 import (
 	"context"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/plantoncloud/mcp-server-planton/internal/common/auth"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 )
 
 // RegisterTools registers all API resource tools and resources with the MCP server.
-func RegisterTools(s *server.MCPServer, cfg *config.Config) {
+func RegisterTools(s *server.MCPServer, factory clientfactory.Factory) {
 	// Register resources first (makes them available to agents immediately)
 	registerKindsResource(s)
 
 	// Register tools
-	registerListKindsTool(s, cfg)
+	registerListKindsTool(s, factory)
 
-	log.Println("Registered 1 resource and 1 API resource tool")
+	slog.Debug("Registered 1 resource and 1 API resource tool")
 }
 
 // registerKindsResource registers the API resource kinds MCP resource.
@@ -27,17 +26,16 @@ func registerKindsResource(s *server.MCPServer) {
 		CreateApiResourceKindsResource(),
 		HandleReadApiResourceKinds,
 	)
-	log.Println("  - planton://api-resource-kinds (resource)")
+	slog.Debug("Registered resource", "uri", "planton://api-resource-kinds")
 }
 
 // registerListKindsTool registers the list_api_resource_kinds tool.
-func registerListKindsTool(s *server.MCPServer, cfg *config.Config) {
+func registerListKindsTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateListApiResourceKindsTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleListApiResourceKinds(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleListApiResourceKinds(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - list_api_resource_kinds")
+	slog.Debug("Registered tool", "tool", "list_api_resource_kinds")
 }
@@ -1,19 +1,19 @@
 package commons
 
 import (
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/domains/commons/apiresource"
 )
 
 // RegisterTools registers all Commons domain tools with the MCP server.
-func RegisterTools(s *server.MCPServer, cfg *config.Config) {
-	log.Println("Registering Commons tools...")
+func RegisterTools(s *server.MCPServer, factory clientfactory.Factory) {
+	slog.Debug("Registering Commons tools...")
 
 	// Register API resource tools
-	apiresource.RegisterTools(s, cfg)
+	apiresource.RegisterTools(s, factory)
 
-	log.Println("Commons tools registration complete")
+	slog.Debug("Commons tools registration complete")
 }
@@ -3,16 +3,12 @@ package clients
 import (
 	"context"
 	"fmt"
-	"log"
-	"strings"
+	"log/slog"
 
 	githubcredentialv1grpc "buf.build/gen/go/blintora/apis/grpc/go/ai/planton/connect/githubcredential/v1/githubcredentialv1grpc"
 	"buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/commons/apiresource"
 	githubcredentialv1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/connect/githubcredential/v1"
-	commonauth "github.com/plantoncloud/mcp-server-planton/internal/common/auth"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/grpcpool"
 )
 
 // GithubCredentialClient is a gRPC client for querying Planton Cloud GitHub Credential resources.
@@ -22,13 +18,13 @@ import (
 // API key and enforce Fine-Grained Authorization (FGA) checks based on the
 // user's actual permissions.
 type GithubCredentialClient struct {
-	conn   *grpc.ClientConn
+	conn   *grpcpool.Conn
 	client githubcredentialv1grpc.GithubCredentialQueryControllerClient
 }
 
 // GithubQueryClient is a gRPC client for querying GitHub information via Planton Cloud.
 type GithubQueryClient struct {
-	conn   *grpc.ClientConn
+	conn   *grpcpool.Conn
 	client githubcredentialv1grpc.GithubQueryControllerClient
 }
 
@@ -40,26 +36,8 @@ type GithubQueryClient struct {
 //
 // Returns a GithubCredentialClient and any error encountered during connection setup.
 func NewGithubCredentialClient(grpcEndpoint, apiKey string) (*GithubCredentialClient, error) {
-	// Determine transport credentials based on endpoint port
-	var transportCreds credentials.TransportCredentials
-	if strings.HasSuffix(grpcEndpoint, ":443") {
-		// Use TLS for port 443 (production endpoints)
-		transportCreds = credentials.NewTLS(nil)
-		log.Printf("Using TLS transport for endpoint: %s", grpcEndpoint)
-	} else {
-		// Use insecure for other ports (local development)
-		transportCreds = insecure.NewCredentials()
-		log.Printf("Using insecure transport for endpoint: %s", grpcEndpoint)
-	}
-
-	// Create gRPC dial options with per-RPC credentials (matches CLI pattern)
-	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(transportCreds),
-		grpc.WithPerRPCCredentials(commonauth.NewTokenAuth(apiKey)),
-	}
-
-	// Establish connection
-	conn, err := grpc.NewClient(grpcEndpoint, opts...)
+	// Borrow the shared connection to the endpoint; the API key is attached per RPC
+	conn, err := grpcpool.Dial(grpcEndpoint, apiKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
 	}
@@ -67,7 +45,7 @@ func NewGithubCredentialClient(grpcEndpoint, apiKey string) (*GithubCredentialCl
 	// Create GitHub credential query client
 	client := githubcredentialv1grpc.NewGithubCredentialQueryControllerClient(conn)
 
-	log.Printf("GithubCredentialClient initialized for endpoint: %s", grpcEndpoint)
+	slog.Debug("GithubCredentialClient initialized", "endpoint", grpcEndpoint)
 
 	return &GithubCredentialClient{
 		conn:   conn,
@@ -87,7 +65,7 @@ func NewGithubCredentialClient(grpcEndpoint, apiKey string) (*GithubCredentialCl
 //
 // Returns the full GithubCredential object or an error.
 func (c *GithubCredentialClient) GetById(ctx context.Context, credentialID string) (*githubcredentialv1.GithubCredential, error) {
-	log.Printf("Querying GitHub credential by ID: %s", credentialID)
+	slog.DebugContext(ctx, "Querying GitHub credential by ID", "credential_id", credentialID)
 
 	// Create request
 	req := &apiresource.ApiResourceId{
@@ -97,11 +75,11 @@ func (c *GithubCredentialClient) GetById(ctx context.Context, credentialID strin
 	// Make gRPC call (interceptor attaches API key automatically)
 	resp, err := c.client.Get(ctx, req)
 	if err != nil {
-		log.Printf("gRPC error querying GitHub credential %s: %v", credentialID, err)
+		slog.DebugContext(ctx, "gRPC error querying GitHub credential", "credential_id", credentialID, "error", err)
 		return nil, err
 	}
 
-	log.Printf("Successfully retrieved GitHub credential: %s", credentialID)
+	slog.DebugContext(ctx, "Successfully retrieved GitHub credential", "credential_id", credentialID)
 
 	return resp, nil
 }
@@ -115,7 +93,7 @@ func (c *GithubCredentialClient) GetById(ctx context.Context, credentialID strin
 //
 // Returns the full GithubCredential object or an error.
 func (c *GithubCredentialClient) GetByOrgBySlug(ctx context.Context, orgID, slug string) (*githubcredentialv1.GithubCredential, error) {
-	log.Printf("Querying GitHub credential by org: %s, slug: %s", orgID, slug)
+	slog.DebugContext(ctx, "Querying GitHub credential by org and slug", "org_id", orgID, "slug", slug)
 
 	// Create request
 	req := &apiresource.ApiResourceByOrgBySlugRequest{
@@ -126,40 +104,20 @@ func (c *GithubCredentialClient) GetByOrgBySlug(ctx context.Context, orgID, slug
 	// Make gRPC call (interceptor attaches API key automatically)
 	resp, err := c.client.GetByOrgBySlug(ctx, req)
 	if err != nil {
-		log.Printf("gRPC error querying GitHub credential by org/slug: %v", err)
+		slog.DebugContext(ctx, "gRPC error querying GitHub credential by org and slug", "org_id", orgID, "slug", slug, "error", err)
 		return nil, err
 	}
 
-	log.Printf("Successfully retrieved GitHub credential: %s/%s", orgID, slug)
+	slog.DebugContext(ctx, "Successfully retrieved GitHub credential", "org_id", orgID, "slug", slug)
 
 	return resp, nil
 }
 
-// NewGithubCredentialClientFromContext creates a new GitHub Credential gRPC client
-// using the API key from the request context.
-//
-// This constructor is used in HTTP transport mode to create clients with per-user API keys
-// extracted from Authorization headers, enabling proper multi-user support with Fine-Grained
-// Authorization.
-//
-// Args:
-//   - ctx: Context containing the API key (set by HTTP authentication middleware)
-//   - grpcEndpoint: Planton Cloud APIs endpoint (e.g., "localhost:8080" or "api.live.planton.ai:443")
-//
-// Returns a GithubCredentialClient and any error encountered during connection setup.
-// Returns an error if no API key is found in the context.
-func NewGithubCredentialClientFromContext(ctx context.Context, grpcEndpoint string) (*GithubCredentialClient, error) {
-	apiKey, err := commonauth.GetAPIKey(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get API key from context: %w", err)
-	}
-	return NewGithubCredentialClient(grpcEndpoint, apiKey)
-}
-
-// Close closes the gRPC connection.
+// Close gives the pooled gRPC connection back. The shared connection stays open
+// for other calls.
 func (c *GithubCredentialClient) Close() error {
 	if c.conn != nil {
-		log.Println("Closing GithubCredentialClient connection")
+		slog.Debug("Releasing GithubCredentialClient connection")
 		return c.conn.Close()
 	}
 	return nil
@@ -173,26 +131,8 @@ func (c *GithubCredentialClient) Close() error {
 //
 // Returns a GithubQueryClient and any error encountered during connection setup.
 func NewGithubQueryClient(grpcEndpoint, apiKey string) (*GithubQueryClient, error) {
-	// Determine transport credentials based on endpoint port
-	var transportCreds credentials.TransportCredentials
-	if strings.HasSuffix(grpcEndpoint, ":443") {
-		// Use TLS for port 443 (production endpoints)
-		transportCreds = credentials.NewTLS(nil)
-		log.Printf("Using TLS transport for endpoint: %s", grpcEndpoint)
-	} else {
-		// Use insecure for other ports (local development)
-		transportCreds = insecure.NewCredentials()
-		log.Printf("Using insecure transport for endpoint: %s", grpcEndpoint)
-	}
-
-	// Create gRPC dial options with per-RPC credentials (matches CLI pattern)
-	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(transportCreds),
-		grpc.WithPerRPCCredentials(commonauth.NewTokenAuth(apiKey)),
-	}
-
-	// Establish connection
-	conn, err := grpc.NewClient(grpcEndpoint, opts...)
+	// Borrow the shared connection to the endpoint; the API key is attached per RPC
+	conn, err := grpcpool.Dial(grpcEndpoint, apiKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
 	}
@@ -200,7 +140,7 @@ func NewGithubQueryClient(grpcEndpoint, apiKey string) (*GithubQueryClient, erro
 	// Create GitHub query client
 	client := githubcredentialv1grpc.NewGithubQueryControllerClient(conn)
 
-	log.Printf("GithubQueryClient initialized for endpoint: %s", grpcEndpoint)
+	slog.Debug("GithubQueryClient initialized", "endpoint", grpcEndpoint)
 
 	return &GithubQueryClient{
 		conn:   conn,
@@ -216,7 +156,7 @@ func NewGithubQueryClient(grpcEndpoint, apiKey string) (*GithubQueryClient, erro
 //
 // Returns a list of GitHub repositories or an error.
 func (c *GithubQueryClient) FindGithubRepositories(ctx context.Context, credentialID string) (*githubcredentialv1.FindGithubRepositoriesResponse, error) {
-	log.Printf("Finding GitHub repositories for credential: %s", credentialID)
+	slog.DebugContext(ctx, "Finding GitHub repositories", "credential_id", credentialID)
 
 	// Create request
 	req := &githubcredentialv1.FindGithubRepositoriesInput{
@@ -226,11 +166,11 @@ func (c *GithubQueryClient) FindGithubRepositories(ctx context.Context, credenti
 	// Make gRPC call (interceptor attaches API key automatically)
 	resp, err := c.client.FindGithubRepositories(ctx, req)
 	if err != nil {
-		log.Printf("gRPC error finding GitHub repositories: %v", err)
+		slog.DebugContext(ctx, "gRPC error finding GitHub repositories", "credential_id", credentialID, "error", err)
 		return nil, err
 	}
 
-	log.Printf("Successfully retrieved %d GitHub repositories", len(resp.GetRepos()))
+	slog.DebugContext(ctx, "Successfully retrieved GitHub repositories", "count", len(resp.GetRepos()))
 
 	return resp, nil
 }
@@ -243,7 +183,7 @@ func (c *GithubQueryClient) FindGithubRepositories(ctx context.Context, credenti
 //
 // Returns a GithubInstallationToken with token and expiry or an error.
 func (c *GithubQueryClient) GetInstallationToken(ctx context.Context, credentialID string) (*githubcredentialv1.GithubInstallationToken, error) {
-	log.Printf("Getting installation token for credential: %s", credentialID)
+	slog.DebugContext(ctx, "Getting installation token", "credential_id", credentialID)
 
 	// Create request
 	req := &apiresource.ApiResourceId{
@@ -253,36 +193,20 @@ func (c *GithubQueryClient) GetInstallationToken(ctx context.Context, credential
 	// Make gRPC call (interceptor attaches API key automatically)
 	resp, err := c.client.GetInstallationToken(ctx, req)
 	if err != nil {
-		log.Printf("gRPC error getting installation token: %v", err)
+		slog.DebugContext(ctx, "gRPC error getting installation token", "credential_id", credentialID, "error", err)
 		return nil, err
 	}
 
-	log.Printf("Successfully retrieved installation token for credential: %s", credentialID)
+	slog.DebugContext(ctx, "Successfully retrieved installation token", "credential_id", credentialID)
 
 	return resp, nil
 }
 
-// NewGithubQueryClientFromContext creates a new GitHub Query gRPC client
-// using the API key from the request context.
-//
-// Args:
-//   - ctx: Context containing the API key (set by HTTP authentication middleware)
-//   - grpcEndpoint: Planton Cloud APIs endpoint (e.g., "localhost:8080" or "api.live.planton.ai:443")
-//
-// Returns a GithubQueryClient and any error encountered during connection setup.
-// Returns an error if no API key is found in the context.
-func NewGithubQueryClientFromContext(ctx context.Context, grpcEndpoint string) (*GithubQueryClient, error) {
-	apiKey, err := commonauth.GetAPIKey(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get API key from context: %w", err)
-	}
-	return NewGithubQueryClient(grpcEndpoint, apiKey)
-}
-
-// Close closes the gRPC connection.
+// Close gives the pooled gRPC connection back. The shared connection stays open
+// for other calls.
 func (c *GithubQueryClient) Close() error {
 	if c.conn != nil {
-		log.Println("Closing GithubQueryClient connection")
+		slog.Debug("Releasing GithubQueryClient connection")
 		return c.conn.Close()
 	}
 	return nil
@@ -4,13 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
-	connectclients "github.com/plantoncloud/mcp-server-planton/internal/domains/connect/clients"
-	servicehubclients "github.com/plantoncloud/mcp-server-planton/internal/domains/servicehub/clients"
 )
 
 // GithubCredentialInfo contains GitHub credential information (metadata only, no secrets).
@@ -42,6 +40,7 @@ func CreateGetGithubCredentialForServiceTool() mcp.Tool {
 			},
 			Required: []string{"service_id"},
 		},
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
 	}
 }
 
@@ -49,88 +48,66 @@ func CreateGetGithubCredentialForServiceTool() mcp.Tool {
 func HandleGetGithubCredentialForService(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
-	log.Printf("Tool invoked: get_github_credential_for_service")
+	slog.DebugContext(ctx, "Tool invoked")
 
 	// Extract service_id from arguments
 	serviceID, ok := arguments["service_id"].(string)
 	if !ok || serviceID == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "service_id is required",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	// Create Service client to get service details
-	serviceClient, err := servicehubclients.NewServiceClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
+	serviceClient, err := factory.ServiceClient(ctx)
 	if err != nil {
-		serviceClient, err = servicehubclients.NewServiceClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			errResp := errors.ErrorResponse{
-				Error:   "CLIENT_ERROR",
-				Message: fmt.Sprintf("Failed to create service gRPC client: %v", err),
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
-		}
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeClientError,
+			Message: fmt.Sprintf("Failed to create service gRPC client: %v", err),
+		}), nil
 	}
 	defer serviceClient.Close()
 
 	// Get service to extract GitHub credential ID
 	service, err := serviceClient.GetById(ctx, serviceID)
 	if err != nil {
-		return errors.HandleGRPCError(err, serviceID), nil
+		return errors.HandleGRPCError(ctx, err, errors.Resource{ResourceID: serviceID}), nil
 	}
 
 	// Extract GitHub credential ID from service spec
 	githubRepo := service.GetSpec().GetGitRepo().GetGithubRepo()
 	if githubRepo == nil {
-		errResp := errors.ErrorResponse{
-			Error:   "NOT_FOUND",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeNotFound,
 			Message: "Service is not connected to a GitHub repository",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	credentialID := githubRepo.GetGithubCredentialId()
 	if credentialID == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "NOT_FOUND",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeNotFound,
 			Message: "Service does not have a GitHub credential configured",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	// Create GitHub credential client
-	credClient, err := connectclients.NewGithubCredentialClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
+	credClient, err := factory.GithubCredentialClient(ctx)
 	if err != nil {
-		credClient, err = connectclients.NewGithubCredentialClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			errResp := errors.ErrorResponse{
-				Error:   "CLIENT_ERROR",
-				Message: fmt.Sprintf("Failed to create GitHub credential gRPC client: %v", err),
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
-		}
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeClientError,
+			Message: fmt.Sprintf("Failed to create GitHub credential gRPC client: %v", err),
+		}), nil
 	}
 	defer credClient.Close()
 
 	// Get GitHub credential
 	credential, err := credClient.GetById(ctx, credentialID)
 	if err != nil {
-		return errors.HandleGRPCError(err, credentialID), nil
+		return errors.HandleGRPCError(ctx, err, errors.Resource{ResourceID: credentialID}), nil
 	}
 
 	// Convert to info struct (metadata only, no secrets)
@@ -146,17 +123,15 @@ func HandleGetGithubCredentialForService(
 		ConnectionHost: credential.GetSpec().GetGithubConnectionHost(),
 	}
 
-	log.Printf("Tool completed: get_github_credential_for_service, credential: %s", credentialID)
+	slog.DebugContext(ctx, "Tool completed", "credential_id", credentialID)
 
 	// Return formatted JSON response
 	resultJSON, err := json.MarshalIndent(credInfo, "", "  ")
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: fmt.Sprintf("Failed to marshal response: %v", err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	return mcp.NewToolResultText(string(resultJSON)), nil
@@ -183,6 +158,7 @@ func CreateGetGithubCredentialByOrgBySlugTool() mcp.Tool {
 			},
 			Required: []string{"org_id", "slug"},
 		},
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
 	}
 }
 
@@ -190,54 +166,42 @@ func CreateGetGithubCredentialByOrgBySlugTool() mcp.Tool {
 func HandleGetGithubCredentialByOrgBySlug(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
-	log.Printf("Tool invoked: get_github_credential_by_org_by_slug")
+	slog.DebugContext(ctx, "Tool invoked")
 
 	// Extract arguments
 	orgID, okOrg := arguments["org_id"].(string)
 	slug, okSlug := arguments["slug"].(string)
 
 	if !okOrg || orgID == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "org_id is required",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	if !okSlug || slug == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "slug is required",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	// Create GitHub credential client
-	client, err := connectclients.NewGithubCredentialClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
+	client, err := factory.GithubCredentialClient(ctx)
 	if err != nil {
-		client, err = connectclients.NewGithubCredentialClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			errResp := errors.ErrorResponse{
-				Error:   "CLIENT_ERROR",
-				Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
-		}
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeClientError,
+			Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
+		}), nil
 	}
 	defer client.Close()
 
 	// Get GitHub credential
 	credential, err := client.GetByOrgBySlug(ctx, orgID, slug)
 	if err != nil {
-		return errors.HandleGRPCError(err, fmt.Sprintf("%s/%s", orgID, slug)), nil
+		return errors.HandleGRPCError(ctx, err, errors.Resource{OrgID: orgID, Name: slug}), nil
 	}
 
 	// Convert to info struct (metadata only, no secrets)
@@ -253,17 +217,15 @@ func HandleGetGithubCredentialByOrgBySlug(
 		ConnectionHost: credential.GetSpec().GetGithubConnectionHost(),
 	}
 
-	log.Printf("Tool completed: get_github_credential_by_org_by_slug, credential: %s/%s", orgID, slug)
+	slog.DebugContext(ctx, "Tool completed", "org_id", orgID, "slug", slug)
 
 	// Return formatted JSON response
 	resultJSON, err := json.MarshalIndent(credInfo, "", "  ")
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: fmt.Sprintf("Failed to marshal response: %v", err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	return mcp.NewToolResultText(string(resultJSON)), nil
@@ -4,12 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
-	"github.com/plantoncloud/mcp-server-planton/internal/domains/connect/clients"
 )
 
 // GithubInstallationTokenInfo contains installation token for Git operations.
@@ -35,6 +34,12 @@ func CreateGetGithubInstallationTokenTool() mcp.Tool {
 			},
 			Required: []string{"credential_id"},
 		},
+		// Issues a credential with write access to the repositories, so the tool is
+		// not read-only although it changes no Planton resource
+		Annotations: mcp.ToolAnnotation{
+			ReadOnlyHint:    mcp.ToBoolPtr(false),
+			DestructiveHint: mcp.ToBoolPtr(false),
+		},
 	}
 }
 
@@ -42,43 +47,33 @@ func CreateGetGithubInstallationTokenTool() mcp.Tool {
 func HandleGetGithubInstallationToken(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
-	log.Printf("Tool invoked: get_github_installation_token")
+	slog.DebugContext(ctx, "Tool invoked")
 
 	// Extract credential_id from arguments
 	credentialID, ok := arguments["credential_id"].(string)
 	if !ok || credentialID == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "credential_id is required",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	// Create GitHub query client
-	client, err := clients.NewGithubQueryClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
+	client, err := factory.GithubQueryClient(ctx)
 	if err != nil {
-		client, err = clients.NewGithubQueryClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			errResp := errors.ErrorResponse{
-				Error:   "CLIENT_ERROR",
-				Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
-		}
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeClientError,
+			Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
+		}), nil
 	}
 	defer client.Close()
 
 	// Get installation token
 	tokenResp, err := client.GetInstallationToken(ctx, credentialID)
 	if err != nil {
-		return errors.HandleGRPCError(err, credentialID), nil
+		return errors.HandleGRPCError(ctx, err, errors.Resource{ResourceID: credentialID}), nil
 	}
 
 	// Format expiry timestamp
@@ -90,17 +85,15 @@ func HandleGetGithubInstallationToken(
 		ExpiresAt: expiresAtStr,
 	}
 
-	log.Printf("Tool completed: get_github_installation_token, credential: %s", credentialID)
+	slog.DebugContext(ctx, "Tool completed", "credential_id", credentialID)
 
 	// Return formatted JSON response
 	resultJSON, err := json.MarshalIndent(tokenInfo, "", "  ")
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: fmt.Sprintf("Failed to marshal response: %v", err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	return mcp.NewToolResultText(string(resultJSON)), nil
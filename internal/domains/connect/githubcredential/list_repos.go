@@ -4,12 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
-	"github.com/plantoncloud/mcp-server-planton/internal/domains/connect/clients"
 )
 
 // GithubRepositoryInfo contains GitHub repository information.
@@ -36,6 +35,7 @@ func CreateListGithubRepositoriesTool() mcp.Tool {
 			},
 			Required: []string{"credential_id"},
 		},
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
 	}
 }
 
@@ -43,43 +43,33 @@ func CreateListGithubRepositoriesTool() mcp.Tool {
 func HandleListGithubRepositories(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
-	log.Printf("Tool invoked: list_github_repositories")
+	slog.DebugContext(ctx, "Tool invoked")
 
 	// Extract credential_id from arguments
 	credentialID, ok := arguments["credential_id"].(string)
 	if !ok || credentialID == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "credential_id is required",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	// Create GitHub query client
-	client, err := clients.NewGithubQueryClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
+	client, err := factory.GithubQueryClient(ctx)
 	if err != nil {
-		client, err = clients.NewGithubQueryClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			errResp := errors.ErrorResponse{
-				Error:   "CLIENT_ERROR",
-				Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
-		}
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeClientError,
+			Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
+		}), nil
 	}
 	defer client.Close()
 
 	// Find GitHub repositories
 	repoList, err := client.FindGithubRepositories(ctx, credentialID)
 	if err != nil {
-		return errors.HandleGRPCError(err, credentialID), nil
+		return errors.HandleGRPCError(ctx, err, errors.Resource{ResourceID: credentialID}), nil
 	}
 
 	// Convert to simple structs
@@ -94,21 +84,18 @@ func HandleListGithubRepositories(
 		repos = append(repos, repoInfo)
 	}
 
-	log.Printf(
-		"Tool completed: list_github_repositories, returned %d repositories for credential %s",
-		len(repos),
-		credentialID,
+	slog.DebugContext(ctx, "Tool completed",
+		"credential_id", credentialID,
+		"count", len(repos),
 	)
 
 	// Return formatted JSON response
 	resultJSON, err := json.MarshalIndent(repos, "", "  ")
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: fmt.Sprintf("Failed to marshal response: %v", err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	return mcp.NewToolResultText(string(resultJSON)), nil
@@ -2,68 +2,63 @@ package githubcredential
 
 import (
 	"context"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/plantoncloud/mcp-server-planton/internal/common/auth"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 )
 
 // RegisterTools registers all GitHub credential tools with the MCP server.
-func RegisterTools(s *server.MCPServer, cfg *config.Config) {
-	registerGetGithubCredentialForServiceTool(s, cfg)
-	registerGetGithubCredentialByOrgBySlugTool(s, cfg)
-	registerListGithubRepositoriesTool(s, cfg)
-	registerGetGithubInstallationTokenTool(s, cfg)
+func RegisterTools(s *server.MCPServer, factory clientfactory.Factory) {
+	registerGetGithubCredentialForServiceTool(s, factory)
+	registerGetGithubCredentialByOrgBySlugTool(s, factory)
+	registerListGithubRepositoriesTool(s, factory)
+	registerGetGithubInstallationTokenTool(s, factory)
 
-	log.Println("Registered 4 GitHub credential tools")
+	slog.Debug("Registered 4 GitHub credential tools")
 }
 
 // registerGetGithubCredentialForServiceTool registers the get_github_credential_for_service tool.
-func registerGetGithubCredentialForServiceTool(s *server.MCPServer, cfg *config.Config) {
+func registerGetGithubCredentialForServiceTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateGetGithubCredentialForServiceTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleGetGithubCredentialForService(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleGetGithubCredentialForService(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - get_github_credential_for_service")
+	slog.Debug("Registered tool", "tool", "get_github_credential_for_service")
 }
 
 // registerGetGithubCredentialByOrgBySlugTool registers the get_github_credential_by_org_by_slug tool.
-func registerGetGithubCredentialByOrgBySlugTool(s *server.MCPServer, cfg *config.Config) {
+func registerGetGithubCredentialByOrgBySlugTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateGetGithubCredentialByOrgBySlugTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleGetGithubCredentialByOrgBySlug(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleGetGithubCredentialByOrgBySlug(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - get_github_credential_by_org_by_slug")
+	slog.Debug("Registered tool", "tool", "get_github_credential_by_org_by_slug")
 }
 
 // registerListGithubRepositoriesTool registers the list_github_repositories tool.
-func registerListGithubRepositoriesTool(s *server.MCPServer, cfg *config.Config) {
+func registerListGithubRepositoriesTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateListGithubRepositoriesTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleListGithubRepositories(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleListGithubRepositories(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - list_github_repositories")
+	slog.Debug("Registered tool", "tool", "list_github_repositories")
 }
 
 // registerGetGithubInstallationTokenTool registers the get_github_installation_token tool.
-func registerGetGithubInstallationTokenTool(s *server.MCPServer, cfg *config.Config) {
+func registerGetGithubInstallationTokenTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateGetGithubInstallationTokenTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleGetGithubInstallationToken(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleGetGithubInstallationToken(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - get_github_installation_token")
+	slog.Debug("Registered tool", "tool", "get_github_installation_token")
 }
@@ -1,19 +1,19 @@
 package connect
 
 import (
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/domains/connect/githubcredential"
 )
 
 // RegisterTools registers all Connect domain tools with the MCP server.
-func RegisterTools(s *server.MCPServer, cfg *config.Config) {
-	log.Println("Registering Connect tools...")
+func RegisterTools(s *server.MCPServer, factory clientfactory.Factory) {
+	slog.Debug("Registering Connect tools...")
 
 	// Register GitHub credential tools
-	githubcredential.RegisterTools(s, cfg)
+	githubcredential.RegisterTools(s, factory)
 
-	log.Println("Connect tools registration complete")
+	slog.Debug("Connect tools registration complete")
 }
@@ -3,8 +3,7 @@ package clients
 import (
 	"context"
 	"fmt"
-	"log"
-	"strings"
+	"log/slog"
 
 	cloudresourcev1grpc "buf.build/gen/go/blintora/apis/grpc/go/ai/planton/infrahub/cloudresource/v1/cloudresourcev1grpc"
 	cloudresourcesearchgrpc "buf.build/gen/go/blintora/apis/grpc/go/ai/planton/search/v1/infrahub/cloudresource/cloudresourcegrpc"
@@ -12,10 +11,7 @@ import (
 	"buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/search/v1/apiresource"
 	cloudresourcesearch "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/search/v1/infrahub/cloudresource"
 	cloudresourcekind "buf.build/gen/go/project-planton/apis/protocolbuffers/go/org/project_planton/shared/cloudresourcekind"
-	commonauth "github.com/plantoncloud/mcp-server-planton/internal/common/auth"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/grpcpool"
 )
 
 // CloudResourceQueryClient is a gRPC client for querying Planton Cloud Cloud Resources.
@@ -25,7 +21,7 @@ import (
 // API key and enforce Fine-Grained Authorization (FGA) checks based on the
 // user's actual permissions.
 type CloudResourceQueryClient struct {
-	conn   *grpc.ClientConn
+	conn   *grpcpool.Conn
 	client cloudresourcev1grpc.CloudResourceQueryControllerClient
 }
 
@@ -37,26 +33,8 @@ type CloudResourceQueryClient struct {
 //
 // Returns a CloudResourceQueryClient and any error encountered during connection setup.
 func NewCloudResourceQueryClient(grpcEndpoint, apiKey string) (*CloudResourceQueryClient, error) {
-	// Determine transport credentials based on endpoint port
-	var transportCreds credentials.TransportCredentials
-	if strings.HasSuffix(grpcEndpoint, ":443") {
-		// Use TLS for port 443 (production endpoints)
-		transportCreds = credentials.NewTLS(nil)
-		log.Printf("Using TLS transport for endpoint: %s", grpcEndpoint)
-	} else {
-		// Use insecure for other ports (local development)
-		transportCreds = insecure.NewCredentials()
-		log.Printf("Using insecure transport for endpoint: %s", grpcEndpoint)
-	}
-
-	// Create gRPC dial options with per-RPC credentials (matches CLI pattern)
-	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(transportCreds),
-		grpc.WithPerRPCCredentials(commonauth.NewTokenAuth(apiKey)),
-	}
-
-	// Establish connection
-	conn, err := grpc.NewClient(grpcEndpoint, opts...)
+	// Borrow the shared connection to the endpoint; the API key is attached per RPC
+	conn, err := grpcpool.Dial(grpcEndpoint, apiKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
 	}
@@ -64,7 +42,7 @@ func NewCloudResourceQueryClient(grpcEndpoint, apiKey string) (*CloudResourceQue
 	// Create cloud resource query client
 	client := cloudresourcev1grpc.NewCloudResourceQueryControllerClient(conn)
 
-	log.Printf("CloudResourceQueryClient initialized for endpoint: %s", grpcEndpoint)
+	slog.Debug("CloudResourceQueryClient initialized", "endpoint", grpcEndpoint)
 
 	return &CloudResourceQueryClient{
 		conn:   conn,
@@ -84,7 +62,7 @@ func NewCloudResourceQueryClient(grpcEndpoint, apiKey string) (*CloudResourceQue
 //
 // Returns the full CloudResource object or an error.
 func (c *CloudResourceQueryClient) GetById(ctx context.Context, resourceID string) (*cloudresourcev1.CloudResource, error) {
-	log.Printf("Querying cloud resource by ID: %s", resourceID)
+	slog.DebugContext(ctx, "Querying cloud resource by ID", "resource_id", resourceID)
 
 	// Create request
 	req := &cloudresourcev1.CloudResourceId{
@@ -94,40 +72,20 @@ func (c *CloudResourceQueryClient) GetById(ctx context.Context, resourceID strin
 	// Make gRPC call (interceptor attaches API key automatically)
 	resp, err := c.client.Get(ctx, req)
 	if err != nil {
-		log.Printf("gRPC error querying cloud resource %s: %v", resourceID, err)
+		slog.DebugContext(ctx, "gRPC error querying cloud resource", "resource_id", resourceID, "error", err)
 		return nil, err
 	}
 
-	log.Printf("Successfully retrieved cloud resource: %s", resourceID)
+	slog.DebugContext(ctx, "Successfully retrieved cloud resource", "resource_id", resourceID)
 
 	return resp, nil
 }
 
-// NewCloudResourceQueryClientFromContext creates a new Cloud Resource Query gRPC client
-// using the API key from the request context.
-//
-// This constructor is used in HTTP transport mode to create clients with per-user API keys
-// extracted from Authorization headers, enabling proper multi-user support with Fine-Grained
-// Authorization.
-//
-// Args:
-//   - ctx: Context containing the API key (set by HTTP authentication middleware)
-//   - grpcEndpoint: Planton Cloud APIs endpoint (e.g., "localhost:8080" or "api.live.planton.ai:443")
-//
-// Returns a CloudResourceQueryClient and any error encountered during connection setup.
-// Returns an error if no API key is found in the context.
-func NewCloudResourceQueryClientFromContext(ctx context.Context, grpcEndpoint string) (*CloudResourceQueryClient, error) {
-	apiKey, err := commonauth.GetAPIKey(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get API key from context: %w", err)
-	}
-	return NewCloudResourceQueryClient(grpcEndpoint, apiKey)
-}
-
-// Close closes the gRPC connection.
+// Close gives the pooled gRPC connection back. The shared connection stays open
+// for other calls.
 func (c *CloudResourceQueryClient) Close() error {
 	if c.conn != nil {
-		log.Println("Closing CloudResourceQueryClient connection")
+		slog.Debug("Releasing CloudResourceQueryClient connection")
 		return c.conn.Close()
 	}
 	return nil
@@ -140,7 +98,7 @@ func (c *CloudResourceQueryClient) Close() error {
 // API key and enforce Fine-Grained Authorization (FGA) checks based on the
 // user's actual permissions.
 type CloudResourceSearchClient struct {
-	conn   *grpc.ClientConn
+	conn   *grpcpool.Conn
 	client cloudresourcesearchgrpc.CloudResourceSearchQueryControllerClient
 }
 
@@ -152,26 +110,8 @@ type CloudResourceSearchClient struct {
 //
 // Returns a CloudResourceSearchClient and any error encountered during connection setup.
 func NewCloudResourceSearchClient(grpcEndpoint, apiKey string) (*CloudResourceSearchClient, error) {
-	// Determine transport credentials based on endpoint port
-	var transportCreds credentials.TransportCredentials
-	if strings.HasSuffix(grpcEndpoint, ":443") {
-		// Use TLS for port 443 (production endpoints)
-		transportCreds = credentials.NewTLS(nil)
-		log.Printf("Using TLS transport for endpoint: %s", grpcEndpoint)
-	} else {
-		// Use insecure for other ports (local development)
-		transportCreds = insecure.NewCredentials()
-		log.Printf("Using insecure transport for endpoint: %s", grpcEndpoint)
-	}
-
-	// Create gRPC dial options with per-RPC credentials (matches CLI pattern)
-	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(transportCreds),
-		grpc.WithPerRPCCredentials(commonauth.NewTokenAuth(apiKey)),
-	}
-
-	// Establish connection
-	conn, err := grpc.NewClient(grpcEndpoint, opts...)
+	// Borrow the shared connection to the endpoint; the API key is attached per RPC
+	conn, err := grpcpool.Dial(grpcEndpoint, apiKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
 	}
@@ -179,7 +119,7 @@ func NewCloudResourceSearchClient(grpcEndpoint, apiKey string) (*CloudResourceSe
 	// Create cloud resource search query client
 	client := cloudresourcesearchgrpc.NewCloudResourceSearchQueryControllerClient(conn)
 
-	log.Printf("CloudResourceSearchClient initialized for endpoint: %s", grpcEndpoint)
+	slog.Debug("CloudResourceSearchClient initialized", "endpoint", grpcEndpoint)
 
 	return &CloudResourceSearchClient{
 		conn:   conn,
@@ -209,8 +149,12 @@ func (c *CloudResourceSearchClient) GetCloudResourcesCanvasView(
 	kinds []cloudresourcekind.CloudResourceKind,
 	searchText string,
 ) (*cloudresourcesearch.ExploreCloudResourcesCanvasViewResponse, error) {
-	log.Printf("Querying cloud resources canvas view for org: %s, envs: %v, kinds: %v, searchText: %q",
-		orgID, envNames, kinds, searchText)
+	slog.DebugContext(ctx, "Querying cloud resources canvas view",
+		"org_id", orgID,
+		"envs", envNames,
+		"kinds", kinds,
+		"search_text", searchText,
+	)
 
 	// Create request
 	req := &cloudresourcesearch.ExploreCloudResourcesRequest{
@@ -223,11 +167,11 @@ func (c *CloudResourceSearchClient) GetCloudResourcesCanvasView(
 	// Make gRPC call (interceptor attaches API key automatically)
 	resp, err := c.client.GetCloudResourcesCanvasView(ctx, req)
 	if err != nil {
-		log.Printf("gRPC error querying cloud resources canvas view: %v", err)
+		slog.DebugContext(ctx, "gRPC error querying cloud resources canvas view", "org_id", orgID, "error", err)
 		return nil, err
 	}
 
-	log.Printf("Successfully retrieved cloud resources canvas view")
+	slog.DebugContext(ctx, "Successfully retrieved cloud resources canvas view", "org_id", orgID)
 
 	return resp, nil
 }
@@ -252,8 +196,12 @@ func (c *CloudResourceSearchClient) LookupCloudResource(
 	kind cloudresourcekind.CloudResourceKind,
 	name string,
 ) (*apiresource.ApiResourceSearchRecord, error) {
-	log.Printf("Looking up cloud resource: org=%s, env=%s, kind=%v, name=%s",
-		orgID, envName, kind, name)
+	slog.DebugContext(ctx, "Looking up cloud resource",
+		"org_id", orgID,
+		"env", envName,
+		"kind", kind.String(),
+		"name", name,
+	)
 
 	// Create request
 	req := &cloudresourcesearch.LookupCloudResourceInput{
@@ -266,40 +214,20 @@ func (c *CloudResourceSearchClient) LookupCloudResource(
 	// Make gRPC call (interceptor attaches API key automatically)
 	resp, err := c.client.LookupCloudResource(ctx, req)
 	if err != nil {
-		log.Printf("gRPC error looking up cloud resource: %v", err)
+		slog.DebugContext(ctx, "gRPC error looking up cloud resource", "org_id", orgID, "name", name, "error", err)
 		return nil, err
 	}
 
-	log.Printf("Successfully found cloud resource: %s", resp.GetId())
+	slog.DebugContext(ctx, "Successfully found cloud resource", "resource_id", resp.GetId())
 
 	return resp, nil
 }
 
-// NewCloudResourceSearchClientFromContext creates a new Cloud Resource Search gRPC client
-// using the API key from the request context.
-//
-// This constructor is used in HTTP transport mode to create clients with per-user API keys
-// extracted from Authorization headers, enabling proper multi-user support with Fine-Grained
-// Authorization.
-//
-// Args:
-//   - ctx: Context containing the API key (set by HTTP authentication middleware)
-//   - grpcEndpoint: Planton Cloud APIs endpoint (e.g., "localhost:8080" or "api.live.planton.ai:443")
-//
-// Returns a CloudResourceSearchClient and any error encountered during connection setup.
-// Returns an error if no API key is found in the context.
-func NewCloudResourceSearchClientFromContext(ctx context.Context, grpcEndpoint string) (*CloudResourceSearchClient, error) {
-	apiKey, err := commonauth.GetAPIKey(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get API key from context: %w", err)
-	}
-	return NewCloudResourceSearchClient(grpcEndpoint, apiKey)
-}
-
-// Close closes the gRPC connection.
+// Close gives the pooled gRPC connection back. The shared connection stays open
+// for other calls.
 func (c *CloudResourceSearchClient) Close() error {
 	if c.conn != nil {
-		log.Println("Closing CloudResourceSearchClient connection")
+		slog.Debug("Releasing CloudResourceSearchClient connection")
 		return c.conn.Close()
 	}
 	return nil
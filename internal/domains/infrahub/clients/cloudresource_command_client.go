@@ -3,16 +3,12 @@ package clients
 import (
 	"context"
 	"fmt"
-	"log"
-	"strings"
+	"log/slog"
 
 	cloudresourcev1grpc "buf.build/gen/go/blintora/apis/grpc/go/ai/planton/infrahub/cloudresource/v1/cloudresourcev1grpc"
 	apiresource "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/commons/apiresource"
 	cloudresourcev1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/infrahub/cloudresource/v1"
-	commonauth "github.com/plantoncloud/mcp-server-planton/internal/common/auth"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/grpcpool"
 )
 
 // CloudResourceCommandClient is a gRPC client for command operations on Planton Cloud Resources.
@@ -23,7 +19,7 @@ import (
 // and enforce Fine-Grained Authorization (FGA) checks based on the user's
 // actual permissions.
 type CloudResourceCommandClient struct {
-	conn   *grpc.ClientConn
+	conn   *grpcpool.Conn
 	client cloudresourcev1grpc.CloudResourceCommandControllerClient
 }
 
@@ -35,26 +31,8 @@ type CloudResourceCommandClient struct {
 //
 // Returns a CloudResourceCommandClient and any error encountered during connection setup.
 func NewCloudResourceCommandClient(grpcEndpoint, apiKey string) (*CloudResourceCommandClient, error) {
-	// Determine transport credentials based on endpoint port
-	var transportCreds credentials.TransportCredentials
-	if strings.HasSuffix(grpcEndpoint, ":443") {
-		// Use TLS for port 443 (production endpoints)
-		transportCreds = credentials.NewTLS(nil)
-		log.Printf("Using TLS transport for endpoint: %s", grpcEndpoint)
-	} else {
-		// Use insecure for other ports (local development)
-		transportCreds = insecure.NewCredentials()
-		log.Printf("Using insecure transport for endpoint: %s", grpcEndpoint)
-	}
-
-	// Create gRPC dial options with per-RPC credentials (matches CLI pattern)
-	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(transportCreds),
-		grpc.WithPerRPCCredentials(commonauth.NewTokenAuth(apiKey)),
-	}
-
-	// Establish connection
-	conn, err := grpc.NewClient(grpcEndpoint, opts...)
+	// Borrow the shared connection to the endpoint; the API key is attached per RPC
+	conn, err := grpcpool.Dial(grpcEndpoint, apiKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
 	}
@@ -62,7 +40,7 @@ func NewCloudResourceCommandClient(grpcEndpoint, apiKey string) (*CloudResourceC
 	// Create cloud resource command client
 	client := cloudresourcev1grpc.NewCloudResourceCommandControllerClient(conn)
 
-	log.Printf("CloudResourceCommandClient initialized for endpoint: %s", grpcEndpoint)
+	slog.Debug("CloudResourceCommandClient initialized", "endpoint", grpcEndpoint)
 
 	return &CloudResourceCommandClient{
 		conn:   conn,
@@ -82,18 +60,19 @@ func NewCloudResourceCommandClient(grpcEndpoint, apiKey string) (*CloudResourceC
 //
 // Returns the created CloudResource object or an error.
 func (c *CloudResourceCommandClient) Create(ctx context.Context, resource *cloudresourcev1.CloudResource) (*cloudresourcev1.CloudResource, error) {
-	log.Printf("Creating cloud resource: kind=%s, name=%s",
-		resource.GetSpec().GetKind().String(),
-		resource.GetMetadata().GetName())
+	slog.DebugContext(ctx, "Creating cloud resource",
+		"kind", resource.GetSpec().GetKind().String(),
+		"name", resource.GetMetadata().GetName(),
+	)
 
 	// Make gRPC call (interceptor attaches API key automatically)
 	resp, err := c.client.Create(ctx, resource)
 	if err != nil {
-		log.Printf("gRPC error creating cloud resource: %v", err)
+		slog.DebugContext(ctx, "gRPC error creating cloud resource", "error", err)
 		return nil, err
 	}
 
-	log.Printf("Successfully created cloud resource: %s", resp.GetMetadata().GetId())
+	slog.DebugContext(ctx, "Successfully created cloud resource", "resource_id", resp.GetMetadata().GetId())
 
 	return resp, nil
 }
@@ -110,18 +89,19 @@ func (c *CloudResourceCommandClient) Create(ctx context.Context, resource *cloud
 //
 // Returns the updated CloudResource object or an error.
 func (c *CloudResourceCommandClient) Update(ctx context.Context, resource *cloudresourcev1.CloudResource) (*cloudresourcev1.CloudResource, error) {
-	log.Printf("Updating cloud resource: id=%s, kind=%s",
-		resource.GetMetadata().GetId(),
-		resource.GetSpec().GetKind().String())
+	slog.DebugContext(ctx, "Updating cloud resource",
+		"resource_id", resource.GetMetadata().GetId(),
+		"kind", resource.GetSpec().GetKind().String(),
+	)
 
 	// Make gRPC call (interceptor attaches API key automatically)
 	resp, err := c.client.Update(ctx, resource)
 	if err != nil {
-		log.Printf("gRPC error updating cloud resource: %v", err)
+		slog.DebugContext(ctx, "gRPC error updating cloud resource", "resource_id", resource.GetMetadata().GetId(), "error", err)
 		return nil, err
 	}
 
-	log.Printf("Successfully updated cloud resource: %s", resp.GetMetadata().GetId())
+	slog.DebugContext(ctx, "Successfully updated cloud resource", "resource_id", resp.GetMetadata().GetId())
 
 	return resp, nil
 }
@@ -138,7 +118,7 @@ func (c *CloudResourceCommandClient) Update(ctx context.Context, resource *cloud
 //
 // Returns the deleted CloudResource object or an error.
 func (c *CloudResourceCommandClient) Delete(ctx context.Context, resourceID string) (*cloudresourcev1.CloudResource, error) {
-	log.Printf("Deleting cloud resource by ID: %s", resourceID)
+	slog.DebugContext(ctx, "Deleting cloud resource by ID", "resource_id", resourceID)
 
 	// Create delete request
 	req := &apiresource.ApiResourceDeleteInput{
@@ -148,40 +128,20 @@ func (c *CloudResourceCommandClient) Delete(ctx context.Context, resourceID stri
 	// Make gRPC call (interceptor attaches API key automatically)
 	resp, err := c.client.Delete(ctx, req)
 	if err != nil {
-		log.Printf("gRPC error deleting cloud resource %s: %v", resourceID, err)
+		slog.DebugContext(ctx, "gRPC error deleting cloud resource", "resource_id", resourceID, "error", err)
 		return nil, err
 	}
 
-	log.Printf("Successfully deleted cloud resource: %s", resourceID)
+	slog.DebugContext(ctx, "Successfully deleted cloud resource", "resource_id", resourceID)
 
 	return resp, nil
 }
 
-// NewCloudResourceCommandClientFromContext creates a new Cloud Resource Command gRPC client
-// using the API key from the request context.
-//
-// This constructor is used in HTTP transport mode to create clients with per-user API keys
-// extracted from Authorization headers, enabling proper multi-user support with Fine-Grained
-// Authorization for create, update, and delete operations.
-//
-// Args:
-//   - ctx: Context containing the API key (set by HTTP authentication middleware)
-//   - grpcEndpoint: Planton Cloud APIs endpoint (e.g., "localhost:8080" or "api.live.planton.ai:443")
-//
-// Returns a CloudResourceCommandClient and any error encountered during connection setup.
-// Returns an error if no API key is found in the context.
-func NewCloudResourceCommandClientFromContext(ctx context.Context, grpcEndpoint string) (*CloudResourceCommandClient, error) {
-	apiKey, err := commonauth.GetAPIKey(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get API key from context: %w", err)
-	}
-	return NewCloudResourceCommandClient(grpcEndpoint, apiKey)
-}
-
-// Close closes the gRPC connection.
+// Close gives the pooled gRPC connection back. The shared connection stays open
+// for other calls.
 func (c *CloudResourceCommandClient) Close() error {
 	if c.conn != nil {
-		log.Println("Closing CloudResourceCommandClient connection")
+		slog.Debug("Releasing CloudResourceCommandClient connection")
 		return c.conn.Close()
 	}
 	return nil
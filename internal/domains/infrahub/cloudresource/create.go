@@ -2,16 +2,16 @@ package cloudresource
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
 
 	apiresource "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/commons/apiresource"
+	cloudresourcev1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/infrahub/cloudresource/v1"
+	cloudresourcekind "buf.build/gen/go/project-planton/apis/protocolbuffers/go/org/project_planton/shared/cloudresourcekind"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
-	"github.com/plantoncloud/mcp-server-planton/internal/domains/infrahub/clients"
 	crinternal "github.com/plantoncloud/mcp-server-planton/internal/domains/infrahub/cloudresource/internal"
 	"google.golang.org/protobuf/encoding/protojson"
 )
@@ -30,6 +30,10 @@ RECOMMENDED WORKFLOW:
 2. Collect field values from user based on schema
 3. Call this tool with complete specification
 
+Set dry_run to check the request without creating anything: the spec is validated against
+the resource's buf.validate rules and the name is checked for uniqueness, and every problem
+is returned at once (same as 'validate_cloud_resource').
+
 ALTERNATIVE WORKFLOW (also works):
 1. Call this tool with available information
 2. If validation fails, errors will indicate missing/invalid fields with their schemas
@@ -74,9 +78,17 @@ Common cloud_resource_kind values:
 					"type":        "object",
 					"description": "Resource-specific specification (fields vary by resource type)",
 				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Validate the request and report every problem without creating the resource (default: false)",
+				},
 			},
 			Required: []string{"cloud_resource_kind", "org_id", "env_name", "resource_name", "spec"},
 		},
+		Annotations: mcp.ToolAnnotation{
+			ReadOnlyHint:    mcp.ToBoolPtr(false),
+			DestructiveHint: mcp.ToBoolPtr(false),
+		},
 	}
 }
 
@@ -87,54 +99,120 @@ Common cloud_resource_kind values:
 //  2. Normalizes the cloud_resource_kind
 //  3. Builds ApiResourceMetadata
 //  4. Wraps spec data into CloudResource using reflection
-//  5. Validates the CloudResource (returns helpful errors if validation fails)
+//  5. With dry_run, validates the CloudResource and returns the report instead
 //  6. Calls CloudResourceCommandClient to create the resource
 //  7. Unwraps and returns the created resource
 func HandleCreateCloudResource(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
+	// 1-4. Extract the arguments and wrap the spec into a CloudResource
+	req, errResult := parseCreateRequest(arguments, "create")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	slog.DebugContext(ctx, "Tool invoked",
+		"kind", req.kind.String(),
+		"org_id", req.orgID,
+		"env", req.envName,
+		"name", req.resourceName,
+	)
+
+	// 5. With dry_run, validate without calling any Command RPC
+	if dryRun, _ := arguments["dry_run"].(bool); dryRun {
+		return validationResult(ctx, validateCreateRequest(ctx, req, factory, true)), nil
+	}
+
+	// 6. Create gRPC command client with the caller's credentials and call create RPC
+	client, err := factory.CloudResourceCommandClient(ctx)
+	if err != nil {
+		return errorResponse(errors.CodeClientError, fmt.Sprintf("Failed to create gRPC client: %v", err)), nil
+	}
+	defer client.Close()
+
+	createdResource, err := client.Create(ctx, req.cloudResource)
+	if err != nil {
+		// Validation errors from the backend come back with the schema of each field
+		return mutationErrorResult(ctx, err, req.kind, errors.Resource{OrgID: req.orgID, Name: req.resourceName}), nil
+	}
+
+	// 7. Unwrap the created resource and return it as JSON
+	unwrappedResource, err := crinternal.UnwrapCloudResource(createdResource)
+	if err != nil {
+		return errorResponse(errors.CodeInternal, fmt.Sprintf("Failed to unwrap created resource: %v", err)), nil
+	}
+
+	slog.DebugContext(ctx, "Tool completed",
+		"resource_id", createdResource.GetMetadata().GetId(),
+	)
+
+	marshaler := protojson.MarshalOptions{
+		Indent:          "  ",
+		EmitUnpopulated: false,
+		UseProtoNames:   true,
+	}
+
+	resultJSON, err := marshaler.Marshal(unwrappedResource)
+	if err != nil {
+		return errorResponse(errors.CodeInternal, fmt.Sprintf("Failed to marshal resource: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// createRequest is a create_cloud_resource or validate_cloud_resource call with its
+// spec wrapped into a CloudResource.
+type createRequest struct {
+	kind          cloudresourcekind.CloudResourceKind
+	orgID         string
+	envName       string
+	resourceName  string
+	cloudResource *cloudresourcev1.CloudResource
+}
+
+// parseCreateRequest extracts the arguments of create_cloud_resource and
+// validate_cloud_resource and wraps the spec into a CloudResource.
+//
+// Args:
+//   - arguments: Tool call arguments
+//   - verb: What the call does, for error messages: "create" or "validate"
+//
+// Returns:
+//   - The request
+//   - A tool error if an argument is missing or invalid, or the spec cannot be wrapped
+func parseCreateRequest(arguments map[string]interface{}, verb string) (*createRequest, *mcp.CallToolResult) {
 	// 1. Extract cloud_resource_kind
 	kindStr, ok := arguments["cloud_resource_kind"].(string)
 	if !ok || kindStr == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return nil, errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "cloud_resource_kind is required",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		})
 	}
 
 	// 2. Normalize cloud_resource_kind
 	kind, err := crinternal.NormalizeCloudResourceKind(kindStr)
 	if err != nil {
 		// Return error with suggestions
-		errResp := map[string]interface{}{
-			"error":                     "INVALID_CLOUD_RESOURCE_KIND",
-			"message":                   err.Error(),
-			"input":                     kindStr,
-			"popular_kinds_by_category": crinternal.GetPopularKindsByCategory(),
-			"hint":                      "Call 'get_cloud_resource_schema' with a valid kind to see required fields",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		return nil, invalidKindResult(kindStr, err, "Call 'get_cloud_resource_schema' with a valid kind to see required fields")
 	}
 
 	// 3. Extract other required arguments
 	orgID, ok := arguments["org_id"].(string)
 	if !ok || orgID == "" {
-		return errorResponse("INVALID_ARGUMENT", "org_id is required"), nil
+		return nil, errorResponse(errors.CodeInvalidArgument, "org_id is required")
 	}
 
 	envName, ok := arguments["env_name"].(string)
 	if !ok || envName == "" {
-		return errorResponse("INVALID_ARGUMENT", "env_name is required"), nil
+		return nil, errorResponse(errors.CodeInvalidArgument, "env_name is required")
 	}
 
 	resourceName, ok := arguments["resource_name"].(string)
 	if !ok || resourceName == "" {
-		return errorResponse("INVALID_ARGUMENT", "resource_name is required"), nil
+		return nil, errorResponse(errors.CodeInvalidArgument, "resource_name is required")
 	}
 
 	// Normalize resource name to lowercase
@@ -143,7 +221,7 @@ func HandleCreateCloudResource(
 	// 4. Extract spec data
 	specData, ok := arguments["spec"].(map[string]interface{})
 	if !ok {
-		return errorResponse("INVALID_ARGUMENT", "spec is required and must be an object"), nil
+		return nil, errorResponse(errors.CodeInvalidArgument, "spec is required and must be an object")
 	}
 
 	// 5. Extract optional fields
@@ -157,9 +235,6 @@ func HandleCreateCloudResource(
 		}
 	}
 
-	log.Printf("Tool invoked: create_cloud_resource, kind=%s, org=%s, env=%s, name=%s",
-		kind.String(), orgID, envName, resourceName)
-
 	// 6. Build ApiResourceMetadata
 	// Note: Description is not part of ApiResourceMetadata, it's stored in the resource spec
 	metadata := &apiresource.ApiResourceMetadata{
@@ -178,68 +253,18 @@ func HandleCreateCloudResource(
 	cloudResource, err := crinternal.WrapCloudResource(kind, specData, metadata)
 	if err != nil {
 		// Wrapping failed - return error with schema guidance
-		errResp := map[string]interface{}{
-			"error":   "INVALID_SPEC_DATA",
-			"message": fmt.Sprintf("Failed to create %s resource: %v", kind.String(), err),
-			"hint":    fmt.Sprintf("Call 'get_cloud_resource_schema' with cloud_resource_kind='%s' for the complete schema", kind.String()),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
-	}
-
-	// 8. Create gRPC command client with per-user API key from context
-	// For HTTP transport: API key extracted from Authorization header
-	// For STDIO transport: API key from environment variable (fallback to config)
-	client, err := clients.NewCloudResourceCommandClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
-	if err != nil {
-		// Fallback to config API key for STDIO mode
-		client, err = clients.NewCloudResourceCommandClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			return errorResponse("CLIENT_ERROR", fmt.Sprintf("Failed to create gRPC client: %v", err)), nil
-		}
-	}
-	defer client.Close()
-
-	// 9. Call create RPC
-	createdResource, err := client.Create(ctx, cloudResource)
-	if err != nil {
-		// Handle gRPC errors (includes validation errors from backend)
-		return errors.HandleGRPCError(err, orgID), nil
-	}
-
-	// 10. Unwrap the created resource
-	unwrappedResource, err := crinternal.UnwrapCloudResource(createdResource)
-	if err != nil {
-		return errorResponse("INTERNAL_ERROR", fmt.Sprintf("Failed to unwrap created resource: %v", err)), nil
-	}
-
-	log.Printf("Tool completed: create_cloud_resource, created resource_id=%s",
-		createdResource.GetMetadata().GetId())
-
-	// 11. Return the created resource as JSON
-	marshaler := protojson.MarshalOptions{
-		Indent:          "  ",
-		EmitUnpopulated: false,
-		UseProtoNames:   true,
+		return nil, errors.ToolError(errors.ErrorResponse{
+			Error:   codeInvalidSpecData,
+			Message: fmt.Sprintf("Failed to %s %s resource: %v", verb, kind.String(), err),
+			Hint:    fmt.Sprintf("Call 'get_cloud_resource_schema' with cloud_resource_kind='%s' for the complete schema", kind.String()),
+		})
 	}
 
-	resultJSON, err := marshaler.Marshal(unwrappedResource)
-	if err != nil {
-		return errorResponse("INTERNAL_ERROR", fmt.Sprintf("Failed to marshal resource: %v", err)), nil
-	}
-
-	return mcp.NewToolResultText(string(resultJSON)), nil
-}
-
-// errorResponse creates a standard error response
-func errorResponse(errorCode, message string) *mcp.CallToolResult {
-	errResp := errors.ErrorResponse{
-		Error:   errorCode,
-		Message: message,
-	}
-	errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-	return mcp.NewToolResultText(string(errJSON))
+	return &createRequest{
+		kind:          kind,
+		orgID:         orgID,
+		envName:       envName,
+		resourceName:  resourceName,
+		cloudResource: cloudResource,
+	}, nil
 }
@@ -4,12 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
-	"github.com/plantoncloud/mcp-server-planton/internal/domains/infrahub/clients"
 	crinternal "github.com/plantoncloud/mcp-server-planton/internal/domains/infrahub/cloudresource/internal"
 	"google.golang.org/protobuf/encoding/protojson"
 )
@@ -51,6 +50,10 @@ WORKFLOW:
 			},
 			Required: []string{"resource_id"},
 		},
+		Annotations: mcp.ToolAnnotation{
+			ReadOnlyHint:    mcp.ToBoolPtr(false),
+			DestructiveHint: mcp.ToBoolPtr(true),
+		},
 	}
 }
 
@@ -63,43 +66,30 @@ WORKFLOW:
 func HandleDeleteCloudResource(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
 	// 1. Extract resource_id
 	resourceID, ok := arguments["resource_id"].(string)
 	if !ok || resourceID == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "resource_id is required",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	// 2. Extract optional parameters
 	versionMessage, _ := arguments["version_message"].(string)
 	force, _ := arguments["force"].(bool)
 
-	log.Printf("Tool invoked: delete_cloud_resource, resource_id=%s, force=%v", resourceID, force)
+	slog.DebugContext(ctx, "Tool invoked", "resource_id", resourceID, "force", force)
 
-	// 3. Create command client with per-user API key from context
-	// For HTTP transport: API key extracted from Authorization header
-	// For STDIO transport: API key from environment variable (fallback to config)
-	client, err := clients.NewCloudResourceCommandClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
+	// 3. Create command client with the caller's credentials
+	client, err := factory.CloudResourceCommandClient(ctx)
 	if err != nil {
-		// Fallback to config API key for STDIO mode
-		client, err = clients.NewCloudResourceCommandClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			errResp := errors.ErrorResponse{
-				Error:   "CLIENT_ERROR",
-				Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
-		}
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeClientError,
+			Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
+		}), nil
 	}
 	defer client.Close()
 
@@ -108,21 +98,19 @@ func HandleDeleteCloudResource(
 	// For now, we're using the simple Delete(resourceID) signature
 	deletedResource, err := client.Delete(ctx, resourceID)
 	if err != nil {
-		return errors.HandleGRPCError(err, ""), nil
+		return errors.HandleGRPCError(ctx, err, errors.Resource{ResourceID: resourceID}), nil
 	}
 
 	// 5. Unwrap the deleted resource
 	unwrappedResource, err := crinternal.UnwrapCloudResource(deletedResource)
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: fmt.Sprintf("Failed to unwrap deleted resource: %v", err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
-	log.Printf("Tool completed: delete_cloud_resource, deleted resource_id=%s", resourceID)
+	slog.DebugContext(ctx, "Tool completed", "resource_id", resourceID)
 
 	// 6. Return deletion confirmation with resource details
 	marshaler := protojson.MarshalOptions{
@@ -133,12 +121,10 @@ func HandleDeleteCloudResource(
 
 	resourceJSON, err := marshaler.Marshal(unwrappedResource)
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: fmt.Sprintf("Failed to marshal resource: %v", err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	// Wrap in a deletion response
@@ -151,12 +137,10 @@ func HandleDeleteCloudResource(
 
 	responseJSON, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: fmt.Sprintf("Failed to marshal response: %v", err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	return mcp.NewToolResultText(string(responseJSON)), nil
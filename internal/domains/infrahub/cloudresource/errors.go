@@ -0,0 +1,141 @@
+package cloudresource
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	cloudresourcekind "buf.build/gen/go/project-planton/apis/protocolbuffers/go/org/project_planton/shared/cloudresourcekind"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
+	crinternal "github.com/plantoncloud/mcp-server-planton/internal/domains/infrahub/cloudresource/internal"
+)
+
+// Error codes specific to the cloud resource tools
+const (
+	codeInvalidKind      = "INVALID_CLOUD_RESOURCE_KIND"
+	codeInvalidSpecData  = "INVALID_SPEC_DATA"
+	codeSchemaExtraction = "SCHEMA_EXTRACTION_ERROR"
+)
+
+// invalidKindResponse is the error returned for a cloud resource kind that cannot be
+// normalized, with the most common kinds as suggestions.
+type invalidKindResponse struct {
+	errors.ErrorResponse
+	Input                  string              `json:"input"`
+	PopularKindsByCategory map[string][]string `json:"popular_kinds_by_category"`
+}
+
+// invalidKindResult builds the tool error for an unknown cloud resource kind.
+//
+// Args:
+//   - input: Kind as passed by the caller
+//   - err: Normalization error
+//   - hint: What to do next
+func invalidKindResult(input string, err error, hint string) *mcp.CallToolResult {
+	return errors.ToolError(invalidKindResponse{
+		ErrorResponse: errors.ErrorResponse{
+			Error:   codeInvalidKind,
+			Message: err.Error(),
+			Hint:    hint,
+		},
+		Input:                  input,
+		PopularKindsByCategory: crinternal.GetPopularKindsByCategory(),
+	})
+}
+
+// fieldViolation is a field that failed validation, by the backend or locally, with
+// the schema of the field so the caller can fix it without looking the schema up.
+type fieldViolation struct {
+	errors.FieldViolation
+	// SpecPath is the path of the field in the spec argument, e.g. "spec.cidr_block".
+	// Empty for fields outside the spec, such as metadata.name.
+	SpecPath string                  `json:"spec_path,omitempty"`
+	Schema   *crinternal.SchemaField `json:"schema,omitempty"`
+}
+
+// validationErrorResponse is the error returned when the backend rejects a cloud
+// resource with field violations. Its FieldViolations replace the ones of the
+// embedded ErrorResponse.
+type validationErrorResponse struct {
+	errors.ErrorResponse
+	FieldViolations []fieldViolation `json:"field_violations"`
+}
+
+// mutationErrorResult builds the tool error for a failed create or update of a cloud
+// resource. The field violations of an INVALID_ARGUMENT error are joined with the
+// schema of the fields they are about; other errors are returned as by
+// errors.HandleGRPCError.
+//
+// Args:
+//   - err: Error returned by the Create or Update RPC
+//   - kind: Kind of the cloud resource, whose schema describes the fields
+//   - resource: What the call was about
+func mutationErrorResult(
+	ctx context.Context,
+	err error,
+	kind cloudresourcekind.CloudResourceKind,
+	resource errors.Resource,
+) *mcp.CallToolResult {
+	errResp := errors.FromGRPCError(err, resource)
+	errors.LogToolError(ctx, errResp)
+	if errResp.Error != errors.CodeInvalidArgument || len(errResp.FieldViolations) == 0 {
+		return errors.ToolError(errResp)
+	}
+
+	resp := validationErrorResponse{
+		ErrorResponse:   errResp,
+		FieldViolations: withSchemas(ctx, kind, errResp.FieldViolations),
+	}
+	resp.ErrorResponse.FieldViolations = nil
+	resp.Hint = fixFieldsHint(kind)
+	return errors.ToolError(resp)
+}
+
+// withSchemas joins field violations reported as CloudResource paths with the schema
+// of the fields they are about. Violations are returned without schemas if the
+// schema cannot be extracted.
+func withSchemas(
+	ctx context.Context,
+	kind cloudresourcekind.CloudResourceKind,
+	violations []errors.FieldViolation,
+) []fieldViolation {
+	if len(violations) == 0 {
+		return nil
+	}
+	schema, err := crinternal.ExtractCloudResourceSchema(kind)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to extract schema for field violations", "kind", kind.String(), "error", err)
+	}
+
+	joined := make([]fieldViolation, 0, len(violations))
+	for _, violation := range violations {
+		specPath, ok := crinternal.CloudObjectFieldPath(kind, violation.FieldPath)
+		if !ok {
+			joined = append(joined, fieldViolation{FieldViolation: violation})
+			continue
+		}
+		joined = append(joined, fieldViolation{
+			FieldViolation: violation,
+			SpecPath:       specPath,
+			Schema:         schema.Field(specPath),
+		})
+	}
+	return joined
+}
+
+// fixFieldsHint is the hint of a response listing field violations
+func fixFieldsHint(kind cloudresourcekind.CloudResourceKind) string {
+	return fmt.Sprintf(
+		"Fix the fields in field_violations and retry. Call 'get_cloud_resource_schema' with cloud_resource_kind='%s' for the complete schema",
+		kind.String(),
+	)
+}
+
+// errorResponse creates a standard error response
+func errorResponse(errorCode, message string) *mcp.CallToolResult {
+	return errors.ToolError(errors.ErrorResponse{
+		Error:   errorCode,
+		Message: message,
+	})
+}
@@ -2,14 +2,12 @@ package cloudresource
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
-	"github.com/plantoncloud/mcp-server-planton/internal/domains/infrahub/clients"
 	crinternal "github.com/plantoncloud/mcp-server-planton/internal/domains/infrahub/cloudresource/internal"
 	"google.golang.org/protobuf/encoding/protojson"
 )
@@ -33,6 +31,7 @@ func CreateGetCloudResourceByIdTool() mcp.Tool {
 			},
 			Required: []string{"resource_id"},
 		},
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
 	}
 }
 
@@ -47,61 +46,46 @@ func CreateGetCloudResourceByIdTool() mcp.Tool {
 func HandleGetCloudResourceById(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
 	// Extract resource_id from arguments
 	resourceID, ok := arguments["resource_id"].(string)
 	if !ok || resourceID == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "resource_id is required",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
-	log.Printf("Tool invoked: get_cloud_resource_by_id, resource_id=%s", resourceID)
+	slog.DebugContext(ctx, "Tool invoked", "resource_id", resourceID)
 
-	// Create gRPC client with per-user API key from context
-	// For HTTP transport: API key extracted from Authorization header
-	// For STDIO transport: API key from environment variable (fallback to config)
-	client, err := clients.NewCloudResourceQueryClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
+	// Create gRPC client with the caller's credentials (see auth.CredentialPolicy)
+	client, err := factory.CloudResourceQueryClient(ctx)
 	if err != nil {
-		// Fallback to config API key for STDIO mode
-		client, err = clients.NewCloudResourceQueryClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			errResp := errors.ErrorResponse{
-				Error:   "CLIENT_ERROR",
-				Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
-		}
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeClientError,
+			Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
+		}), nil
 	}
 	defer client.Close()
 
 	// Get cloud resource by ID (returns CloudResource wrapper)
 	cloudResource, err := client.GetById(ctx, resourceID)
 	if err != nil {
-		return errors.HandleGRPCError(err, ""), nil
+		return errors.HandleGRPCError(ctx, err, errors.Resource{ResourceID: resourceID}), nil
 	}
 
 	// Unwrap to get the specific cloud resource object
 	// This extracts the actual resource (e.g., AwsEksCluster, GcpGkeCluster) from the wrapper
 	unwrappedResource, err := crinternal.UnwrapCloudResource(cloudResource)
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: fmt.Sprintf("Failed to unwrap cloud resource: %v", err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
-	log.Printf("Tool completed: get_cloud_resource_by_id, retrieved resource: %s", resourceID)
+	slog.DebugContext(ctx, "Tool completed", "resource_id", resourceID)
 
 	// Convert protobuf to JSON
 	// Use protojson for better handling of Any types and other protobuf specifics
@@ -113,12 +97,10 @@ func HandleGetCloudResourceById(
 
 	resultJSON, err := marshaler.Marshal(unwrappedResource)
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: fmt.Sprintf("Failed to marshal cloud resource: %v", err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	return mcp.NewToolResultText(string(resultJSON)), nil
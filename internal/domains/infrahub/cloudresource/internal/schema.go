@@ -2,6 +2,7 @@ package internal
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	cloudresourcev1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/infrahub/cloudresource/v1"
@@ -99,6 +100,59 @@ func ExtractCloudResourceSchema(kind cloudresourcekind.CloudResourceKind) (*Clou
 	}, nil
 }
 
+// Field returns the schema of the field at a path relative to the resource message,
+// e.g. "spec.subnets[0].cidr", or nil if the schema has no such field. List indexes
+// and map keys in the path are ignored: they select an element of the field.
+func (s *CloudResourceSchema) Field(path string) *SchemaField {
+	path = fieldPathSubscript.ReplaceAllString(path, "")
+	if s == nil || path == "" {
+		return nil
+	}
+
+	fields := s.Fields
+	var field *SchemaField
+	for _, name := range strings.Split(path, ".") {
+		field = nil
+		for i := range fields {
+			// Paths use proto names, but accept the JSON names of the spec too
+			if fields[i].Name == name || fields[i].Name == PascalToSnakeCase(name) {
+				field = &fields[i]
+				break
+			}
+		}
+		if field == nil {
+			return nil
+		}
+		fields = field.NestedFields
+	}
+	return field
+}
+
+// fieldPathSubscript matches a list index or map key in a field path: [0], ["env"]
+var fieldPathSubscript = regexp.MustCompile(`\[(?:"(?:[^"\\]|\\.)*"|[^\]]*)\]`)
+
+// CloudObjectFieldPath converts the path of a field in a CloudResource, as reported
+// by backend validation, to its path in the resource message of the given kind, which
+// is what the spec argument of the create and update tools holds. For example
+// "spec.cloud_object.aws_vpc.spec.cidr_block" becomes "spec.cidr_block".
+//
+// Returns:
+//   - The path relative to the resource message
+//   - false if the field is outside the resource message (e.g. "metadata.name"), in
+//     which case the path is returned unchanged
+func CloudObjectFieldPath(kind cloudresourcekind.CloudResourceKind, path string) (string, bool) {
+	return cloudObjectFieldPath(kindToFieldName(kind.String()), path)
+}
+
+// cloudObjectFieldPath strips the CloudResource prefix of the resource message field.
+func cloudObjectFieldPath(fieldName, path string) (string, bool) {
+	relative, ok := strings.CutPrefix(path, "spec.cloud_object."+fieldName+".")
+	if !ok {
+		return path, false
+	}
+	return relative, true
+}
+
 // kindToFieldName converts a CloudResourceKind string to the protobuf field name format
 // E.g., "aws_rds_instance" stays "aws_rds_instance" (already snake_case)
 // "AwsRdsInstance" converts to "aws_rds_instance" (PascalCase to snake_case)
@@ -0,0 +1,65 @@
+package internal
+
+import "testing"
+
+func TestCloudResourceSchemaField(t *testing.T) {
+	schema := &CloudResourceSchema{
+		Kind: "AwsVpc",
+		Fields: []SchemaField{
+			{
+				Name: "spec",
+				Type: "AwsVpcSpec",
+				NestedFields: []SchemaField{
+					{Name: "cidr_block", Type: "string"},
+					{Name: "subnets", Type: "AwsVpcSubnet", IsRepeated: true, NestedFields: []SchemaField{
+						{Name: "availability_zone", Type: "string"},
+					}},
+					{Name: "tags", Type: "map", IsMap: true},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"spec", "spec"},
+		{"spec.cidr_block", "cidr_block"},
+		{"spec.cidrBlock", "cidr_block"},
+		{"spec.subnets[0].availability_zone", "availability_zone"},
+		{`spec.tags["team.name"]`, "tags"},
+		{"spec.region", ""},
+		{"spec.cidr_block.value", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		field := schema.Field(tt.path)
+		var got string
+		if field != nil {
+			got = field.Name
+		}
+		if got != tt.want {
+			t.Errorf("Field(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCloudObjectFieldPath(t *testing.T) {
+	tests := []struct {
+		path   string
+		want   string
+		wantOK bool
+	}{
+		{"spec.cloud_object.aws_vpc.spec.cidr_block", "spec.cidr_block", true},
+		{"spec.cloud_object.aws_vpc.spec.subnets[1].availability_zone", "spec.subnets[1].availability_zone", true},
+		{"spec.cloud_object.aws_vpc_endpoint.spec.region", "spec.cloud_object.aws_vpc_endpoint.spec.region", false},
+		{"metadata.name", "metadata.name", false},
+	}
+	for _, tt := range tests {
+		got, ok := cloudObjectFieldPath("aws_vpc", tt.path)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("cloudObjectFieldPath(%q) = %q, %t; want %q, %t", tt.path, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	stderrors "errors"
+	"sync"
+
+	cloudresourcev1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/infrahub/cloudresource/v1"
+	"buf.build/go/protovalidate"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// validator is shared by all validations: it caches the rules compiled for each
+// message type, and is safe for concurrent use.
+var validator = sync.OnceValues(func() (protovalidate.Validator, error) {
+	return protovalidate.New()
+})
+
+// ValidateCloudObject checks the resource message of a wrapped cloud resource against
+// its buf.validate rules with protovalidate, the library Planton APIs validate with,
+// and returns every violation at once. Field paths are reported as the backend
+// reports them, e.g. "spec.cloud_object.aws_vpc.spec.cidr_block".
+//
+// The fields the backend sets itself (see shouldSkipField) are not validated.
+//
+// Args:
+//   - cloudResource: CloudResource built by WrapCloudResource
+//
+// Returns the violations, nil if none, or an error if the rules could not be
+// compiled or evaluated.
+func ValidateCloudObject(cloudResource *cloudresourcev1.CloudResource) ([]errors.FieldViolation, error) {
+	cloudObject := cloudResource.GetSpec().GetCloudObject().ProtoReflect()
+	oneofDescriptor := cloudObject.Descriptor().Oneofs().ByName("object")
+	if oneofDescriptor == nil {
+		return nil, nil
+	}
+	field := cloudObject.WhichOneof(oneofDescriptor)
+	if field == nil || field.Message() == nil {
+		return nil, nil
+	}
+	return validateMessage(cloudObject.Get(field).Message(), "spec.cloud_object."+string(field.Name()))
+}
+
+// validateMessage validates a resource message and prefixes the field path of each
+// violation with path, the message's own path in the request.
+func validateMessage(msg protoreflect.Message, path string) ([]errors.FieldViolation, error) {
+	v, err := validator()
+	if err != nil {
+		return nil, err
+	}
+
+	err = v.Validate(msg.Interface(), protovalidate.WithFilter(skipSystemFields(msg.Descriptor())))
+	var validationErr *protovalidate.ValidationError
+	if !stderrors.As(err, &validationErr) {
+		return nil, err
+	}
+
+	violations := errors.ProtovalidateViolations(validationErr.ToProto())
+	for i := range violations {
+		violations[i].FieldPath = joinFieldPath(path, violations[i].FieldPath)
+	}
+	return violations, nil
+}
+
+// skipSystemFields returns a filter leaving out the fields of the resource message
+// that the backend sets itself, but not fields of the same name in nested messages.
+func skipSystemFields(resource protoreflect.MessageDescriptor) protovalidate.Filter {
+	return protovalidate.FilterFunc(func(msg protoreflect.Message, descriptor protoreflect.Descriptor) bool {
+		field, ok := descriptor.(protoreflect.FieldDescriptor)
+		return !ok || msg.Descriptor() != resource || !shouldSkipField(field)
+	})
+}
+
+// joinFieldPath appends a field path to the path of its message. Message-level
+// violations have no field path of their own.
+func joinFieldPath(path, field string) string {
+	if field == "" {
+		return path
+	}
+	return path + "." + field
+}
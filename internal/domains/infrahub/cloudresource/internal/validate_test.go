@@ -0,0 +1,179 @@
+package internal
+
+import (
+	"slices"
+	"testing"
+
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// fieldRules returns field options carrying buf.validate rules
+func fieldRules(rules validate.FieldRules_builder) *descriptorpb.FieldOptions {
+	options := &descriptorpb.FieldOptions{}
+	proto.SetExtension(options, validate.E_Field, rules.Build())
+	return options
+}
+
+// testSpecDescriptor builds a resource message with buf.validate rules, standing in
+// for the messages of the CloudObject oneof.
+func testSpecDescriptor(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+	scalar := func(name string, number int32, kind descriptorpb.FieldDescriptorProto_Type, options *descriptorpb.FieldOptions) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     kind.Enum(),
+			Options:  options,
+		}
+	}
+	withType := func(field *descriptorpb.FieldDescriptorProto, typeName string) *descriptorpb.FieldDescriptorProto {
+		field.TypeName = proto.String(typeName)
+		return field
+	}
+	repeated := func(field *descriptorpb.FieldDescriptorProto) *descriptorpb.FieldDescriptorProto {
+		field.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+		return field
+	}
+	stringType := descriptorpb.FieldDescriptorProto_TYPE_STRING
+
+	file := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("crinternal/validate_test.proto"),
+		Package:    proto.String("crinternal.test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"buf/validate/validate.proto"},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Tier"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("tier_unspecified"), Number: proto.Int32(0)},
+				{Name: proto.String("small"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Node"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					scalar("size", 1, stringType, fieldRules(validate.FieldRules_builder{
+						String: validate.StringRules_builder{In: []string{"s", "m"}}.Build(),
+					})),
+					// Only the system fields of the resource message itself are skipped
+					scalar("kind", 2, stringType, fieldRules(validate.FieldRules_builder{Required: proto.Bool(true)})),
+				},
+			},
+			{
+				Name: proto.String("Spec"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					scalar("region", 1, stringType, fieldRules(validate.FieldRules_builder{Required: proto.Bool(true)})),
+					scalar("name", 2, stringType, fieldRules(validate.FieldRules_builder{
+						Cel:    []*validate.Rule{validate.Rule_builder{Id: proto.String("name.no_admin"), Expression: proto.String("this != 'admin'")}.Build()},
+						String: validate.StringRules_builder{MinLen: proto.Uint64(3), Pattern: proto.String("^[a-z-]+$")}.Build(),
+					})),
+					scalar("cidr_block", 3, stringType, fieldRules(validate.FieldRules_builder{
+						String: validate.StringRules_builder{Ipv4Prefix: proto.Bool(true)}.Build(),
+					})),
+					scalar("replicas", 4, descriptorpb.FieldDescriptorProto_TYPE_INT32, fieldRules(validate.FieldRules_builder{
+						Int32: validate.Int32Rules_builder{Gte: proto.Int32(1), Lte: proto.Int32(10)}.Build(),
+					})),
+					repeated(withType(scalar("tags", 5, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, fieldRules(validate.FieldRules_builder{
+						Map: validate.MapRules_builder{Keys: validate.FieldRules_builder{
+							String: validate.StringRules_builder{MinLen: proto.Uint64(2)}.Build(),
+						}.Build()}.Build(),
+					})), ".crinternal.test.Spec.TagsEntry")),
+					repeated(scalar("zones", 6, stringType, fieldRules(validate.FieldRules_builder{
+						Repeated: validate.RepeatedRules_builder{MinItems: proto.Uint64(1), Unique: proto.Bool(true)}.Build(),
+					}))),
+					withType(scalar("tier", 7, descriptorpb.FieldDescriptorProto_TYPE_ENUM, fieldRules(validate.FieldRules_builder{
+						Enum: validate.EnumRules_builder{DefinedOnly: proto.Bool(true)}.Build(),
+					})), ".crinternal.test.Tier"),
+					withType(scalar("node", 8, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, nil), ".crinternal.test.Node"),
+					withType(scalar("backup", 9, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, nil), ".crinternal.test.Node"),
+					scalar("kind", 10, stringType, fieldRules(validate.FieldRules_builder{Required: proto.Bool(true)})),
+				},
+				NestedType: []*descriptorpb.DescriptorProto{{
+					Name: proto.String("TagsEntry"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						scalar("key", 1, stringType, nil),
+						scalar("value", 2, stringType, nil),
+					},
+					Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+				}},
+			},
+		},
+	}
+
+	fd, err := protodesc.NewFile(file, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fd.Messages().ByName("Spec")
+}
+
+// validateSpecJSON validates a Spec message given as protojson.
+func validateSpecJSON(t *testing.T, spec string) []errors.FieldViolation {
+	t.Helper()
+	msg := dynamicpb.NewMessage(testSpecDescriptor(t))
+	if err := protojson.Unmarshal([]byte(spec), msg); err != nil {
+		t.Fatal(err)
+	}
+	violations, err := validateMessage(msg, "spec.cloud_object.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return violations
+}
+
+func TestValidateMessage(t *testing.T) {
+	violations := validateSpecJSON(t, `{
+		"name": "A",
+		"cidr_block": "10.0.0.1/16",
+		"replicas": 0,
+		"tags": {"a": "x", "ok": "y"},
+		"zones": ["us-east-1a", "us-east-1a"],
+		"tier": 5,
+		"node": {"size": "xl"}
+	}`)
+
+	want := []errors.FieldViolation{
+		{FieldPath: "spec.cloud_object.test.region", Rule: "required", Message: "value is required"},
+		{FieldPath: "spec.cloud_object.test.name", Rule: "string.min_len", Message: "value length must be at least 3 characters"},
+		{FieldPath: "spec.cloud_object.test.name", Rule: "string.pattern", Message: "value does not match regex pattern `^[a-z-]+$`"},
+		{FieldPath: "spec.cloud_object.test.cidr_block", Rule: "string.ipv4_prefix", Message: "value must be a valid IPv4 prefix"},
+		{FieldPath: "spec.cloud_object.test.replicas", Rule: "int32.gte_lte", Message: "value must be greater than or equal to 1 and less than or equal to 10"},
+		{FieldPath: `spec.cloud_object.test.tags["a"]`, Rule: "string.min_len", Message: "value length must be at least 2 characters"},
+		{FieldPath: "spec.cloud_object.test.zones", Rule: "repeated.unique", Message: "repeated value must contain unique items"},
+		{FieldPath: "spec.cloud_object.test.tier", Rule: "enum.defined_only", Message: "value must be one of the defined enum values"},
+		{FieldPath: "spec.cloud_object.test.node.size", Rule: "string.in", Message: "value must be in list [s, m]"},
+		{FieldPath: "spec.cloud_object.test.node.kind", Rule: "required", Message: "value is required"},
+	}
+	if !slices.Equal(violations, want) {
+		t.Errorf("violations:\n%+v\nwant:\n%+v", violations, want)
+	}
+}
+
+func TestValidateMessageCEL(t *testing.T) {
+	violations := validateSpecJSON(t, `{"region": "us-east-1", "name": "admin", "cidr_block": "10.0.0.0/16", "replicas": 1, "zones": ["us-east-1a"]}`)
+
+	want := []errors.FieldViolation{
+		{FieldPath: "spec.cloud_object.test.name", Rule: "name.no_admin"},
+	}
+	if !slices.EqualFunc(violations, want, func(a, b errors.FieldViolation) bool {
+		return a.FieldPath == b.FieldPath && a.Rule == b.Rule
+	}) {
+		t.Errorf("violations = %+v, want %+v", violations, want)
+	}
+}
+
+func TestValidateMessageValid(t *testing.T) {
+	if violations := validateSpecJSON(t, `{"region": "us-east-1", "name": "main", "cidr_block": "10.0.0.0/16", "replicas": 3, "zones": ["us-east-1a"]}`); violations != nil {
+		t.Errorf("violations = %+v, want none", violations)
+	}
+}
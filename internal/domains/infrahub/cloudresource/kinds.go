@@ -4,12 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 
 	cloudresourcekind "buf.build/gen/go/project-planton/apis/protocolbuffers/go/org/project_planton/shared/cloudresourcekind"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
 	crinternal "github.com/plantoncloud/mcp-server-planton/internal/domains/infrahub/cloudresource/internal"
 )
 
@@ -34,6 +34,7 @@ func CreateListCloudResourceKindsTool() mcp.Tool {
 			Properties: map[string]interface{}{},
 			Required:   []string{},
 		},
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
 	}
 }
 
@@ -47,9 +48,9 @@ func CreateListCloudResourceKindsTool() mcp.Tool {
 func HandleListCloudResourceKinds(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
-	log.Printf("Tool invoked: list_cloud_resource_kinds")
+	slog.DebugContext(ctx, "Tool invoked")
 
 	// Build list of cloud resource kinds from enum
 	kinds := make([]CloudResourceKindInfo, 0)
@@ -73,17 +74,15 @@ func HandleListCloudResourceKinds(
 		})
 	}
 
-	log.Printf("Tool completed: list_cloud_resource_kinds, returned %d kinds", len(kinds))
+	slog.DebugContext(ctx, "Tool completed", "count", len(kinds))
 
 	// Return formatted JSON response
 	resultJSON, err := json.MarshalIndent(kinds, "", "  ")
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: "Failed to marshal cloud resource kinds",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	return mcp.NewToolResultText(string(resultJSON)), nil
@@ -153,8 +152,8 @@ func CreateCloudResourceKindsResource() mcp.Resource {
 // HandleReadCloudResourceKinds handles reading the cloud resource kinds MCP resource.
 // This provides the same information as list_cloud_resource_kinds tool but as a resource
 // that agents can access automatically.
-func HandleReadCloudResourceKinds(request mcp.ReadResourceRequest) ([]interface{}, error) {
-	log.Printf("Resource read: cloud-resource-kinds")
+func HandleReadCloudResourceKinds(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	slog.DebugContext(ctx, "Resource read", "resource", "cloud-resource-kinds")
 
 	// Build list of cloud resource kinds from enum
 	kinds := make([]CloudResourceKindInfo, 0)
@@ -184,15 +183,13 @@ func HandleReadCloudResourceKinds(request mcp.ReadResourceRequest) ([]interface{
 		return nil, fmt.Errorf("failed to marshal kinds: %w", err)
 	}
 
-	log.Printf("Resource read completed: cloud-resource-kinds, returned %d kinds", len(kinds))
+	slog.DebugContext(ctx, "Resource read completed", "resource", "cloud-resource-kinds", "count", len(kinds))
 
-	return []interface{}{
+	return []mcp.ResourceContents{
 		mcp.TextResourceContents{
-			ResourceContents: mcp.ResourceContents{
-				URI:      request.Params.URI,
-				MIMEType: "application/json",
-			},
-			Text: string(jsonData),
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(jsonData),
 		},
 	}, nil
 }
@@ -4,15 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
 
 	apiresourcekind "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/commons/apiresource/apiresourcekind"
 	cloudresourcekind "buf.build/gen/go/project-planton/apis/protocolbuffers/go/org/project_planton/shared/cloudresourcekind"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
-	"github.com/plantoncloud/mcp-server-planton/internal/domains/infrahub/clients"
 )
 
 // CreateLookupCloudResourceByNameTool creates the MCP tool definition for looking up a cloud resource by name.
@@ -45,6 +44,7 @@ func CreateLookupCloudResourceByNameTool() mcp.Tool {
 			},
 			Required: []string{"org_id", "env_name", "cloud_resource_kind", "name"},
 		},
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
 	}
 }
 
@@ -58,100 +58,83 @@ func CreateLookupCloudResourceByNameTool() mcp.Tool {
 func HandleLookupCloudResourceByName(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
 	// Extract org_id from arguments
 	orgID, ok := arguments["org_id"].(string)
 	if !ok || orgID == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "org_id is required",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	// Extract env_name
 	envName, ok := arguments["env_name"].(string)
 	if !ok || envName == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
-			Message: "env_name is required",
-			OrgID:   orgID,
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		return errors.ToolError(errors.ErrorResponse{
+			Error:    errors.CodeInvalidArgument,
+			Message:  "env_name is required",
+			Resource: errors.Resource{OrgID: orgID},
+		}), nil
 	}
 
 	// Extract cloud_resource_kind
 	kindStr, ok := arguments["cloud_resource_kind"].(string)
 	if !ok || kindStr == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
-			Message: "cloud_resource_kind is required",
-			OrgID:   orgID,
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		return errors.ToolError(errors.ErrorResponse{
+			Error:    errors.CodeInvalidArgument,
+			Message:  "cloud_resource_kind is required",
+			Resource: errors.Resource{OrgID: orgID},
+		}), nil
 	}
 
 	// Convert kind string to enum
 	kindValue, found := cloudresourcekind.CloudResourceKind_value[kindStr]
 	if !found {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
-			Message: fmt.Sprintf("Unknown CloudResourceKind: %s. Use list_cloud_resource_kinds to see available kinds", kindStr),
-			OrgID:   orgID,
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		return errors.ToolError(errors.ErrorResponse{
+			Error:    errors.CodeInvalidArgument,
+			Message:  fmt.Sprintf("Unknown CloudResourceKind: %s. Use list_cloud_resource_kinds to see available kinds", kindStr),
+			Resource: errors.Resource{OrgID: orgID},
+		}), nil
 	}
 	kind := cloudresourcekind.CloudResourceKind(kindValue)
 
 	// Extract name
 	name, ok := arguments["name"].(string)
 	if !ok || name == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
-			Message: "name is required",
-			OrgID:   orgID,
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		return errors.ToolError(errors.ErrorResponse{
+			Error:    errors.CodeInvalidArgument,
+			Message:  "name is required",
+			Resource: errors.Resource{OrgID: orgID},
+		}), nil
 	}
 
 	// Convert name to lowercase as per API requirement
 	name = strings.ToLower(name)
 
-	log.Printf("Tool invoked: lookup_cloud_resource_by_name, org_id=%s, env=%s, kind=%s, name=%s",
-		orgID, envName, kindStr, name)
+	slog.DebugContext(ctx, "Tool invoked",
+		"org_id", orgID,
+		"env", envName,
+		"kind", kindStr,
+		"name", name,
+	)
 
-	// Create gRPC client with per-user API key from context
-	// For HTTP transport: API key extracted from Authorization header
-	// For STDIO transport: API key from environment variable (fallback to config)
-	client, err := clients.NewCloudResourceSearchClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
+	// Create gRPC client with the caller's credentials (see auth.CredentialPolicy)
+	client, err := factory.CloudResourceSearchClient(ctx)
 	if err != nil {
-		// Fallback to config API key for STDIO mode
-		client, err = clients.NewCloudResourceSearchClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			errResp := errors.ErrorResponse{
-				Error:   "CLIENT_ERROR",
-				Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
-				OrgID:   orgID,
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
-		}
+		return errors.ToolError(errors.ErrorResponse{
+			Error:    errors.CodeClientError,
+			Message:  fmt.Sprintf("Failed to create gRPC client: %v", err),
+			Resource: errors.Resource{OrgID: orgID},
+		}), nil
 	}
 	defer client.Close()
 
 	// Lookup cloud resource
 	record, err := client.LookupCloudResource(ctx, orgID, envName, kind, name)
 	if err != nil {
-		return errors.HandleGRPCError(err, orgID), nil
+		return errors.HandleGRPCError(ctx, err, errors.Resource{OrgID: orgID, Name: name}), nil
 	}
 
 	// Convert to simplified structure
@@ -167,18 +150,16 @@ func HandleLookupCloudResourceByName(
 		Tags:              record.GetTags(),
 	}
 
-	log.Printf("Tool completed: lookup_cloud_resource_by_name, found resource: %s", resource.ID)
+	slog.DebugContext(ctx, "Tool completed", "resource_id", resource.ID)
 
 	// Return formatted JSON response
 	resultJSON, err := json.MarshalIndent(resource, "", "  ")
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
-			Message: fmt.Sprintf("Failed to marshal response: %v", err),
-			OrgID:   orgID,
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		return errors.ToolError(errors.ErrorResponse{
+			Error:    errors.CodeInternal,
+			Message:  fmt.Sprintf("Failed to marshal response: %v", err),
+			Resource: errors.Resource{OrgID: orgID},
+		}), nil
 	}
 
 	return mcp.NewToolResultText(string(resultJSON)), nil
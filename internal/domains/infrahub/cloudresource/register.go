@@ -2,34 +2,34 @@ package cloudresource
 
 import (
 	"context"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/plantoncloud/mcp-server-planton/internal/common/auth"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 )
 
 // RegisterTools registers all cloud resource tools and resources with the MCP server.
-func RegisterTools(s *server.MCPServer, cfg *config.Config) {
+func RegisterTools(s *server.MCPServer, factory clientfactory.Factory) {
 	// Register resources first (makes them available to agents immediately)
 	registerKindsResource(s)
 
 	// Query tools
-	registerGetTool(s, cfg)
-	registerSearchTool(s, cfg)
-	registerLookupTool(s, cfg)
-	registerListKindsTool(s, cfg)
+	registerGetTool(s, factory)
+	registerSearchTool(s, factory)
+	registerLookupTool(s, factory)
+	registerListKindsTool(s, factory)
 
-	// Schema discovery
-	registerGetSchemaTool(s, cfg)
+	// Schema discovery and validation
+	registerGetSchemaTool(s, factory)
+	registerValidateTool(s, factory)
 
 	// Command tools (mutations)
-	registerCreateTool(s, cfg)
-	registerUpdateTool(s, cfg)
-	registerDeleteTool(s, cfg)
+	registerCreateTool(s, factory)
+	registerUpdateTool(s, factory)
+	registerDeleteTool(s, factory)
 
-	log.Println("Registered 1 resource and 8 cloud resource tools")
+	slog.Debug("Registered 1 resource and 9 cloud resource tools")
 }
 
 // registerKindsResource registers the cloud resource kinds MCP resource.
@@ -38,101 +38,104 @@ func registerKindsResource(s *server.MCPServer) {
 		CreateCloudResourceKindsResource(),
 		HandleReadCloudResourceKinds,
 	)
-	log.Println("  - planton://cloud-resource-kinds (resource)")
+	slog.Debug("Registered resource", "uri", "planton://cloud-resource-kinds")
 }
 
 // registerGetTool registers the get_cloud_resource_by_id tool.
-func registerGetTool(s *server.MCPServer, cfg *config.Config) {
+func registerGetTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateGetCloudResourceByIdTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleGetCloudResourceById(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleGetCloudResourceById(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - get_cloud_resource_by_id")
+	slog.Debug("Registered tool", "tool", "get_cloud_resource_by_id")
 }
 
 // registerSearchTool registers the search_cloud_resources tool.
-func registerSearchTool(s *server.MCPServer, cfg *config.Config) {
+func registerSearchTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateSearchCloudResourcesTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleSearchCloudResources(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleSearchCloudResources(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - search_cloud_resources")
+	slog.Debug("Registered tool", "tool", "search_cloud_resources")
 }
 
 // registerLookupTool registers the lookup_cloud_resource_by_name tool.
-func registerLookupTool(s *server.MCPServer, cfg *config.Config) {
+func registerLookupTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateLookupCloudResourceByNameTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleLookupCloudResourceByName(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleLookupCloudResourceByName(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - lookup_cloud_resource_by_name")
+	slog.Debug("Registered tool", "tool", "lookup_cloud_resource_by_name")
 }
 
 // registerListKindsTool registers the list_cloud_resource_kinds tool.
-func registerListKindsTool(s *server.MCPServer, cfg *config.Config) {
+func registerListKindsTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateListCloudResourceKindsTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleListCloudResourceKinds(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleListCloudResourceKinds(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - list_cloud_resource_kinds")
+	slog.Debug("Registered tool", "tool", "list_cloud_resource_kinds")
 }
 
 // registerGetSchemaTool registers the get_cloud_resource_schema tool.
-func registerGetSchemaTool(s *server.MCPServer, cfg *config.Config) {
+func registerGetSchemaTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateGetCloudResourceSchemaTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleGetCloudResourceSchema(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleGetCloudResourceSchema(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - get_cloud_resource_schema")
+	slog.Debug("Registered tool", "tool", "get_cloud_resource_schema")
+}
+
+// registerValidateTool registers the validate_cloud_resource tool.
+func registerValidateTool(s *server.MCPServer, factory clientfactory.Factory) {
+	s.AddTool(
+		CreateValidateCloudResourceTool(),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleValidateCloudResource(ctx, request.GetArguments(), factory)
+		},
+	)
+	slog.Debug("Registered tool", "tool", "validate_cloud_resource")
 }
 
 // registerCreateTool registers the create_cloud_resource tool.
-func registerCreateTool(s *server.MCPServer, cfg *config.Config) {
+func registerCreateTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateCreateCloudResourceTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleCreateCloudResource(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleCreateCloudResource(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - create_cloud_resource")
+	slog.Debug("Registered tool", "tool", "create_cloud_resource")
 }
 
 // registerUpdateTool registers the update_cloud_resource tool.
-func registerUpdateTool(s *server.MCPServer, cfg *config.Config) {
+func registerUpdateTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateUpdateCloudResourceTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleUpdateCloudResource(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleUpdateCloudResource(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - update_cloud_resource")
+	slog.Debug("Registered tool", "tool", "update_cloud_resource")
 }
 
 // registerDeleteTool registers the delete_cloud_resource tool.
-func registerDeleteTool(s *server.MCPServer, cfg *config.Config) {
+func registerDeleteTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateDeleteCloudResourceTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleDeleteCloudResource(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleDeleteCloudResource(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - delete_cloud_resource")
+	slog.Debug("Registered tool", "tool", "delete_cloud_resource")
 }
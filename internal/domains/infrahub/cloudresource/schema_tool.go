@@ -4,11 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
 	crinternal "github.com/plantoncloud/mcp-server-planton/internal/domains/infrahub/cloudresource/internal"
 )
 
@@ -42,6 +42,7 @@ For the complete list of 150+ resource types, use 'list_cloud_resource_kinds' to
 			},
 			Required: []string{"cloud_resource_kind"},
 		},
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
 	}
 }
 
@@ -55,60 +56,49 @@ For the complete list of 150+ resource types, use 'list_cloud_resource_kinds' to
 func HandleGetCloudResourceSchema(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
 	// Extract cloud_resource_kind from arguments
 	kindStr, ok := arguments["cloud_resource_kind"].(string)
 	if !ok || kindStr == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "cloud_resource_kind is required",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
-	log.Printf("Tool invoked: get_cloud_resource_schema, kind=%s", kindStr)
+	slog.DebugContext(ctx, "Tool invoked", "kind", kindStr)
 
 	// Normalize the kind (handles multiple formats)
 	kind, err := crinternal.NormalizeCloudResourceKind(kindStr)
 	if err != nil {
 		// Kind not found - return error with helpful suggestions
-		errResp := map[string]interface{}{
-			"error":                     "INVALID_CLOUD_RESOURCE_KIND",
-			"message":                   err.Error(),
-			"input":                     kindStr,
-			"popular_kinds_by_category": crinternal.GetPopularKindsByCategory(),
-			"hint":                      "Enable 'list_cloud_resource_kinds' tool to discover all 150+ available types",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		return invalidKindResult(kindStr, err, "Enable 'list_cloud_resource_kinds' tool to discover all 150+ available types"), nil
 	}
 
-	log.Printf("Normalized kind: %s -> %s", kindStr, kind.String())
+	slog.DebugContext(ctx, "Normalized kind", "input", kindStr, "kind", kind.String())
 
 	// Extract schema using protobuf reflection
 	schema, err := crinternal.ExtractCloudResourceSchema(kind)
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "SCHEMA_EXTRACTION_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   codeSchemaExtraction,
 			Message: fmt.Sprintf("Failed to extract schema for %s: %v", kind.String(), err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
-	log.Printf("Tool completed: get_cloud_resource_schema, kind=%s, fields=%d", kind.String(), len(schema.Fields))
+	slog.DebugContext(ctx, "Tool completed",
+		"kind", kind.String(),
+		"fields", len(schema.Fields),
+	)
 
 	// Return the schema as JSON
 	schemaJSON, err := json.MarshalIndent(schema, "", "  ")
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: fmt.Sprintf("Failed to marshal schema: %v", err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	return mcp.NewToolResultText(string(schemaJSON)), nil
@@ -4,15 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 
 	apiresourcekind "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/commons/apiresource/apiresourcekind"
 	cloudresourcesearch "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/search/v1/infrahub/cloudresource"
 	cloudresourcekind "buf.build/gen/go/project-planton/apis/protocolbuffers/go/org/project_planton/shared/cloudresourcekind"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
-	"github.com/plantoncloud/mcp-server-planton/internal/domains/infrahub/clients"
 	crinternal "github.com/plantoncloud/mcp-server-planton/internal/domains/infrahub/cloudresource/internal"
 )
 
@@ -65,6 +64,7 @@ func CreateSearchCloudResourcesTool() mcp.Tool {
 			},
 			Required: []string{"org_id"},
 		},
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
 	}
 }
 
@@ -79,17 +79,15 @@ func CreateSearchCloudResourcesTool() mcp.Tool {
 func HandleSearchCloudResources(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
 	// Extract org_id from arguments
 	orgID, ok := arguments["org_id"].(string)
 	if !ok || orgID == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "org_id is required",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	// Extract optional env_names
@@ -112,7 +110,7 @@ func HandleSearchCloudResources(
 				if kindValue, err := crinternal.NormalizeCloudResourceKind(kindStr); err == nil {
 					kinds = append(kinds, kindValue)
 				} else {
-					log.Printf("Warning: Unknown CloudResourceKind: %s, error: %v", kindStr, err)
+					slog.WarnContext(ctx, "Unknown CloudResourceKind", "kind", kindStr, "error", err)
 				}
 			}
 		}
@@ -121,52 +119,43 @@ func HandleSearchCloudResources(
 	// Extract optional search_text
 	searchText, _ := arguments["search_text"].(string)
 
-	log.Printf("Tool invoked: search_cloud_resources, org_id=%s, envs=%v, kinds=%v, searchText=%q",
-		orgID, envNames, kinds, searchText)
+	slog.DebugContext(ctx, "Tool invoked",
+		"org_id", orgID,
+		"envs", envNames,
+		"kinds", kinds,
+		"search_text", searchText,
+	)
 
-	// Create gRPC client with per-user API key from context
-	// For HTTP transport: API key extracted from Authorization header
-	// For STDIO transport: API key from environment variable (fallback to config)
-	client, err := clients.NewCloudResourceSearchClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
+	// Create gRPC client with the caller's credentials (see auth.CredentialPolicy)
+	client, err := factory.CloudResourceSearchClient(ctx)
 	if err != nil {
-		// Fallback to config API key for STDIO mode
-		client, err = clients.NewCloudResourceSearchClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			errResp := errors.ErrorResponse{
-				Error:   "CLIENT_ERROR",
-				Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
-				OrgID:   orgID,
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
-		}
+		return errors.ToolError(errors.ErrorResponse{
+			Error:    errors.CodeClientError,
+			Message:  fmt.Sprintf("Failed to create gRPC client: %v", err),
+			Resource: errors.Resource{OrgID: orgID},
+		}), nil
 	}
 	defer client.Close()
 
 	// Query cloud resources
 	resp, err := client.GetCloudResourcesCanvasView(ctx, orgID, envNames, kinds, searchText)
 	if err != nil {
-		return errors.HandleGRPCError(err, orgID), nil
+		return errors.HandleGRPCError(ctx, err, errors.Resource{OrgID: orgID}), nil
 	}
 
 	// Flatten the nested response structure
 	resources := flattenCanvasResponse(resp)
 
-	log.Printf("Tool completed: search_cloud_resources, returned %d resources", len(resources))
+	slog.DebugContext(ctx, "Tool completed", "count", len(resources))
 
 	// Return formatted JSON response
 	resultJSON, err := json.MarshalIndent(resources, "", "  ")
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
-			Message: fmt.Sprintf("Failed to marshal response: %v", err),
-			OrgID:   orgID,
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		return errors.ToolError(errors.ErrorResponse{
+			Error:    errors.CodeInternal,
+			Message:  fmt.Sprintf("Failed to marshal response: %v", err),
+			Resource: errors.Resource{OrgID: orgID},
+		}), nil
 	}
 
 	return mcp.NewToolResultText(string(resultJSON)), nil
@@ -2,14 +2,12 @@ package cloudresource
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
-	"github.com/plantoncloud/mcp-server-planton/internal/domains/infrahub/clients"
 	crinternal "github.com/plantoncloud/mcp-server-planton/internal/domains/infrahub/cloudresource/internal"
 	"google.golang.org/protobuf/encoding/protojson"
 )
@@ -29,6 +27,9 @@ WORKFLOW:
 3. Call this tool with resource_id and the spec changes
 4. If validation fails, errors will indicate which fields are invalid
 
+Set dry_run to check the updated spec against the resource's buf.validate rules without
+updating anything; every problem is returned at once.
+
 Note: You must provide the resource_id and the complete updated spec.`,
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
@@ -45,77 +46,75 @@ Note: You must provide the resource_id and the complete updated spec.`,
 					"type":        "string",
 					"description": "Optional message describing the reason for this update (for audit trail)",
 				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Validate the updated spec and report every problem without updating the resource (default: false)",
+				},
 			},
 			Required: []string{"resource_id", "spec"},
 		},
+		Annotations: mcp.ToolAnnotation{
+			ReadOnlyHint:    mcp.ToBoolPtr(false),
+			DestructiveHint: mcp.ToBoolPtr(true),
+		},
 	}
 }
 
 // HandleUpdateCloudResource handles the MCP tool invocation for updating a cloud resource.
 //
 // This function:
-//  1. Fetches the existing resource by ID
-//  2. Extracts the kind and metadata from the existing resource
-//  3. Wraps the new spec data into CloudResource
-//  4. Validates the updated CloudResource
-//  5. Calls CloudResourceCommandClient to update the resource
-//  6. Unwraps and returns the updated resource
+//  1. Extracts the resource ID
+//  2. Extracts the new spec data
+//  3. Extracts the optional version message
+//  4. Fetches the existing resource by ID
+//  5. Extracts the kind and metadata from the existing resource
+//  6. Adds the version message to the metadata
+//  7. Wraps the new spec data into CloudResource
+//  8. With dry_run, validates the updated CloudResource and returns the report instead
+//  9. Calls CloudResourceCommandClient to update the resource
+//  10. Unwraps the updated resource
+//  11. Returns the updated resource as JSON
 func HandleUpdateCloudResource(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
 	// 1. Extract resource_id
 	resourceID, ok := arguments["resource_id"].(string)
 	if !ok || resourceID == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "resource_id is required",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	// 2. Extract spec data
 	specData, ok := arguments["spec"].(map[string]interface{})
 	if !ok {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "spec is required and must be an object",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	// 3. Extract optional version message
 	versionMessage, _ := arguments["version_message"].(string)
 
-	log.Printf("Tool invoked: update_cloud_resource, resource_id=%s", resourceID)
+	slog.DebugContext(ctx, "Tool invoked", "resource_id", resourceID)
 
-	// 4. Fetch existing resource to get kind and metadata with per-user API key
-	// For HTTP transport: API key extracted from Authorization header
-	// For STDIO transport: API key from environment variable (fallback to config)
-	queryClient, err := clients.NewCloudResourceQueryClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
+	// 4. Fetch existing resource to get kind and metadata
+	queryClient, err := factory.CloudResourceQueryClient(ctx)
 	if err != nil {
-		// Fallback to config API key for STDIO mode
-		queryClient, err = clients.NewCloudResourceQueryClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			errResp := errors.ErrorResponse{
-				Error:   "CLIENT_ERROR",
-				Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
-		}
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeClientError,
+			Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
+		}), nil
 	}
 	defer queryClient.Close()
 
 	existingResource, err := queryClient.GetById(ctx, resourceID)
 	if err != nil {
-		return errors.HandleGRPCError(err, ""), nil
+		return errors.HandleGRPCError(ctx, err, errors.Resource{ResourceID: resourceID}), nil
 	}
 
 	// 5. Extract kind and metadata from existing resource
@@ -127,60 +126,50 @@ func HandleUpdateCloudResource(
 		metadata.Version.Message = versionMessage
 	}
 
-	log.Printf("Updating cloud resource: kind=%s, name=%s", kind.String(), metadata.GetName())
+	slog.DebugContext(ctx, "Updating cloud resource", "kind", kind.String(), "name", metadata.GetName())
 
 	// 7. Wrap the new spec data into CloudResource
 	updatedResource, err := crinternal.WrapCloudResource(kind, specData, metadata)
 	if err != nil {
-		errResp := map[string]interface{}{
-			"error":   "INVALID_SPEC_DATA",
-			"message": fmt.Sprintf("Failed to update %s resource: %v", kind.String(), err),
-			"hint":    fmt.Sprintf("Call 'get_cloud_resource_schema' with cloud_resource_kind='%s' for the complete schema", kind.String()),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   codeInvalidSpecData,
+			Message: fmt.Sprintf("Failed to update %s resource: %v", kind.String(), err),
+			Hint:    fmt.Sprintf("Call 'get_cloud_resource_schema' with cloud_resource_kind='%s' for the complete schema", kind.String()),
+		}), nil
+	}
+
+	// 8. With dry_run, validate without calling any Command RPC
+	if dryRun, _ := arguments["dry_run"].(bool); dryRun {
+		return validationResult(ctx, validateUpdateRequest(ctx, kind, updatedResource, resourceID)), nil
 	}
 
-	// 8. Create command client with per-user API key and update
-	// For HTTP transport: API key extracted from Authorization header
-	// For STDIO transport: API key from environment variable (fallback to config)
-	commandClient, err := clients.NewCloudResourceCommandClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
+	// 9. Create command client with the caller's credentials and update
+	commandClient, err := factory.CloudResourceCommandClient(ctx)
 	if err != nil {
-		// Fallback to config API key for STDIO mode
-		commandClient, err = clients.NewCloudResourceCommandClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			errResp := errors.ErrorResponse{
-				Error:   "CLIENT_ERROR",
-				Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
-		}
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeClientError,
+			Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
+		}), nil
 	}
 	defer commandClient.Close()
 
 	result, err := commandClient.Update(ctx, updatedResource)
 	if err != nil {
-		return errors.HandleGRPCError(err, metadata.GetOrg()), nil
+		return mutationErrorResult(ctx, err, kind, errors.Resource{OrgID: metadata.GetOrg(), ResourceID: resourceID}), nil
 	}
 
-	// 9. Unwrap the updated resource
+	// 10. Unwrap the updated resource
 	unwrappedResource, err := crinternal.UnwrapCloudResource(result)
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: fmt.Sprintf("Failed to unwrap updated resource: %v", err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
-	log.Printf("Tool completed: update_cloud_resource, resource_id=%s", resourceID)
+	slog.DebugContext(ctx, "Tool completed", "resource_id", resourceID)
 
-	// 10. Return the updated resource as JSON
+	// 11. Return the updated resource as JSON
 	marshaler := protojson.MarshalOptions{
 		Indent:          "  ",
 		EmitUnpopulated: false,
@@ -189,12 +178,10 @@ func HandleUpdateCloudResource(
 
 	resultJSON, err := marshaler.Marshal(unwrappedResource)
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: fmt.Sprintf("Failed to marshal resource: %v", err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	return mcp.NewToolResultText(string(resultJSON)), nil
@@ -0,0 +1,249 @@
+package cloudresource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	cloudresourcev1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/infrahub/cloudresource/v1"
+	cloudresourcekind "buf.build/gen/go/project-planton/apis/protocolbuffers/go/org/project_planton/shared/cloudresourcekind"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
+	crinternal "github.com/plantoncloud/mcp-server-planton/internal/domains/infrahub/cloudresource/internal"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateValidateCloudResourceTool creates the MCP tool definition for validating a cloud resource.
+func CreateValidateCloudResourceTool() mcp.Tool {
+	return mcp.Tool{
+		Name: "validate_cloud_resource",
+		Description: `Check a cloud resource before creating it, without creating anything.
+
+Takes the same arguments as 'create_cloud_resource'. The spec is wrapped the way create does it
+and checked against the buf.validate rules of the resource kind, and the resource name is
+checked for uniqueness in the environment. Every problem is returned at once, each with the
+failing field's path, rule, message and schema, so they can all be fixed before calling
+'create_cloud_resource'.`,
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"cloud_resource_kind": map[string]interface{}{
+					"type":        "string",
+					"description": "Resource type enum (e.g., aws_rds_instance, kubernetes_postgres)",
+				},
+				"org_id": map[string]interface{}{
+					"type":        "string",
+					"description": "Organization ID or slug",
+				},
+				"env_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Environment slug (e.g., dev, staging, prod)",
+				},
+				"resource_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Resource name (must be unique within environment)",
+				},
+				"description": map[string]interface{}{
+					"type":        "string",
+					"description": "Optional description for the resource",
+				},
+				"tags": map[string]interface{}{
+					"type":        "array",
+					"description": "Optional tags for the resource",
+					"items": map[string]interface{}{
+						"type": "string",
+					},
+				},
+				"spec": map[string]interface{}{
+					"type":        "object",
+					"description": "Resource-specific specification (fields vary by resource type)",
+				},
+			},
+			Required: []string{"cloud_resource_kind", "org_id", "env_name", "resource_name", "spec"},
+		},
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
+	}
+}
+
+// HandleValidateCloudResource handles the MCP tool invocation for validating a cloud resource.
+//
+// This function:
+//  1. Extracts the arguments and wraps the spec, as create_cloud_resource does
+//  2. Validates the resource message against its buf.validate rules
+//  3. Looks the name up to check that it is unique
+//  4. Returns a report of every problem found
+func HandleValidateCloudResource(
+	ctx context.Context,
+	arguments map[string]interface{},
+	factory clientfactory.Factory,
+) (*mcp.CallToolResult, error) {
+	req, errResult := parseCreateRequest(arguments, "validate")
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	slog.DebugContext(ctx, "Tool invoked",
+		"kind", req.kind.String(),
+		"org_id", req.orgID,
+		"env", req.envName,
+		"name", req.resourceName,
+	)
+
+	return validationResult(ctx, validateCreateRequest(ctx, req, factory, false)), nil
+}
+
+// validationReport is the result of validate_cloud_resource and of a dry run of
+// create_cloud_resource or update_cloud_resource.
+type validationReport struct {
+	Valid             bool   `json:"valid"`
+	DryRun            bool   `json:"dry_run,omitempty"`
+	CloudResourceKind string `json:"cloud_resource_kind"`
+	errors.Resource
+	EnvName         string           `json:"env_name,omitempty"`
+	FieldViolations []fieldViolation `json:"field_violations,omitempty"`
+	// Notes are about checks that could not be run locally
+	Notes []string `json:"notes,omitempty"`
+	Hint  string   `json:"hint,omitempty"`
+}
+
+// validateCreateRequest runs the checks of a create request: the buf.validate rules
+// of the spec and the uniqueness of the name. No Command RPC is called.
+//
+// Args:
+//   - req: Parsed create request
+//   - dryRun: Whether the checks are a dry run of create_cloud_resource
+func validateCreateRequest(
+	ctx context.Context,
+	req *createRequest,
+	factory clientfactory.Factory,
+	dryRun bool,
+) validationReport {
+	violations, notes := validateSpec(ctx, req.cloudResource)
+
+	violation, note := checkNameUnique(ctx, req, factory)
+	if violation != nil {
+		violations = append(violations, *violation)
+	}
+	if note != "" {
+		notes = append(notes, note)
+	}
+
+	nextStep := "Call 'create_cloud_resource' with the same arguments to create it"
+	if dryRun {
+		nextStep = "Call 'create_cloud_resource' again without dry_run to create it"
+	}
+	report := newValidationReport(ctx, req.kind, violations, notes, nextStep)
+	report.DryRun = dryRun
+	report.Resource = errors.Resource{OrgID: req.orgID, Name: req.resourceName}
+	report.EnvName = req.envName
+	return report
+}
+
+// validateUpdateRequest runs the checks of an update request. The name is not
+// checked: updates keep the name of the existing resource.
+//
+// Args:
+//   - cloudResource: Existing resource with the new spec wrapped in
+//   - resourceID: ID of the resource being updated
+func validateUpdateRequest(
+	ctx context.Context,
+	kind cloudresourcekind.CloudResourceKind,
+	cloudResource *cloudresourcev1.CloudResource,
+	resourceID string,
+) validationReport {
+	violations, notes := validateSpec(ctx, cloudResource)
+	report := newValidationReport(ctx, kind, violations, notes,
+		"Call 'update_cloud_resource' again without dry_run to apply the update")
+	report.DryRun = true
+	report.Resource = errors.Resource{OrgID: cloudResource.GetMetadata().GetOrg(), ResourceID: resourceID}
+	report.EnvName = cloudResource.GetMetadata().GetEnv()
+	return report
+}
+
+// validateSpec validates a wrapped cloud resource against the buf.validate rules of
+// its kind, with a note if the rules could not be evaluated.
+func validateSpec(ctx context.Context, cloudResource *cloudresourcev1.CloudResource) ([]errors.FieldViolation, []string) {
+	violations, err := crinternal.ValidateCloudObject(cloudResource)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to validate cloud resource spec", "error", err)
+		return nil, []string{fmt.Sprintf("The spec could not be validated locally: %v; Planton APIs validate it on create or update", err)}
+	}
+	return violations, nil
+}
+
+// checkNameUnique looks the resource name up in its environment.
+//
+// Returns:
+//   - A violation of metadata.name if a resource of the kind already has the name
+//   - A note if the lookup failed, in which case uniqueness is unknown
+func checkNameUnique(ctx context.Context, req *createRequest, factory clientfactory.Factory) (*errors.FieldViolation, string) {
+	const unchecked = "The name could not be checked for uniqueness: "
+
+	client, err := factory.CloudResourceSearchClient(ctx)
+	if err != nil {
+		return nil, unchecked + fmt.Sprintf("failed to create gRPC client: %v", err)
+	}
+	defer client.Close()
+
+	record, err := client.LookupCloudResource(ctx, req.orgID, req.envName, req.kind, req.resourceName)
+	switch {
+	case err == nil:
+		return &errors.FieldViolation{
+			FieldPath: "metadata.name",
+			Rule:      "unique",
+			Message: fmt.Sprintf("a %s named '%s' already exists in environment '%s' (id: %s)",
+				req.kind.String(), req.resourceName, req.envName, record.GetId()),
+		}, ""
+	case status.Code(err) == codes.NotFound:
+		return nil, ""
+	default:
+		errResp := errors.FromGRPCError(err, errors.Resource{OrgID: req.orgID, Name: req.resourceName})
+		slog.WarnContext(ctx, "Failed to check cloud resource name", "code", errResp.Error, "message", errResp.Message)
+		return nil, unchecked + errResp.Message
+	}
+}
+
+// newValidationReport builds a report from the problems found.
+//
+// Args:
+//   - violations: Problems found, as CloudResource paths
+//   - notes: Checks that could not be run
+//   - nextStep: Hint for a valid request
+func newValidationReport(
+	ctx context.Context,
+	kind cloudresourcekind.CloudResourceKind,
+	violations []errors.FieldViolation,
+	notes []string,
+	nextStep string,
+) validationReport {
+	report := validationReport{
+		Valid:             len(violations) == 0,
+		CloudResourceKind: kind.String(),
+		FieldViolations:   withSchemas(ctx, kind, violations),
+		Notes:             notes,
+		Hint:              nextStep,
+	}
+	if !report.Valid {
+		report.Hint = fixFieldsHint(kind)
+	}
+	return report
+}
+
+// validationResult returns a validation report as the tool result. An invalid request
+// is a successful call: the report lists its problems.
+func validationResult(ctx context.Context, report validationReport) *mcp.CallToolResult {
+	slog.DebugContext(ctx, "Tool completed",
+		"kind", report.CloudResourceKind,
+		"valid", report.Valid,
+		"field_violations", len(report.FieldViolations),
+	)
+
+	resultJSON, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errorResponse(errors.CodeInternal, fmt.Sprintf("Failed to marshal validation report: %v", err))
+	}
+	return mcp.NewToolResultText(string(resultJSON))
+}
@@ -0,0 +1,105 @@
+package cloudresource
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	searchapiresource "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/search/v1/apiresource"
+	cloudresourcekind "buf.build/gen/go/project-planton/apis/protocolbuffers/go/org/project_planton/shared/cloudresourcekind"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeFactory hands out fakeSearchClient. Other clients, including the command
+// client, are not implemented, so validation calling them panics.
+type fakeFactory struct {
+	clientfactory.Factory
+	client *fakeSearchClient
+	err    error
+}
+
+func (f *fakeFactory) CloudResourceSearchClient(context.Context) (clientfactory.CloudResourceSearchClient, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.client, nil
+}
+
+// fakeSearchClient returns a fixed lookup result or error.
+type fakeSearchClient struct {
+	clientfactory.CloudResourceSearchClient
+	record *searchapiresource.ApiResourceSearchRecord
+	err    error
+	closed bool
+}
+
+func (c *fakeSearchClient) LookupCloudResource(
+	_ context.Context, _, _ string, _ cloudresourcekind.CloudResourceKind, _ string,
+) (*searchapiresource.ApiResourceSearchRecord, error) {
+	return c.record, c.err
+}
+
+func (c *fakeSearchClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestCheckNameUnique(t *testing.T) {
+	req := &createRequest{
+		kind:         cloudresourcekind.CloudResourceKind(cloudresourcekind.CloudResourceKind_value["AwsVpc"]),
+		orgID:        "acme",
+		envName:      "prod",
+		resourceName: "main-vpc",
+	}
+
+	tests := []struct {
+		name          string
+		factory       *fakeFactory
+		wantViolation *errors.FieldViolation
+		wantNote      string
+	}{
+		{
+			name:    "name taken",
+			factory: &fakeFactory{client: &fakeSearchClient{record: &searchapiresource.ApiResourceSearchRecord{Id: "awsvpc-123"}}},
+			wantViolation: &errors.FieldViolation{
+				FieldPath: "metadata.name",
+				Rule:      "unique",
+				Message:   "a " + req.kind.String() + " named 'main-vpc' already exists in environment 'prod' (id: awsvpc-123)",
+			},
+		},
+		{
+			name:    "name free",
+			factory: &fakeFactory{client: &fakeSearchClient{err: status.Error(codes.NotFound, "not found")}},
+		},
+		{
+			name:     "lookup denied",
+			factory:  &fakeFactory{client: &fakeSearchClient{err: status.Error(codes.PermissionDenied, "denied")}},
+			wantNote: "The name could not be checked for uniqueness: You don't have permission to access 'main-vpc' in organization 'acme'. Please contact your organization administrator.",
+		},
+		{
+			name:     "no client",
+			factory:  &fakeFactory{err: stderrors.New("no credentials")},
+			wantNote: "The name could not be checked for uniqueness: failed to create gRPC client: no credentials",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violation, note := checkNameUnique(context.Background(), req, tt.factory)
+			switch {
+			case (violation == nil) != (tt.wantViolation == nil):
+				t.Errorf("violation = %+v, want %+v", violation, tt.wantViolation)
+			case violation != nil && *violation != *tt.wantViolation:
+				t.Errorf("violation = %+v, want %+v", *violation, *tt.wantViolation)
+			}
+			if note != tt.wantNote {
+				t.Errorf("note = %q, want %q", note, tt.wantNote)
+			}
+			if tt.factory.client != nil && !tt.factory.client.closed {
+				t.Error("search client was not closed")
+			}
+		})
+	}
+}
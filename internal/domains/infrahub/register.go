@@ -1,22 +1,22 @@
 package infrahub
 
 import (
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/domains/infrahub/cloudresource"
 )
 
 // RegisterTools registers all InfraHub domain tools with the MCP server.
-func RegisterTools(s *server.MCPServer, cfg *config.Config) {
-	log.Println("Registering InfraHub tools...")
+func RegisterTools(s *server.MCPServer, factory clientfactory.Factory) {
+	slog.Debug("Registering InfraHub tools...")
 
 	// Register cloud resource tools
-	cloudresource.RegisterTools(s, cfg)
+	cloudresource.RegisterTools(s, factory)
 
 	// Future: Register stack job tools
-	// stackjob.RegisterTools(s, cfg)
+	// stackjob.RegisterTools(s, factory)
 
-	log.Println("InfraHub tools registration complete")
+	slog.Debug("InfraHub tools registration complete")
 }
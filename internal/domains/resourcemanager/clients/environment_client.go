@@ -3,16 +3,12 @@ package clients
 import (
 	"context"
 	"fmt"
-	"log"
-	"strings"
+	"log/slog"
 
 	environmentv1grpc "buf.build/gen/go/blintora/apis/grpc/go/ai/planton/resourcemanager/environment/v1/environmentv1grpc"
 	environmentv1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/resourcemanager/environment/v1"
 	organizationv1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/resourcemanager/organization/v1"
-	commonauth "github.com/plantoncloud/mcp-server-planton/internal/common/auth"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/grpcpool"
 )
 
 // EnvironmentClient is a gRPC client for querying Planton Cloud Environment resources.
@@ -22,7 +18,7 @@ import (
 // API key and enforce Fine-Grained Authorization (FGA) checks based on the
 // user's actual permissions.
 type EnvironmentClient struct {
-	conn   *grpc.ClientConn
+	conn   *grpcpool.Conn
 	client environmentv1grpc.EnvironmentQueryControllerClient
 }
 
@@ -34,26 +30,8 @@ type EnvironmentClient struct {
 //
 // Returns an EnvironmentClient and any error encountered during connection setup.
 func NewEnvironmentClient(grpcEndpoint, apiKey string) (*EnvironmentClient, error) {
-	// Determine transport credentials based on endpoint port
-	var transportCreds credentials.TransportCredentials
-	if strings.HasSuffix(grpcEndpoint, ":443") {
-		// Use TLS for port 443 (production endpoints)
-		transportCreds = credentials.NewTLS(nil)
-		log.Printf("Using TLS transport for endpoint: %s", grpcEndpoint)
-	} else {
-		// Use insecure for other ports (local development)
-		transportCreds = insecure.NewCredentials()
-		log.Printf("Using insecure transport for endpoint: %s", grpcEndpoint)
-	}
-
-	// Create gRPC dial options with per-RPC credentials (matches CLI pattern)
-	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(transportCreds),
-		grpc.WithPerRPCCredentials(commonauth.NewTokenAuth(apiKey)),
-	}
-
-	// Establish connection
-	conn, err := grpc.NewClient(grpcEndpoint, opts...)
+	// Borrow the shared connection to the endpoint; the API key is attached per RPC
+	conn, err := grpcpool.Dial(grpcEndpoint, apiKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
 	}
@@ -61,7 +39,7 @@ func NewEnvironmentClient(grpcEndpoint, apiKey string) (*EnvironmentClient, erro
 	// Create environment query client
 	client := environmentv1grpc.NewEnvironmentQueryControllerClient(conn)
 
-	log.Printf("EnvironmentClient initialized for endpoint: %s", grpcEndpoint)
+	slog.Debug("EnvironmentClient initialized", "endpoint", grpcEndpoint)
 
 	return &EnvironmentClient{
 		conn:   conn,
@@ -82,7 +60,7 @@ func NewEnvironmentClient(grpcEndpoint, apiKey string) (*EnvironmentClient, erro
 //
 // Returns a list of Environment objects or an error.
 func (c *EnvironmentClient) FindByOrg(ctx context.Context, orgID string) ([]*environmentv1.Environment, error) {
-	log.Printf("Querying environments for org: %s", orgID)
+	slog.DebugContext(ctx, "Querying environments", "org_id", orgID)
 
 	// Create request
 	req := &organizationv1.OrganizationId{
@@ -92,42 +70,22 @@ func (c *EnvironmentClient) FindByOrg(ctx context.Context, orgID string) ([]*env
 	// Make gRPC call (interceptor attaches API key automatically)
 	resp, err := c.client.FindByOrg(ctx, req)
 	if err != nil {
-		log.Printf("gRPC error querying environments for org %s: %v", orgID, err)
+		slog.DebugContext(ctx, "gRPC error querying environments", "org_id", orgID, "error", err)
 		return nil, err
 	}
 
 	// Extract environments from response
 	environments := resp.GetEntries()
-	log.Printf("Found %d environments for org: %s", len(environments), orgID)
+	slog.DebugContext(ctx, "Found environments", "org_id", orgID, "count", len(environments))
 
 	return environments, nil
 }
 
-// NewEnvironmentClientFromContext creates a new Environment gRPC client
-// using the API key from the request context.
-//
-// This constructor is used in HTTP transport mode to create clients with per-user API keys
-// extracted from Authorization headers, enabling proper multi-user support with Fine-Grained
-// Authorization.
-//
-// Args:
-//   - ctx: Context containing the API key (set by HTTP authentication middleware)
-//   - grpcEndpoint: Planton Cloud APIs endpoint (e.g., "localhost:8080" or "api.live.planton.ai:443")
-//
-// Returns an EnvironmentClient and any error encountered during connection setup.
-// Returns an error if no API key is found in the context.
-func NewEnvironmentClientFromContext(ctx context.Context, grpcEndpoint string) (*EnvironmentClient, error) {
-	apiKey, err := commonauth.GetAPIKey(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get API key from context: %w", err)
-	}
-	return NewEnvironmentClient(grpcEndpoint, apiKey)
-}
-
-// Close closes the gRPC connection.
+// Close gives the pooled gRPC connection back. The shared connection stays open
+// for other calls.
 func (c *EnvironmentClient) Close() error {
 	if c.conn != nil {
-		log.Println("Closing EnvironmentClient connection")
+		slog.Debug("Releasing EnvironmentClient connection")
 		return c.conn.Close()
 	}
 	return nil
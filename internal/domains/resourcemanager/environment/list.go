@@ -4,12 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
-	"github.com/plantoncloud/mcp-server-planton/internal/domains/resourcemanager/clients"
 )
 
 // EnvironmentSimple is a simplified representation of an Environment for JSON serialization.
@@ -37,6 +36,7 @@ func CreateListEnvironmentsTool() mcp.Tool {
 			},
 			Required: []string{"org_id"},
 		},
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
 	}
 }
 
@@ -51,47 +51,34 @@ func CreateListEnvironmentsTool() mcp.Tool {
 func HandleListEnvironmentsForOrg(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
 	// Extract org_id from arguments
 	orgID, ok := arguments["org_id"].(string)
 	if !ok || orgID == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "org_id is required",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
-	log.Printf("Tool invoked: list_environments_for_org, org_id=%s", orgID)
+	slog.DebugContext(ctx, "Tool invoked", "org_id", orgID)
 
-	// Create gRPC client with per-user API key from context
-	// For HTTP transport: API key extracted from Authorization header
-	// For STDIO transport: API key from environment variable (fallback to config)
-	client, err := clients.NewEnvironmentClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
+	// Create gRPC client with the caller's credentials (see auth.CredentialPolicy)
+	client, err := factory.EnvironmentClient(ctx)
 	if err != nil {
-		// Fallback to config API key for STDIO mode
-		client, err = clients.NewEnvironmentClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			errResp := errors.ErrorResponse{
-				Error:   "CLIENT_ERROR",
-				Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
-				OrgID:   orgID,
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
-		}
+		return errors.ToolError(errors.ErrorResponse{
+			Error:    errors.CodeClientError,
+			Message:  fmt.Sprintf("Failed to create gRPC client: %v", err),
+			Resource: errors.Resource{OrgID: orgID},
+		}), nil
 	}
 	defer client.Close()
 
 	// Query environments
 	environments, err := client.FindByOrg(ctx, orgID)
 	if err != nil {
-		return errors.HandleGRPCError(err, orgID), nil
+		return errors.HandleGRPCError(ctx, err, errors.Resource{OrgID: orgID}), nil
 	}
 
 	// Convert protobuf objects to JSON-serializable structs
@@ -106,21 +93,18 @@ func HandleListEnvironmentsForOrg(
 		envList = append(envList, envSimple)
 	}
 
-	log.Printf(
-		"Tool completed: list_environments_for_org, returned %d environments",
-		len(envList),
+	slog.DebugContext(ctx, "Tool completed",
+		"count", len(envList),
 	)
 
 	// Return formatted JSON response
 	resultJSON, err := json.MarshalIndent(envList, "", "  ")
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
-			Message: fmt.Sprintf("Failed to marshal response: %v", err),
-			OrgID:   orgID,
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		return errors.ToolError(errors.ErrorResponse{
+			Error:    errors.CodeInternal,
+			Message:  fmt.Sprintf("Failed to marshal response: %v", err),
+			Resource: errors.Resource{OrgID: orgID},
+		}), nil
 	}
 
 	return mcp.NewToolResultText(string(resultJSON)), nil
@@ -2,31 +2,29 @@ package environment
 
 import (
 	"context"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/plantoncloud/mcp-server-planton/internal/common/auth"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 )
 
 // RegisterTools registers all environment tools with the MCP server.
-func RegisterTools(s *server.MCPServer, cfg *config.Config) {
-	registerListTool(s, cfg)
-	// Future: registerGetTool(s, cfg)
-	// Future: registerCreateTool(s, cfg)
+func RegisterTools(s *server.MCPServer, factory clientfactory.Factory) {
+	registerListTool(s, factory)
+	// Future: registerGetTool(s, factory)
+	// Future: registerCreateTool(s, factory)
 
-	log.Println("Registered 1 environment tool")
+	slog.Debug("Registered 1 environment tool")
 }
 
 // registerListTool registers the list_environments_for_org tool.
-func registerListTool(s *server.MCPServer, cfg *config.Config) {
+func registerListTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateListEnvironmentsTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleListEnvironmentsForOrg(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleListEnvironmentsForOrg(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - list_environments_for_org")
+	slog.Debug("Registered tool", "tool", "list_environments_for_org")
 }
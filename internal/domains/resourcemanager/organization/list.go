@@ -4,12 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
-	"github.com/plantoncloud/mcp-server-planton/internal/domains/resourcemanager/clients"
 )
 
 // OrganizationSimple is a simplified representation of an Organization for JSON serialization.
@@ -31,6 +30,7 @@ func CreateListOrganizationsTool() mcp.Tool {
 			Type:       "object",
 			Properties: map[string]interface{}{},
 		},
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
 	}
 }
 
@@ -44,35 +44,24 @@ func CreateListOrganizationsTool() mcp.Tool {
 func HandleListOrganizations(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
-	log.Printf("Tool invoked: list_organizations")
+	slog.DebugContext(ctx, "Tool invoked")
 
-	// Create gRPC client with per-user API key from context
-	// For HTTP transport: API key extracted from Authorization header
-	// For STDIO transport: API key from environment variable (fallback to config)
-	client, err := clients.NewOrganizationClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
+	// Create gRPC client with the caller's credentials (see auth.CredentialPolicy)
+	client, err := factory.OrganizationClient(ctx)
 	if err != nil {
-		// Fallback to config API key for STDIO mode
-		client, err = clients.NewOrganizationClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			errResp := errors.ErrorResponse{
-				Error:   "CLIENT_ERROR",
-				Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
-		}
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeClientError,
+			Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
+		}), nil
 	}
 	defer client.Close()
 
 	// Query organizations
 	organizations, err := client.List(ctx)
 	if err != nil {
-		return errors.HandleGRPCError(err, ""), nil
+		return errors.HandleGRPCError(ctx, err, errors.Resource{}), nil
 	}
 
 	// Convert protobuf objects to JSON-serializable structs
@@ -87,20 +76,17 @@ func HandleListOrganizations(
 		orgList = append(orgList, orgSimple)
 	}
 
-	log.Printf(
-		"Tool completed: list_organizations, returned %d organizations",
-		len(orgList),
+	slog.DebugContext(ctx, "Tool completed",
+		"count", len(orgList),
 	)
 
 	// Return formatted JSON response
 	resultJSON, err := json.MarshalIndent(orgList, "", "  ")
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: fmt.Sprintf("Failed to marshal response: %v", err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	return mcp.NewToolResultText(string(resultJSON)), nil
@@ -0,0 +1,132 @@
+package organization
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/commons/apiresource"
+	organizationv1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/resourcemanager/organization/v1"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/auth"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeFactory hands out fakeOrganizationClient. Other clients are not implemented.
+type fakeFactory struct {
+	clientfactory.Factory
+	client *fakeOrganizationClient
+	err    error
+}
+
+func (f *fakeFactory) OrganizationClient(context.Context) (clientfactory.OrganizationClient, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.client, nil
+}
+
+// fakeOrganizationClient returns fixed organizations or a fixed error.
+type fakeOrganizationClient struct {
+	organizations []*organizationv1.Organization
+	err           error
+	closed        bool
+}
+
+func (c *fakeOrganizationClient) List(context.Context) ([]*organizationv1.Organization, error) {
+	return c.organizations, c.err
+}
+
+func (c *fakeOrganizationClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+// resultText returns the text content of a tool result.
+func resultText(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+	if len(result.Content) != 1 {
+		t.Fatalf("got %d content items, want 1", len(result.Content))
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("got content %T, want mcp.TextContent", result.Content[0])
+	}
+	return text.Text
+}
+
+func TestHandleListOrganizations(t *testing.T) {
+	client := &fakeOrganizationClient{
+		organizations: []*organizationv1.Organization{{
+			Metadata: &apiresource.ApiResourceMetadata{Id: "org-1", Slug: "acme", Name: "Acme"},
+			Spec:     &organizationv1.OrganizationSpec{Description: "Acme Corp"},
+		}},
+	}
+
+	result, err := HandleListOrganizations(context.Background(), nil, &fakeFactory{client: client})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []OrganizationSimple
+	if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := OrganizationSimple{ID: "org-1", Slug: "acme", Name: "Acme", Description: "Acme Corp"}
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("got %+v, want [%+v]", got, want)
+	}
+	if !client.closed {
+		t.Error("client was not closed")
+	}
+}
+
+func TestHandleListOrganizationsErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		factory   *fakeFactory
+		wantError string
+	}{
+		{
+			name:      "no credentials",
+			factory:   &fakeFactory{err: auth.ErrNoCredentials},
+			wantError: "CLIENT_ERROR",
+		},
+		{
+			name: "permission denied",
+			factory: &fakeFactory{client: &fakeOrganizationClient{
+				err: status.Error(codes.PermissionDenied, "denied"),
+			}},
+			wantError: "PERMISSION_DENIED",
+		},
+		{
+			name: "not a gRPC error",
+			factory: &fakeFactory{client: &fakeOrganizationClient{
+				err: errors.New("boom"),
+			}},
+			wantError: "UNKNOWN_ERROR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := HandleListOrganizations(context.Background(), nil, tt.factory)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got struct {
+				Error string `json:"error"`
+			}
+			if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Error != tt.wantError {
+				t.Errorf("error = %q, want %q", got.Error, tt.wantError)
+			}
+		})
+	}
+}
@@ -2,31 +2,29 @@ package organization
 
 import (
 	"context"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/plantoncloud/mcp-server-planton/internal/common/auth"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 )
 
 // RegisterTools registers all organization tools with the MCP server.
-func RegisterTools(s *server.MCPServer, cfg *config.Config) {
-	registerListTool(s, cfg)
-	// Future: registerGetTool(s, cfg)
-	// Future: registerCreateTool(s, cfg)
+func RegisterTools(s *server.MCPServer, factory clientfactory.Factory) {
+	registerListTool(s, factory)
+	// Future: registerGetTool(s, factory)
+	// Future: registerCreateTool(s, factory)
 
-	log.Println("Registered 1 organization tool")
+	slog.Debug("Registered 1 organization tool")
 }
 
 // registerListTool registers the list_organizations tool.
-func registerListTool(s *server.MCPServer, cfg *config.Config) {
+func registerListTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateListOrganizationsTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleListOrganizations(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleListOrganizations(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - list_organizations")
+	slog.Debug("Registered tool", "tool", "list_organizations")
 }
@@ -1,23 +1,23 @@
 package resourcemanager
 
 import (
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/domains/resourcemanager/environment"
 	"github.com/plantoncloud/mcp-server-planton/internal/domains/resourcemanager/organization"
 )
 
 // RegisterTools registers all ResourceManager domain tools with the MCP server.
-func RegisterTools(s *server.MCPServer, cfg *config.Config) {
-	log.Println("Registering ResourceManager tools...")
+func RegisterTools(s *server.MCPServer, factory clientfactory.Factory) {
+	slog.Debug("Registering ResourceManager tools...")
 
 	// Register environment tools
-	environment.RegisterTools(s, cfg)
+	environment.RegisterTools(s, factory)
 
 	// Register organization tools
-	organization.RegisterTools(s, cfg)
+	organization.RegisterTools(s, factory)
 
-	log.Println("ResourceManager tools registration complete")
+	slog.Debug("ResourceManager tools registration complete")
 }
@@ -3,18 +3,12 @@ package clients
 import (
 	"context"
 	"fmt"
-	"log"
-	"strings"
-	"time"
+	"log/slog"
 
 	pipelinev1grpc "buf.build/gen/go/blintora/apis/grpc/go/ai/planton/servicehub/pipeline/v1/pipelinev1grpc"
 	pipelinev1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/servicehub/pipeline/v1"
 	servicev1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/servicehub/service/v1"
-	commonauth "github.com/plantoncloud/mcp-server-planton/internal/common/auth"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/grpc/keepalive"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/grpcpool"
 )
 
 // PipelineClient is a gRPC client for querying Planton Cloud Pipeline resources.
@@ -24,7 +18,7 @@ import (
 // API key and enforce Fine-Grained Authorization (FGA) checks based on the
 // user's actual permissions.
 type PipelineClient struct {
-	conn   *grpc.ClientConn
+	conn   *grpcpool.Conn
 	client pipelinev1grpc.PipelineQueryControllerClient
 }
 
@@ -36,32 +30,8 @@ type PipelineClient struct {
 //
 // Returns a PipelineClient and any error encountered during connection setup.
 func NewPipelineClient(grpcEndpoint, apiKey string) (*PipelineClient, error) {
-	// Determine transport credentials based on endpoint port
-	var transportCreds credentials.TransportCredentials
-	if strings.HasSuffix(grpcEndpoint, ":443") {
-		// Use TLS for port 443 (production endpoints)
-		transportCreds = credentials.NewTLS(nil)
-		log.Printf("Using TLS transport for endpoint: %s", grpcEndpoint)
-	} else {
-		// Use insecure for other ports (local development)
-		transportCreds = insecure.NewCredentials()
-		log.Printf("Using insecure transport for endpoint: %s", grpcEndpoint)
-	}
-
-	// Create gRPC dial options with per-RPC credentials and keepalive settings
-	// Keepalive prevents connection drops during long-running streaming operations
-	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(transportCreds),
-		grpc.WithPerRPCCredentials(commonauth.NewTokenAuth(apiKey)),
-		grpc.WithKeepaliveParams(keepalive.ClientParameters{
-			Time:                30 * time.Second, // Send pings every 30 seconds if no activity
-			Timeout:             10 * time.Second, // Wait 10 seconds for ping ack before considering connection dead
-			PermitWithoutStream: true,             // Allow pings even when no streams are active
-		}),
-	}
-
-	// Establish connection
-	conn, err := grpc.NewClient(grpcEndpoint, opts...)
+	// Borrow the shared connection to the endpoint; the API key is attached per RPC
+	conn, err := grpcpool.Dial(grpcEndpoint, apiKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
 	}
@@ -69,7 +39,7 @@ func NewPipelineClient(grpcEndpoint, apiKey string) (*PipelineClient, error) {
 	// Create pipeline query client
 	client := pipelinev1grpc.NewPipelineQueryControllerClient(conn)
 
-	log.Printf("PipelineClient initialized for endpoint: %s", grpcEndpoint)
+	slog.Debug("PipelineClient initialized", "endpoint", grpcEndpoint)
 
 	return &PipelineClient{
 		conn:   conn,
@@ -89,7 +59,7 @@ func NewPipelineClient(grpcEndpoint, apiKey string) (*PipelineClient, error) {
 //
 // Returns the full Pipeline object or an error.
 func (c *PipelineClient) GetById(ctx context.Context, pipelineID string) (*pipelinev1.Pipeline, error) {
-	log.Printf("Querying pipeline by ID: %s", pipelineID)
+	slog.DebugContext(ctx, "Querying pipeline by ID", "pipeline_id", pipelineID)
 
 	// Create request
 	req := &pipelinev1.PipelineId{
@@ -99,11 +69,11 @@ func (c *PipelineClient) GetById(ctx context.Context, pipelineID string) (*pipel
 	// Make gRPC call (interceptor attaches API key automatically)
 	resp, err := c.client.Get(ctx, req)
 	if err != nil {
-		log.Printf("gRPC error querying pipeline %s: %v", pipelineID, err)
+		slog.DebugContext(ctx, "gRPC error querying pipeline", "pipeline_id", pipelineID, "error", err)
 		return nil, err
 	}
 
-	log.Printf("Successfully retrieved pipeline: %s", pipelineID)
+	slog.DebugContext(ctx, "Successfully retrieved pipeline", "pipeline_id", pipelineID)
 
 	return resp, nil
 }
@@ -120,7 +90,7 @@ func (c *PipelineClient) GetById(ctx context.Context, pipelineID string) (*pipel
 //
 // Returns the full Pipeline object or an error.
 func (c *PipelineClient) GetLastPipelineByServiceId(ctx context.Context, serviceID string) (*pipelinev1.Pipeline, error) {
-	log.Printf("Querying latest pipeline for service: %s", serviceID)
+	slog.DebugContext(ctx, "Querying latest pipeline for service", "service_id", serviceID)
 
 	// Create request
 	req := &servicev1.ServiceId{
@@ -130,11 +100,11 @@ func (c *PipelineClient) GetLastPipelineByServiceId(ctx context.Context, service
 	// Make gRPC call
 	resp, err := c.client.GetLastPipelineByServiceId(ctx, req)
 	if err != nil {
-		log.Printf("gRPC error querying latest pipeline for service %s: %v", serviceID, err)
+		slog.DebugContext(ctx, "gRPC error querying latest pipeline for service", "service_id", serviceID, "error", err)
 		return nil, err
 	}
 
-	log.Printf("Successfully retrieved latest pipeline for service: %s", serviceID)
+	slog.DebugContext(ctx, "Successfully retrieved latest pipeline for service", "service_id", serviceID)
 
 	return resp, nil
 }
@@ -150,7 +120,7 @@ func (c *PipelineClient) GetLastPipelineByServiceId(ctx context.Context, service
 //
 // Returns a stream client for reading TektonTaskLogEntry messages or an error.
 func (c *PipelineClient) GetLogStream(ctx context.Context, pipelineID string) (pipelinev1grpc.PipelineQueryController_GetLogStreamClient, error) {
-	log.Printf("Starting log stream for pipeline: %s", pipelineID)
+	slog.DebugContext(ctx, "Starting log stream", "pipeline_id", pipelineID)
 
 	// Create request
 	req := &pipelinev1.PipelineId{
@@ -160,11 +130,11 @@ func (c *PipelineClient) GetLogStream(ctx context.Context, pipelineID string) (p
 	// Make gRPC streaming call
 	stream, err := c.client.GetLogStream(ctx, req)
 	if err != nil {
-		log.Printf("gRPC error starting log stream for pipeline %s: %v", pipelineID, err)
+		slog.DebugContext(ctx, "gRPC error starting log stream", "pipeline_id", pipelineID, "error", err)
 		return nil, err
 	}
 
-	log.Printf("Successfully started log stream for pipeline: %s", pipelineID)
+	slog.DebugContext(ctx, "Successfully started log stream", "pipeline_id", pipelineID)
 
 	return stream, nil
 }
@@ -179,7 +149,7 @@ func (c *PipelineClient) GetLogStream(ctx context.Context, pipelineID string) (p
 //
 // Returns a stream client for reading PipelineStatus messages or an error.
 func (c *PipelineClient) GetStatusStream(ctx context.Context, pipelineID string) (pipelinev1grpc.PipelineQueryController_GetStatusStreamClient, error) {
-	log.Printf("Starting status stream for pipeline: %s", pipelineID)
+	slog.DebugContext(ctx, "Starting status stream", "pipeline_id", pipelineID)
 
 	// Create request
 	req := &pipelinev1.PipelineId{
@@ -189,40 +159,20 @@ func (c *PipelineClient) GetStatusStream(ctx context.Context, pipelineID string)
 	// Make gRPC streaming call
 	stream, err := c.client.GetStatusStream(ctx, req)
 	if err != nil {
-		log.Printf("gRPC error starting status stream for pipeline %s: %v", pipelineID, err)
+		slog.DebugContext(ctx, "gRPC error starting status stream", "pipeline_id", pipelineID, "error", err)
 		return nil, err
 	}
 
-	log.Printf("Successfully started status stream for pipeline: %s", pipelineID)
+	slog.DebugContext(ctx, "Successfully started status stream", "pipeline_id", pipelineID)
 
 	return stream, nil
 }
 
-// NewPipelineClientFromContext creates a new Pipeline gRPC client
-// using the API key from the request context.
-//
-// This constructor is used in HTTP transport mode to create clients with per-user API keys
-// extracted from Authorization headers, enabling proper multi-user support with Fine-Grained
-// Authorization.
-//
-// Args:
-//   - ctx: Context containing the API key (set by HTTP authentication middleware)
-//   - grpcEndpoint: Planton Cloud APIs endpoint (e.g., "localhost:8080" or "api.live.planton.ai:443")
-//
-// Returns a PipelineClient and any error encountered during connection setup.
-// Returns an error if no API key is found in the context.
-func NewPipelineClientFromContext(ctx context.Context, grpcEndpoint string) (*PipelineClient, error) {
-	apiKey, err := commonauth.GetAPIKey(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get API key from context: %w", err)
-	}
-	return NewPipelineClient(grpcEndpoint, apiKey)
-}
-
-// Close closes the gRPC connection.
+// Close gives the pooled gRPC connection back. The shared connection stays open
+// for other calls.
 func (c *PipelineClient) Close() error {
 	if c.conn != nil {
-		log.Println("Closing PipelineClient connection")
+		slog.Debug("Releasing PipelineClient connection")
 		return c.conn.Close()
 	}
 	return nil
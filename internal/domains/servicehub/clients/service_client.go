@@ -3,17 +3,13 @@ package clients
 import (
 	"context"
 	"fmt"
-	"log"
-	"strings"
+	"log/slog"
 
 	servicev1grpc "buf.build/gen/go/blintora/apis/grpc/go/ai/planton/servicehub/service/v1/servicev1grpc"
 	"buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/commons/apiresource"
 	"buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/commons/protobuf"
 	servicev1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/servicehub/service/v1"
-	commonauth "github.com/plantoncloud/mcp-server-planton/internal/common/auth"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/grpcpool"
 )
 
 // ServiceClient is a gRPC client for querying Planton Cloud Service Hub Service resources.
@@ -23,7 +19,7 @@ import (
 // API key and enforce Fine-Grained Authorization (FGA) checks based on the
 // user's actual permissions.
 type ServiceClient struct {
-	conn   *grpc.ClientConn
+	conn   *grpcpool.Conn
 	client servicev1grpc.ServiceQueryControllerClient
 }
 
@@ -35,26 +31,8 @@ type ServiceClient struct {
 //
 // Returns a ServiceClient and any error encountered during connection setup.
 func NewServiceClient(grpcEndpoint, apiKey string) (*ServiceClient, error) {
-	// Determine transport credentials based on endpoint port
-	var transportCreds credentials.TransportCredentials
-	if strings.HasSuffix(grpcEndpoint, ":443") {
-		// Use TLS for port 443 (production endpoints)
-		transportCreds = credentials.NewTLS(nil)
-		log.Printf("Using TLS transport for endpoint: %s", grpcEndpoint)
-	} else {
-		// Use insecure for other ports (local development)
-		transportCreds = insecure.NewCredentials()
-		log.Printf("Using insecure transport for endpoint: %s", grpcEndpoint)
-	}
-
-	// Create gRPC dial options with per-RPC credentials (matches CLI pattern)
-	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(transportCreds),
-		grpc.WithPerRPCCredentials(commonauth.NewTokenAuth(apiKey)),
-	}
-
-	// Establish connection
-	conn, err := grpc.NewClient(grpcEndpoint, opts...)
+	// Borrow the shared connection to the endpoint; the API key is attached per RPC
+	conn, err := grpcpool.Dial(grpcEndpoint, apiKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
 	}
@@ -62,7 +40,7 @@ func NewServiceClient(grpcEndpoint, apiKey string) (*ServiceClient, error) {
 	// Create service query client
 	client := servicev1grpc.NewServiceQueryControllerClient(conn)
 
-	log.Printf("ServiceClient initialized for endpoint: %s", grpcEndpoint)
+	slog.Debug("ServiceClient initialized", "endpoint", grpcEndpoint)
 
 	return &ServiceClient{
 		conn:   conn,
@@ -82,7 +60,7 @@ func NewServiceClient(grpcEndpoint, apiKey string) (*ServiceClient, error) {
 //
 // Returns the full Service object or an error.
 func (c *ServiceClient) GetById(ctx context.Context, serviceID string) (*servicev1.Service, error) {
-	log.Printf("Querying service by ID: %s", serviceID)
+	slog.DebugContext(ctx, "Querying service by ID", "service_id", serviceID)
 
 	// Create request
 	req := &servicev1.ServiceId{
@@ -92,11 +70,11 @@ func (c *ServiceClient) GetById(ctx context.Context, serviceID string) (*service
 	// Make gRPC call (interceptor attaches API key automatically)
 	resp, err := c.client.Get(ctx, req)
 	if err != nil {
-		log.Printf("gRPC error querying service %s: %v", serviceID, err)
+		slog.DebugContext(ctx, "gRPC error querying service", "service_id", serviceID, "error", err)
 		return nil, err
 	}
 
-	log.Printf("Successfully retrieved service: %s", serviceID)
+	slog.DebugContext(ctx, "Successfully retrieved service", "service_id", serviceID)
 
 	return resp, nil
 }
@@ -110,7 +88,7 @@ func (c *ServiceClient) GetById(ctx context.Context, serviceID string) (*service
 //
 // Returns the full Service object or an error.
 func (c *ServiceClient) GetByOrgBySlug(ctx context.Context, orgID, slug string) (*servicev1.Service, error) {
-	log.Printf("Querying service by org: %s, slug: %s", orgID, slug)
+	slog.DebugContext(ctx, "Querying service by org and slug", "org_id", orgID, "slug", slug)
 
 	// Create request
 	req := &apiresource.ApiResourceByOrgBySlugRequest{
@@ -121,11 +99,11 @@ func (c *ServiceClient) GetByOrgBySlug(ctx context.Context, orgID, slug string)
 	// Make gRPC call (interceptor attaches API key automatically)
 	resp, err := c.client.GetByOrgBySlug(ctx, req)
 	if err != nil {
-		log.Printf("gRPC error querying service by org/slug: %v", err)
+		slog.DebugContext(ctx, "gRPC error querying service by org and slug", "org_id", orgID, "slug", slug, "error", err)
 		return nil, err
 	}
 
-	log.Printf("Successfully retrieved service: %s/%s", orgID, slug)
+	slog.DebugContext(ctx, "Successfully retrieved service", "org_id", orgID, "slug", slug)
 
 	return resp, nil
 }
@@ -138,7 +116,7 @@ func (c *ServiceClient) GetByOrgBySlug(ctx context.Context, orgID, slug string)
 //
 // Returns a list of branch names or an error.
 func (c *ServiceClient) ListBranches(ctx context.Context, serviceID string) (*protobuf.StringList, error) {
-	log.Printf("Listing branches for service: %s", serviceID)
+	slog.DebugContext(ctx, "Listing branches for service", "service_id", serviceID)
 
 	// Create request
 	req := &servicev1.ServiceId{
@@ -148,11 +126,11 @@ func (c *ServiceClient) ListBranches(ctx context.Context, serviceID string) (*pr
 	// Make gRPC call (interceptor attaches API key automatically)
 	resp, err := c.client.ListBranches(ctx, req)
 	if err != nil {
-		log.Printf("gRPC error listing branches for service %s: %v", serviceID, err)
+		slog.DebugContext(ctx, "gRPC error listing branches for service", "service_id", serviceID, "error", err)
 		return nil, err
 	}
 
-	log.Printf("Successfully retrieved %d branches for service %s", len(resp.GetEntries()), serviceID)
+	slog.DebugContext(ctx, "Successfully retrieved branches for service", "service_id", serviceID, "count", len(resp.GetEntries()))
 
 	return resp, nil
 }
@@ -165,45 +143,25 @@ func (c *ServiceClient) ListBranches(ctx context.Context, serviceID string) (*pr
 //
 // Returns a list of services or an error.
 func (c *ServiceClient) Find(ctx context.Context, request *apiresource.FindApiResourcesRequest) (*servicev1.ServiceList, error) {
-	log.Printf("Finding services with filters")
+	slog.DebugContext(ctx, "Finding services with filters")
 
 	// Make gRPC call (interceptor attaches API key automatically)
 	resp, err := c.client.Find(ctx, request)
 	if err != nil {
-		log.Printf("gRPC error finding services: %v", err)
+		slog.DebugContext(ctx, "gRPC error finding services", "error", err)
 		return nil, err
 	}
 
-	log.Printf("Successfully found %d services", len(resp.GetEntries()))
+	slog.DebugContext(ctx, "Successfully found services", "count", len(resp.GetEntries()))
 
 	return resp, nil
 }
 
-// NewServiceClientFromContext creates a new Service gRPC client
-// using the API key from the request context.
-//
-// This constructor is used in HTTP transport mode to create clients with per-user API keys
-// extracted from Authorization headers, enabling proper multi-user support with Fine-Grained
-// Authorization.
-//
-// Args:
-//   - ctx: Context containing the API key (set by HTTP authentication middleware)
-//   - grpcEndpoint: Planton Cloud APIs endpoint (e.g., "localhost:8080" or "api.live.planton.ai:443")
-//
-// Returns a ServiceClient and any error encountered during connection setup.
-// Returns an error if no API key is found in the context.
-func NewServiceClientFromContext(ctx context.Context, grpcEndpoint string) (*ServiceClient, error) {
-	apiKey, err := commonauth.GetAPIKey(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get API key from context: %w", err)
-	}
-	return NewServiceClient(grpcEndpoint, apiKey)
-}
-
-// Close closes the gRPC connection.
+// Close gives the pooled gRPC connection back. The shared connection stays open
+// for other calls.
 func (c *ServiceClient) Close() error {
 	if c.conn != nil {
-		log.Println("Closing ServiceClient connection")
+		slog.Debug("Releasing ServiceClient connection")
 		return c.conn.Close()
 	}
 	return nil
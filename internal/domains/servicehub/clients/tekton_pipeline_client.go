@@ -3,16 +3,12 @@ package clients
 import (
 	"context"
 	"fmt"
-	"log"
-	"strings"
+	"log/slog"
 
 	tektonpipelinev1grpc "buf.build/gen/go/blintora/apis/grpc/go/ai/planton/servicehub/tektonpipeline/v1/tektonpipelinev1grpc"
 	"buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/commons/apiresource"
 	tektonpipelinev1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/servicehub/tektonpipeline/v1"
-	commonauth "github.com/plantoncloud/mcp-server-planton/internal/common/auth"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/grpcpool"
 )
 
 // TektonPipelineClient is a gRPC client for querying Planton Cloud Tekton Pipeline resources.
@@ -22,7 +18,7 @@ import (
 // API key and enforce Fine-Grained Authorization (FGA) checks based on the
 // user's actual permissions.
 type TektonPipelineClient struct {
-	conn   *grpc.ClientConn
+	conn   *grpcpool.Conn
 	client tektonpipelinev1grpc.TektonPipelineQueryControllerClient
 }
 
@@ -34,26 +30,8 @@ type TektonPipelineClient struct {
 //
 // Returns a TektonPipelineClient and any error encountered during connection setup.
 func NewTektonPipelineClient(grpcEndpoint, apiKey string) (*TektonPipelineClient, error) {
-	// Determine transport credentials based on endpoint port
-	var transportCreds credentials.TransportCredentials
-	if strings.HasSuffix(grpcEndpoint, ":443") {
-		// Use TLS for port 443 (production endpoints)
-		transportCreds = credentials.NewTLS(nil)
-		log.Printf("Using TLS transport for endpoint: %s", grpcEndpoint)
-	} else {
-		// Use insecure for other ports (local development)
-		transportCreds = insecure.NewCredentials()
-		log.Printf("Using insecure transport for endpoint: %s", grpcEndpoint)
-	}
-
-	// Create gRPC dial options with per-RPC credentials (matches CLI pattern)
-	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(transportCreds),
-		grpc.WithPerRPCCredentials(commonauth.NewTokenAuth(apiKey)),
-	}
-
-	// Establish connection
-	conn, err := grpc.NewClient(grpcEndpoint, opts...)
+	// Borrow the shared connection to the endpoint; the API key is attached per RPC
+	conn, err := grpcpool.Dial(grpcEndpoint, apiKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
 	}
@@ -61,7 +39,7 @@ func NewTektonPipelineClient(grpcEndpoint, apiKey string) (*TektonPipelineClient
 	// Create tekton pipeline query client
 	client := tektonpipelinev1grpc.NewTektonPipelineQueryControllerClient(conn)
 
-	log.Printf("TektonPipelineClient initialized for endpoint: %s", grpcEndpoint)
+	slog.Debug("TektonPipelineClient initialized", "endpoint", grpcEndpoint)
 
 	return &TektonPipelineClient{
 		conn:   conn,
@@ -81,7 +59,7 @@ func NewTektonPipelineClient(grpcEndpoint, apiKey string) (*TektonPipelineClient
 //
 // Returns the full TektonPipeline object or an error.
 func (c *TektonPipelineClient) GetById(ctx context.Context, pipelineID string) (*tektonpipelinev1.TektonPipeline, error) {
-	log.Printf("Querying Tekton pipeline by ID: %s", pipelineID)
+	slog.DebugContext(ctx, "Querying Tekton pipeline by ID", "pipeline_id", pipelineID)
 
 	// Create request
 	req := &apiresource.ApiResourceId{
@@ -91,11 +69,11 @@ func (c *TektonPipelineClient) GetById(ctx context.Context, pipelineID string) (
 	// Make gRPC call (interceptor attaches API key automatically)
 	resp, err := c.client.Get(ctx, req)
 	if err != nil {
-		log.Printf("gRPC error querying Tekton pipeline %s: %v", pipelineID, err)
+		slog.DebugContext(ctx, "gRPC error querying Tekton pipeline", "pipeline_id", pipelineID, "error", err)
 		return nil, err
 	}
 
-	log.Printf("Successfully retrieved Tekton pipeline: %s", pipelineID)
+	slog.DebugContext(ctx, "Successfully retrieved Tekton pipeline", "pipeline_id", pipelineID)
 
 	return resp, nil
 }
@@ -109,7 +87,7 @@ func (c *TektonPipelineClient) GetById(ctx context.Context, pipelineID string) (
 //
 // Returns the full TektonPipeline object or an error.
 func (c *TektonPipelineClient) GetByOrgAndName(ctx context.Context, orgID, name string) (*tektonpipelinev1.TektonPipeline, error) {
-	log.Printf("Querying Tekton pipeline by org: %s, name: %s", orgID, name)
+	slog.DebugContext(ctx, "Querying Tekton pipeline by org and name", "org_id", orgID, "name", name)
 
 	// Create request
 	req := &tektonpipelinev1.GetByOrgAndNameInput{
@@ -120,40 +98,20 @@ func (c *TektonPipelineClient) GetByOrgAndName(ctx context.Context, orgID, name
 	// Make gRPC call (interceptor attaches API key automatically)
 	resp, err := c.client.GetByOrgAndName(ctx, req)
 	if err != nil {
-		log.Printf("gRPC error querying Tekton pipeline by org/name: %v", err)
+		slog.DebugContext(ctx, "gRPC error querying Tekton pipeline by org and name", "org_id", orgID, "name", name, "error", err)
 		return nil, err
 	}
 
-	log.Printf("Successfully retrieved Tekton pipeline: %s/%s", orgID, name)
+	slog.DebugContext(ctx, "Successfully retrieved Tekton pipeline", "org_id", orgID, "name", name)
 
 	return resp, nil
 }
 
-// NewTektonPipelineClientFromContext creates a new Tekton Pipeline gRPC client
-// using the API key from the request context.
-//
-// This constructor is used in HTTP transport mode to create clients with per-user API keys
-// extracted from Authorization headers, enabling proper multi-user support with Fine-Grained
-// Authorization.
-//
-// Args:
-//   - ctx: Context containing the API key (set by HTTP authentication middleware)
-//   - grpcEndpoint: Planton Cloud APIs endpoint (e.g., "localhost:8080" or "api.live.planton.ai:443")
-//
-// Returns a TektonPipelineClient and any error encountered during connection setup.
-// Returns an error if no API key is found in the context.
-func NewTektonPipelineClientFromContext(ctx context.Context, grpcEndpoint string) (*TektonPipelineClient, error) {
-	apiKey, err := commonauth.GetAPIKey(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get API key from context: %w", err)
-	}
-	return NewTektonPipelineClient(grpcEndpoint, apiKey)
-}
-
-// Close closes the gRPC connection.
+// Close gives the pooled gRPC connection back. The shared connection stays open
+// for other calls.
 func (c *TektonPipelineClient) Close() error {
 	if c.conn != nil {
-		log.Println("Closing TektonPipelineClient connection")
+		slog.Debug("Releasing TektonPipelineClient connection")
 		return c.conn.Close()
 	}
 	return nil
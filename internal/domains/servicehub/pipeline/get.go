@@ -4,12 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
-	"github.com/plantoncloud/mcp-server-planton/internal/domains/servicehub/clients"
 )
 
 // PipelineSimple is a simplified representation of a Pipeline for JSON serialization.
@@ -62,6 +61,7 @@ func CreateGetPipelineByIdTool() mcp.Tool {
 			},
 			Required: []string{"pipeline_id"},
 		},
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
 	}
 }
 
@@ -69,43 +69,33 @@ func CreateGetPipelineByIdTool() mcp.Tool {
 func HandleGetPipelineById(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
-	log.Printf("Tool invoked: get_pipeline_by_id")
+	slog.DebugContext(ctx, "Tool invoked")
 
 	// Extract pipeline_id from arguments
 	pipelineID, ok := arguments["pipeline_id"].(string)
 	if !ok || pipelineID == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "pipeline_id is required",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	// Create gRPC client
-	client, err := clients.NewPipelineClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
+	client, err := factory.PipelineClient(ctx)
 	if err != nil {
-		client, err = clients.NewPipelineClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			errResp := errors.ErrorResponse{
-				Error:   "CLIENT_ERROR",
-				Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
-		}
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeClientError,
+			Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
+		}), nil
 	}
 	defer client.Close()
 
 	// Query pipeline
 	pipeline, err := client.GetById(ctx, pipelineID)
 	if err != nil {
-		return errors.HandleGRPCError(err, pipelineID), nil
+		return errors.HandleGRPCError(ctx, err, errors.Resource{PipelineID: pipelineID}), nil
 	}
 
 	// Convert to simple struct
@@ -165,17 +155,15 @@ func HandleGetPipelineById(
 		}
 	}
 
-	log.Printf("Tool completed: get_pipeline_by_id, pipeline: %s", pipelineID)
+	slog.DebugContext(ctx, "Tool completed", "pipeline_id", pipelineID)
 
 	// Return formatted JSON response
 	resultJSON, err := json.MarshalIndent(pipelineSimple, "", "  ")
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: fmt.Sprintf("Failed to marshal response: %v", err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	return mcp.NewToolResultText(string(resultJSON)), nil
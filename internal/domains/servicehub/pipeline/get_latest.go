@@ -4,12 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
-	"github.com/plantoncloud/mcp-server-planton/internal/domains/servicehub/clients"
 )
 
 // CreateGetLatestPipelineByServiceIdTool creates the MCP tool definition for getting latest pipeline by service ID.
@@ -29,6 +28,7 @@ func CreateGetLatestPipelineByServiceIdTool() mcp.Tool {
 			},
 			Required: []string{"service_id"},
 		},
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
 	}
 }
 
@@ -36,43 +36,33 @@ func CreateGetLatestPipelineByServiceIdTool() mcp.Tool {
 func HandleGetLatestPipelineByServiceId(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
-	log.Printf("Tool invoked: get_latest_pipeline_by_service_id")
+	slog.DebugContext(ctx, "Tool invoked")
 
 	// Extract service_id from arguments
 	serviceID, ok := arguments["service_id"].(string)
 	if !ok || serviceID == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "service_id is required",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	// Create gRPC client
-	client, err := clients.NewPipelineClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
+	client, err := factory.PipelineClient(ctx)
 	if err != nil {
-		client, err = clients.NewPipelineClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			errResp := errors.ErrorResponse{
-				Error:   "CLIENT_ERROR",
-				Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
-		}
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeClientError,
+			Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
+		}), nil
 	}
 	defer client.Close()
 
 	// Query latest pipeline for service
 	pipeline, err := client.GetLastPipelineByServiceId(ctx, serviceID)
 	if err != nil {
-		return errors.HandleGRPCError(err, serviceID), nil
+		return errors.HandleGRPCError(ctx, err, errors.Resource{ResourceID: serviceID}), nil
 	}
 
 	// Convert to simple struct
@@ -132,17 +122,18 @@ func HandleGetLatestPipelineByServiceId(
 		}
 	}
 
-	log.Printf("Tool completed: get_latest_pipeline_by_service_id, service: %s, pipeline: %s", serviceID, pipelineSimple.ID)
+	slog.DebugContext(ctx, "Tool completed",
+		"service_id", serviceID,
+		"pipeline_id", pipelineSimple.ID,
+	)
 
 	// Return formatted JSON response
 	resultJSON, err := json.MarshalIndent(pipelineSimple, "", "  ")
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: fmt.Sprintf("Failed to marshal response: %v", err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	return mcp.NewToolResultText(string(resultJSON)), nil
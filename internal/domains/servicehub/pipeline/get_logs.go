@@ -5,20 +5,25 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
-	"github.com/plantoncloud/mcp-server-planton/internal/domains/servicehub/clients"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/metrics"
 )
 
 const (
-	// MaxLogStreamDuration is the maximum time allowed for streaming logs
+	// MaxLogStreamDuration is the default time allowed for streaming logs
 	// Reduced to 45 seconds to prevent HTTP/SSE connection timeouts and improve responsiveness
 	MaxLogStreamDuration = 45 * time.Second
 
+	// LogResponseReserve is the part of the tool call's deadline kept free to build the
+	// response once streaming stops, so partial logs are returned instead of a timeout.
+	// Short deadlines keep a quarter of the time left instead.
+	LogResponseReserve = 5 * time.Second
+
 	// MaxLogEntries is the maximum number of log entries to return
 	// Prevents overwhelming the client and hitting timeout limits
 	MaxLogEntries = 5000
@@ -60,7 +65,7 @@ func CreateGetPipelineBuildLogsTool() mcp.Tool {
 			"Returns Tekton task logs including build output, errors, and diagnostic messages. " +
 			"Logs are fetched from Redis (for running pipelines) or R2 storage (for completed pipelines). " +
 			"Use this to troubleshoot build failures and understand what happened during pipeline execution. " +
-			fmt.Sprintf("Note: Returns up to %d log entries per request with a %d second timeout. ", MaxLogEntries, int(MaxLogStreamDuration.Seconds())) +
+			fmt.Sprintf("Note: Returns up to %d log entries per request with a %d second timeout by default. ", MaxLogEntries, int(MaxLogStreamDuration.Seconds())) +
 			"For large log files, use 'max_entries' and 'skip_entries' parameters for pagination. " +
 			"If limits are reached, partial results are returned with a message indicating more logs are available.",
 		InputSchema: mcp.ToolInputSchema{
@@ -81,6 +86,7 @@ func CreateGetPipelineBuildLogsTool() mcp.Tool {
 			},
 			Required: []string{"pipeline_id"},
 		},
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
 	}
 }
 
@@ -88,27 +94,25 @@ func CreateGetPipelineBuildLogsTool() mcp.Tool {
 func HandleGetPipelineBuildLogs(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
 	// Add panic recovery to prevent SSE server crashes
 	defer func() {
 		if r := recover(); r != nil {
-			log.Printf("Recovered from panic in get_pipeline_build_logs: %v", r)
+			slog.ErrorContext(ctx, "Recovered from panic in get_pipeline_build_logs", "panic", r)
 			// Don't return error - panic has already occurred, just log it
 		}
 	}()
 
-	log.Printf("Tool invoked: get_pipeline_build_logs")
+	slog.DebugContext(ctx, "Tool invoked")
 
 	// Extract pipeline_id from arguments
 	pipelineID, ok := arguments["pipeline_id"].(string)
 	if !ok || pipelineID == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "pipeline_id is required",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	// Extract optional max_entries parameter
@@ -118,7 +122,7 @@ func HandleGetPipelineBuildLogs(
 		if requestedMax > 0 && requestedMax <= MaxLogEntries {
 			maxEntries = requestedMax
 		} else if requestedMax > MaxLogEntries {
-			log.Printf("Requested max_entries %d exceeds limit %d, using limit", requestedMax, MaxLogEntries)
+			slog.DebugContext(ctx, "Requested max_entries exceeds limit, using limit", "requested", requestedMax, "limit", MaxLogEntries)
 			maxEntries = MaxLogEntries
 		}
 	}
@@ -132,35 +136,31 @@ func HandleGetPipelineBuildLogs(
 		}
 	}
 
-	log.Printf("Pipeline logs request: pipeline=%s, max_entries=%d, skip_entries=%d",
-		pipelineID, maxEntries, skipEntries)
+	slog.DebugContext(ctx, "Pipeline logs request",
+		"pipeline_id", pipelineID,
+		"max_entries", maxEntries,
+		"skip_entries", skipEntries,
+	)
 
 	// Create gRPC client
-	client, err := clients.NewPipelineClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
+	client, err := factory.PipelineClient(ctx)
 	if err != nil {
-		client, err = clients.NewPipelineClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			errResp := errors.ErrorResponse{
-				Error:   "CLIENT_ERROR",
-				Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
-		}
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeClientError,
+			Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
+		}), nil
 	}
 	defer client.Close()
 
 	// Create timeout context for streaming
-	streamCtx, cancel := context.WithTimeout(ctx, MaxLogStreamDuration)
+	streamDuration := logStreamDuration(ctx)
+	streamCtx, cancel := context.WithTimeout(ctx, streamDuration)
 	defer cancel()
 
 	// Start log stream with timeout context
 	stream, err := client.GetLogStream(streamCtx, pipelineID)
 	if err != nil {
-		return errors.HandleGRPCError(err, pipelineID), nil
+		return errors.HandleGRPCError(ctx, err, errors.Resource{PipelineID: pipelineID}), nil
 	}
 
 	// Track streaming state
@@ -185,23 +185,36 @@ func HandleGetPipelineBuildLogs(
 			select {
 			case <-ctx.Done():
 				contextCancelled = true
-				log.Printf("Context cancelled at checkpoint: pipeline=%s, entries=%d, processed=%d",
-					pipelineID, len(logEntries), totalProcessed)
+				slog.DebugContext(ctx, "Context cancelled at checkpoint",
+					"pipeline_id", pipelineID,
+					"entries", len(logEntries),
+					"processed", totalProcessed,
+				)
 				break
 			case <-streamCtx.Done():
 				timeoutReached = true
-				log.Printf("Stream timeout at checkpoint: pipeline=%s, entries=%d, processed=%d",
-					pipelineID, len(logEntries), totalProcessed)
+				slog.DebugContext(ctx, "Stream timeout at checkpoint",
+					"pipeline_id", pipelineID,
+					"entries", len(logEntries),
+					"processed", totalProcessed,
+				)
 				break
 			default:
 				// Continue streaming but check for early return conditions
-				log.Printf("Checkpoint: pipeline=%s, entries=%d, processed=%d, elapsed=%v",
-					pipelineID, len(logEntries), totalProcessed, elapsed)
+				slog.DebugContext(ctx, "Checkpoint",
+					"pipeline_id", pipelineID,
+					"entries", len(logEntries),
+					"processed", totalProcessed,
+					"elapsed", elapsed,
+				)
 
 				// Smart early return: if we have reasonable data and approaching timeout, return early
-				if len(logEntries) >= EarlyReturnThreshold && elapsed >= time.Duration(float64(MaxLogStreamDuration)*EarlyReturnTimeRatio) {
-					log.Printf("Early return triggered: pipeline=%s, entries=%d, elapsed=%v (threshold reached)",
-						pipelineID, len(logEntries), elapsed)
+				if len(logEntries) >= EarlyReturnThreshold && elapsed >= time.Duration(float64(streamDuration)*EarlyReturnTimeRatio) {
+					slog.DebugContext(ctx, "Early return triggered (threshold reached)",
+						"pipeline_id", pipelineID,
+						"entries", len(logEntries),
+						"elapsed", elapsed,
+					)
 					limitReached = true
 					hasMore = true
 					break
@@ -214,48 +227,51 @@ func HandleGetPipelineBuildLogs(
 		logEntry, err := stream.Recv()
 		if err == io.EOF {
 			// Stream completed successfully
-			log.Printf("Stream completed: pipeline=%s, entries=%d, duration=%v",
-				pipelineID, len(logEntries), time.Since(startTime))
+			slog.DebugContext(ctx, "Stream completed",
+				"pipeline_id", pipelineID,
+				"entries", len(logEntries),
+				"duration", time.Since(startTime),
+			)
 			break
 		}
 		if err != nil {
 			// Check if this is a timeout error
 			if streamCtx.Err() == context.DeadlineExceeded {
 				timeoutReached = true
-				log.Printf("Log stream timeout reached for pipeline: %s, duration: %v, entries collected: %d, skipped: %d",
-					pipelineID, time.Since(startTime), len(logEntries), entriesSkipped)
+				slog.InfoContext(ctx, "Log stream timeout reached",
+					"pipeline_id", pipelineID,
+					"duration", time.Since(startTime),
+					"entries", len(logEntries),
+					"skipped", entriesSkipped,
+				)
 				break
 			}
 			// Check for context cancellation
 			if ctx.Err() != nil {
 				contextCancelled = true
-				log.Printf("Context cancelled during stream receive: %v, entries: %d", ctx.Err(), len(logEntries))
+				slog.DebugContext(ctx, "Context cancelled during stream receive", "error", ctx.Err(), "entries", len(logEntries))
 				break
 			}
 			// Other stream error - provide actionable guidance
-			log.Printf("Stream error for pipeline %s: %v, entries collected: %d", pipelineID, err, len(logEntries))
+			slog.WarnContext(ctx, "Stream error", "pipeline_id", pipelineID, "error", err, "entries", len(logEntries))
 
 			// If we have partial results, return them with guidance
 			if len(logEntries) > 0 {
-				errResp := errors.ErrorResponse{
-					Error: "STREAM_ERROR",
+				return errors.ToolError(errors.ErrorResponse{
+					Error: errors.CodeStreamError,
 					Message: fmt.Sprintf("Stream interrupted after receiving %d entries: %v. "+
 						"This may be a temporary network issue. Try again or use skip_entries=%d to continue.",
 						len(logEntries), err, skipEntries+len(logEntries)),
-				}
-				errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-				return mcp.NewToolResultText(string(errJSON)), nil
+				}), nil
 			}
 
 			// No partial results - provide retry guidance
-			errResp := errors.ErrorResponse{
-				Error: "STREAM_ERROR",
+			return errors.ToolError(errors.ErrorResponse{
+				Error: errors.CodeStreamError,
 				Message: fmt.Sprintf("Failed to retrieve logs: %v. "+
 					"This may be a temporary network issue or the pipeline may not exist. "+
 					"Verify the pipeline ID and try again.", err),
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
+			}), nil
 		}
 
 		totalProcessed++
@@ -282,14 +298,17 @@ func HandleGetPipelineBuildLogs(
 		if err != io.EOF && streamCtx.Err() != context.DeadlineExceeded {
 			hasMore = true
 		}
-		log.Printf("Log entry limit reached for pipeline: %s, limit: %d, has_more: %v",
-			pipelineID, maxEntries, hasMore)
+		slog.DebugContext(ctx, "Log entry limit reached",
+			"pipeline_id", pipelineID,
+			"limit", maxEntries,
+			"has_more", hasMore,
+		)
 	}
 
 	// Check context before building response to prevent SSE crashes
 	if ctx.Err() != nil {
 		contextCancelled = true
-		log.Printf("Context cancelled before building response: %v, entries collected: %d", ctx.Err(), len(logEntries))
+		slog.DebugContext(ctx, "Context cancelled before building response", "error", ctx.Err(), "entries", len(logEntries))
 		// Still return partial results if we have any
 	}
 
@@ -318,8 +337,8 @@ func HandleGetPipelineBuildLogs(
 		response.Message = fmt.Sprintf(
 			"Log streaming timed out after %d seconds. Showing %d log entries (skipped %d). "+
 				"The pipeline may have produced more logs. Use skip_entries=%d to fetch the next page.",
-			int(MaxLogStreamDuration.Seconds()), len(logEntries), entriesSkipped, skipEntries+len(logEntries))
-	} else if limitReached && hasMore && duration >= time.Duration(float64(MaxLogStreamDuration)*EarlyReturnTimeRatio) {
+			int(streamDuration.Round(time.Second).Seconds()), len(logEntries), entriesSkipped, skipEntries+len(logEntries))
+	} else if limitReached && hasMore && duration >= time.Duration(float64(streamDuration)*EarlyReturnTimeRatio) {
 		response.Message = fmt.Sprintf(
 			"Retrieved %d log entries in %v (early return to prevent timeout). "+
 				"More logs are available. Use skip_entries=%d to fetch the next page.",
@@ -336,37 +355,54 @@ func HandleGetPipelineBuildLogs(
 	}
 
 	entriesPerSec := float64(len(logEntries)) / duration.Seconds()
-	log.Printf("Tool completed: get_pipeline_build_logs, pipeline: %s, entries: %d, duration: %v, rate: %.2f entries/sec, limited: %v, cancelled: %v",
-		pipelineID, len(logEntries), duration, entriesPerSec, limitReached || timeoutReached, contextCancelled)
+	slog.DebugContext(ctx, "Tool completed",
+		"pipeline_id", pipelineID,
+		"entries", len(logEntries),
+		"duration", duration,
+		"entries_per_sec", entriesPerSec,
+		"limited", limitReached || timeoutReached,
+		"cancelled", contextCancelled,
+	)
 
 	// Final context check before marshaling to prevent panic
 	if ctx.Err() != nil {
-		log.Printf("Context invalid before JSON marshal, returning partial results: %v", ctx.Err())
+		slog.DebugContext(ctx, "Context invalid before JSON marshal, returning partial results", "error", ctx.Err())
 		// Return simple response if context is dead to avoid potential panic
 		if len(logEntries) > 0 {
 			// Try to return something, even if abbreviated
 			simpleMsg := fmt.Sprintf("Partial results: %d log entries retrieved before connection lost", len(logEntries))
 			return mcp.NewToolResultText(simpleMsg), nil
 		}
-		errResp := errors.ErrorResponse{
-			Error:   "CONTEXT_CANCELLED",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeCancelled,
 			Message: "Request cancelled before completion",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	// Return formatted JSON response
 	resultJSON, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
-		log.Printf("Failed to marshal response: %v, entries count: %d", err, len(logEntries))
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		slog.ErrorContext(ctx, "Failed to marshal response", "error", err, "entries", len(logEntries))
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: fmt.Sprintf("Failed to marshal response: %v", err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
+	metrics.PipelineLogEntriesDelivered.Add(float64(len(logEntries)))
+
 	return mcp.NewToolResultText(string(resultJSON)), nil
 }
+
+// logStreamDuration returns how long logs are streamed for a tool call: until
+// LogResponseReserve before the call's deadline, or MaxLogStreamDuration when the
+// call has no deadline. A deadline of LogResponseReserve or less would leave no time
+// to stream, so at most a quarter of the time left is reserved.
+func logStreamDuration(ctx context.Context) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return MaxLogStreamDuration
+	}
+	remaining := time.Until(deadline)
+	return max(remaining-min(LogResponseReserve, remaining/4), 0)
+}
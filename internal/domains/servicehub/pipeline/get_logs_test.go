@@ -0,0 +1,35 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLogStreamDuration(t *testing.T) {
+	if got := logStreamDuration(context.Background()); got != MaxLogStreamDuration {
+		t.Errorf("without deadline: %v, want %v", got, MaxLogStreamDuration)
+	}
+
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		want    time.Duration
+	}{
+		{name: "default timeout", timeout: 50 * time.Second, want: 45 * time.Second},
+		{name: "timeout at the reserve", timeout: LogResponseReserve, want: LogResponseReserve * 3 / 4},
+		{name: "short timeout", timeout: 2 * time.Second, want: 1500 * time.Millisecond},
+		{name: "expired", timeout: -time.Second, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+
+			got := logStreamDuration(ctx)
+			if got > tt.want || got < tt.want-100*time.Millisecond {
+				t.Errorf("logStreamDuration() = %v, want about %v", got, tt.want)
+			}
+		})
+	}
+}
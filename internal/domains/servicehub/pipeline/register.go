@@ -2,55 +2,51 @@ package pipeline
 
 import (
 	"context"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/plantoncloud/mcp-server-planton/internal/common/auth"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 )
 
 // RegisterTools registers all pipeline tools with the MCP server.
-func RegisterTools(s *server.MCPServer, cfg *config.Config) {
-	registerGetPipelineByIdTool(s, cfg)
-	registerGetLatestPipelineByServiceIdTool(s, cfg)
-	registerGetPipelineBuildLogsTool(s, cfg)
+func RegisterTools(s *server.MCPServer, factory clientfactory.Factory) {
+	registerGetPipelineByIdTool(s, factory)
+	registerGetLatestPipelineByServiceIdTool(s, factory)
+	registerGetPipelineBuildLogsTool(s, factory)
 
-	log.Println("Registered 3 pipeline tools")
+	slog.Debug("Registered 3 pipeline tools")
 }
 
 // registerGetPipelineByIdTool registers the get_pipeline_by_id tool.
-func registerGetPipelineByIdTool(s *server.MCPServer, cfg *config.Config) {
+func registerGetPipelineByIdTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateGetPipelineByIdTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleGetPipelineById(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleGetPipelineById(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - get_pipeline_by_id")
+	slog.Debug("Registered tool", "tool", "get_pipeline_by_id")
 }
 
 // registerGetLatestPipelineByServiceIdTool registers the get_latest_pipeline_by_service_id tool.
-func registerGetLatestPipelineByServiceIdTool(s *server.MCPServer, cfg *config.Config) {
+func registerGetLatestPipelineByServiceIdTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateGetLatestPipelineByServiceIdTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleGetLatestPipelineByServiceId(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleGetLatestPipelineByServiceId(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - get_latest_pipeline_by_service_id")
+	slog.Debug("Registered tool", "tool", "get_latest_pipeline_by_service_id")
 }
 
 // registerGetPipelineBuildLogsTool registers the get_pipeline_build_logs tool.
-func registerGetPipelineBuildLogsTool(s *server.MCPServer, cfg *config.Config) {
+func registerGetPipelineBuildLogsTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateGetPipelineBuildLogsTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleGetPipelineBuildLogs(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleGetPipelineBuildLogs(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - get_pipeline_build_logs")
+	slog.Debug("Registered tool", "tool", "get_pipeline_build_logs")
 }
@@ -1,27 +1,27 @@
 package servicehub
 
 import (
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/domains/servicehub/pipeline"
 	"github.com/plantoncloud/mcp-server-planton/internal/domains/servicehub/service"
 	"github.com/plantoncloud/mcp-server-planton/internal/domains/servicehub/tektonpipeline"
 )
 
 // RegisterTools registers all Service Hub domain tools with the MCP server.
-func RegisterTools(s *server.MCPServer, cfg *config.Config) {
-	log.Println("Registering Service Hub tools...")
+func RegisterTools(s *server.MCPServer, factory clientfactory.Factory) {
+	slog.Debug("Registering Service Hub tools...")
 
 	// Register service tools
-	service.RegisterTools(s, cfg)
+	service.RegisterTools(s, factory)
 
 	// Register pipeline tools
-	pipeline.RegisterTools(s, cfg)
+	pipeline.RegisterTools(s, factory)
 
 	// Register Tekton pipeline tools
-	tektonpipeline.RegisterTools(s, cfg)
+	tektonpipeline.RegisterTools(s, factory)
 
-	log.Println("Service Hub tools registration complete")
+	slog.Debug("Service Hub tools registration complete")
 }
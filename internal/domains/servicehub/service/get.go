@@ -4,12 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
-	"github.com/plantoncloud/mcp-server-planton/internal/domains/servicehub/clients"
 )
 
 // CreateGetServiceByIdTool creates the MCP tool definition for getting service by ID.
@@ -28,6 +27,7 @@ func CreateGetServiceByIdTool() mcp.Tool {
 			},
 			Required: []string{"service_id"},
 		},
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
 	}
 }
 
@@ -35,43 +35,33 @@ func CreateGetServiceByIdTool() mcp.Tool {
 func HandleGetServiceById(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
-	log.Printf("Tool invoked: get_service_by_id")
+	slog.DebugContext(ctx, "Tool invoked")
 
 	// Extract service_id from arguments
 	serviceID, ok := arguments["service_id"].(string)
 	if !ok || serviceID == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "service_id is required",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	// Create gRPC client
-	client, err := clients.NewServiceClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
+	client, err := factory.ServiceClient(ctx)
 	if err != nil {
-		client, err = clients.NewServiceClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			errResp := errors.ErrorResponse{
-				Error:   "CLIENT_ERROR",
-				Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
-		}
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeClientError,
+			Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
+		}), nil
 	}
 	defer client.Close()
 
 	// Query service
 	svc, err := client.GetById(ctx, serviceID)
 	if err != nil {
-		return errors.HandleGRPCError(err, serviceID), nil
+		return errors.HandleGRPCError(ctx, err, errors.Resource{ResourceID: serviceID}), nil
 	}
 
 	// Convert to simple struct
@@ -105,17 +95,15 @@ func HandleGetServiceById(
 		}
 	}
 
-	log.Printf("Tool completed: get_service_by_id, service: %s", serviceID)
+	slog.DebugContext(ctx, "Tool completed", "service_id", serviceID)
 
 	// Return formatted JSON response
 	resultJSON, err := json.MarshalIndent(serviceSimple, "", "  ")
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: fmt.Sprintf("Failed to marshal response: %v", err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	return mcp.NewToolResultText(string(resultJSON)), nil
@@ -141,6 +129,7 @@ func CreateGetServiceByOrgBySlugTool() mcp.Tool {
 			},
 			Required: []string{"org_id", "slug"},
 		},
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
 	}
 }
 
@@ -148,54 +137,42 @@ func CreateGetServiceByOrgBySlugTool() mcp.Tool {
 func HandleGetServiceByOrgBySlug(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
-	log.Printf("Tool invoked: get_service_by_org_by_slug")
+	slog.DebugContext(ctx, "Tool invoked")
 
 	// Extract arguments
 	orgID, okOrg := arguments["org_id"].(string)
 	slug, okSlug := arguments["slug"].(string)
 
 	if !okOrg || orgID == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "org_id is required",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	if !okSlug || slug == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "slug is required",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	// Create gRPC client
-	client, err := clients.NewServiceClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
+	client, err := factory.ServiceClient(ctx)
 	if err != nil {
-		client, err = clients.NewServiceClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			errResp := errors.ErrorResponse{
-				Error:   "CLIENT_ERROR",
-				Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
-		}
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeClientError,
+			Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
+		}), nil
 	}
 	defer client.Close()
 
 	// Query service
 	svc, err := client.GetByOrgBySlug(ctx, orgID, slug)
 	if err != nil {
-		return errors.HandleGRPCError(err, fmt.Sprintf("%s/%s", orgID, slug)), nil
+		return errors.HandleGRPCError(ctx, err, errors.Resource{OrgID: orgID, Name: slug}), nil
 	}
 
 	// Convert to simple struct
@@ -229,17 +206,15 @@ func HandleGetServiceByOrgBySlug(
 		}
 	}
 
-	log.Printf("Tool completed: get_service_by_org_by_slug, service: %s/%s", orgID, slug)
+	slog.DebugContext(ctx, "Tool completed", "org_id", orgID, "slug", slug)
 
 	// Return formatted JSON response
 	resultJSON, err := json.MarshalIndent(serviceSimple, "", "  ")
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: fmt.Sprintf("Failed to marshal response: %v", err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	return mcp.NewToolResultText(string(resultJSON)), nil
@@ -4,15 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/commons/apiresource"
 	"buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/commons/apiresource/apiresourcekind"
 	"buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/commons/rpc"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
-	"github.com/plantoncloud/mcp-server-planton/internal/domains/servicehub/clients"
 )
 
 // ServiceSimple is a simplified representation of a Service for JSON serialization.
@@ -62,6 +61,7 @@ func CreateListServicesForOrgTool() mcp.Tool {
 			},
 			Required: []string{"org_id"},
 		},
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
 	}
 }
 
@@ -75,39 +75,26 @@ func CreateListServicesForOrgTool() mcp.Tool {
 func HandleListServicesForOrg(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
-	log.Printf("Tool invoked: list_services_for_org")
+	slog.DebugContext(ctx, "Tool invoked")
 
 	// Extract org_id from arguments
 	orgID, ok := arguments["org_id"].(string)
 	if !ok || orgID == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "org_id is required",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
-	// Create gRPC client with per-user API key from context
-	// For HTTP transport: API key extracted from Authorization header
-	// For STDIO transport: API key from environment variable (fallback to config)
-	client, err := clients.NewServiceClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
+	// Create gRPC client with the caller's credentials (see auth.CredentialPolicy)
+	client, err := factory.ServiceClient(ctx)
 	if err != nil {
-		// Fallback to config API key for STDIO mode
-		client, err = clients.NewServiceClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			errResp := errors.ErrorResponse{
-				Error:   "CLIENT_ERROR",
-				Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
-		}
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeClientError,
+			Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
+		}), nil
 	}
 	defer client.Close()
 
@@ -124,7 +111,7 @@ func HandleListServicesForOrg(
 	// Query services
 	serviceList, err := client.Find(ctx, findRequest)
 	if err != nil {
-		return errors.HandleGRPCError(err, ""), nil
+		return errors.HandleGRPCError(ctx, err, errors.Resource{OrgID: orgID}), nil
 	}
 
 	// Convert protobuf objects to JSON-serializable structs
@@ -163,21 +150,18 @@ func HandleListServicesForOrg(
 		services = append(services, serviceSimple)
 	}
 
-	log.Printf(
-		"Tool completed: list_services_for_org, returned %d services for org %s",
-		len(services),
-		orgID,
+	slog.DebugContext(ctx, "Tool completed",
+		"org_id", orgID,
+		"count", len(services),
 	)
 
 	// Return formatted JSON response
 	resultJSON, err := json.MarshalIndent(services, "", "  ")
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: fmt.Sprintf("Failed to marshal response: %v", err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	return mcp.NewToolResultText(string(resultJSON)), nil
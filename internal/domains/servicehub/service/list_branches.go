@@ -4,12 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
-	"github.com/plantoncloud/mcp-server-planton/internal/domains/servicehub/clients"
 )
 
 // BranchList represents a list of Git branches.
@@ -33,6 +32,7 @@ func CreateListServiceBranchesTool() mcp.Tool {
 			},
 			Required: []string{"service_id"},
 		},
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
 	}
 }
 
@@ -40,43 +40,33 @@ func CreateListServiceBranchesTool() mcp.Tool {
 func HandleListServiceBranches(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
-	log.Printf("Tool invoked: list_service_branches")
+	slog.DebugContext(ctx, "Tool invoked")
 
 	// Extract service_id from arguments
 	serviceID, ok := arguments["service_id"].(string)
 	if !ok || serviceID == "" {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "service_id is required",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	// Create gRPC client
-	client, err := clients.NewServiceClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
+	client, err := factory.ServiceClient(ctx)
 	if err != nil {
-		client, err = clients.NewServiceClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			errResp := errors.ErrorResponse{
-				Error:   "CLIENT_ERROR",
-				Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
-		}
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeClientError,
+			Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
+		}), nil
 	}
 	defer client.Close()
 
 	// List branches
 	branchList, err := client.ListBranches(ctx, serviceID)
 	if err != nil {
-		return errors.HandleGRPCError(err, serviceID), nil
+		return errors.HandleGRPCError(ctx, err, errors.Resource{ResourceID: serviceID}), nil
 	}
 
 	// Convert to simple struct
@@ -84,21 +74,18 @@ func HandleListServiceBranches(
 		Branches: branchList.GetEntries(),
 	}
 
-	log.Printf(
-		"Tool completed: list_service_branches, returned %d branches for service %s",
-		len(result.Branches),
-		serviceID,
+	slog.DebugContext(ctx, "Tool completed",
+		"service_id", serviceID,
+		"count", len(result.Branches),
 	)
 
 	// Return formatted JSON response
 	resultJSON, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: fmt.Sprintf("Failed to marshal response: %v", err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	return mcp.NewToolResultText(string(resultJSON)), nil
@@ -2,68 +2,63 @@ package service
 
 import (
 	"context"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/plantoncloud/mcp-server-planton/internal/common/auth"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 )
 
 // RegisterTools registers all service tools with the MCP server.
-func RegisterTools(s *server.MCPServer, cfg *config.Config) {
-	registerListServicesForOrgTool(s, cfg)
-	registerGetServiceByIdTool(s, cfg)
-	registerGetServiceByOrgBySlugTool(s, cfg)
-	registerListServiceBranchesTool(s, cfg)
+func RegisterTools(s *server.MCPServer, factory clientfactory.Factory) {
+	registerListServicesForOrgTool(s, factory)
+	registerGetServiceByIdTool(s, factory)
+	registerGetServiceByOrgBySlugTool(s, factory)
+	registerListServiceBranchesTool(s, factory)
 
-	log.Println("Registered 4 service tools")
+	slog.Debug("Registered 4 service tools")
 }
 
 // registerListServicesForOrgTool registers the list_services_for_org tool.
-func registerListServicesForOrgTool(s *server.MCPServer, cfg *config.Config) {
+func registerListServicesForOrgTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateListServicesForOrgTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleListServicesForOrg(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleListServicesForOrg(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - list_services_for_org")
+	slog.Debug("Registered tool", "tool", "list_services_for_org")
 }
 
 // registerGetServiceByIdTool registers the get_service_by_id tool.
-func registerGetServiceByIdTool(s *server.MCPServer, cfg *config.Config) {
+func registerGetServiceByIdTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateGetServiceByIdTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleGetServiceById(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleGetServiceById(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - get_service_by_id")
+	slog.Debug("Registered tool", "tool", "get_service_by_id")
 }
 
 // registerGetServiceByOrgBySlugTool registers the get_service_by_org_by_slug tool.
-func registerGetServiceByOrgBySlugTool(s *server.MCPServer, cfg *config.Config) {
+func registerGetServiceByOrgBySlugTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateGetServiceByOrgBySlugTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleGetServiceByOrgBySlug(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleGetServiceByOrgBySlug(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - get_service_by_org_by_slug")
+	slog.Debug("Registered tool", "tool", "get_service_by_org_by_slug")
 }
 
 // registerListServiceBranchesTool registers the list_service_branches tool.
-func registerListServiceBranchesTool(s *server.MCPServer, cfg *config.Config) {
+func registerListServiceBranchesTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateListServiceBranchesTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleListServiceBranches(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleListServiceBranches(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - list_service_branches")
+	slog.Debug("Registered tool", "tool", "list_service_branches")
 }
@@ -4,13 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 
 	tektonpipelinev1 "buf.build/gen/go/blintora/apis/protocolbuffers/go/ai/planton/servicehub/tektonpipeline/v1"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/errors"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
-	"github.com/plantoncloud/mcp-server-planton/internal/domains/servicehub/clients"
 )
 
 // TektonPipelineDetails contains detailed pipeline information including YAML content.
@@ -47,6 +46,7 @@ func CreateGetTektonPipelineTool() mcp.Tool {
 				},
 			},
 		},
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
 	}
 }
 
@@ -54,9 +54,9 @@ func CreateGetTektonPipelineTool() mcp.Tool {
 func HandleGetTektonPipeline(
 	ctx context.Context,
 	arguments map[string]interface{},
-	cfg *config.Config,
+	factory clientfactory.Factory,
 ) (*mcp.CallToolResult, error) {
-	log.Printf("Tool invoked: get_tekton_pipeline")
+	slog.DebugContext(ctx, "Tool invoked")
 
 	// Extract arguments
 	pipelineID, hasPipelineID := arguments["pipeline_id"].(string)
@@ -65,29 +65,19 @@ func HandleGetTektonPipeline(
 
 	// Validate arguments - need either pipeline_id OR (org_id + name)
 	if !hasPipelineID && (!hasOrgID || !hasName) {
-		errResp := errors.ErrorResponse{
-			Error:   "INVALID_ARGUMENT",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInvalidArgument,
 			Message: "Provide either pipeline_id OR both org_id and name",
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	// Create gRPC client
-	client, err := clients.NewTektonPipelineClientFromContext(ctx, cfg.PlantonAPIsGRPCEndpoint)
+	client, err := factory.TektonPipelineClient(ctx)
 	if err != nil {
-		client, err = clients.NewTektonPipelineClient(
-			cfg.PlantonAPIsGRPCEndpoint,
-			cfg.PlantonAPIKey,
-		)
-		if err != nil {
-			errResp := errors.ErrorResponse{
-				Error:   "CLIENT_ERROR",
-				Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
-			}
-			errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-			return mcp.NewToolResultText(string(errJSON)), nil
-		}
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeClientError,
+			Message: fmt.Sprintf("Failed to create gRPC client: %v", err),
+		}), nil
 	}
 	defer client.Close()
 
@@ -97,15 +87,15 @@ func HandleGetTektonPipeline(
 	if hasPipelineID && pipelineID != "" {
 		pipeline, err = client.GetById(ctx, pipelineID)
 		if err != nil {
-			return errors.HandleGRPCError(err, pipelineID), nil
+			return errors.HandleGRPCError(ctx, err, errors.Resource{PipelineID: pipelineID}), nil
 		}
-		log.Printf("Retrieved pipeline by ID: %s", pipelineID)
+		slog.DebugContext(ctx, "Retrieved pipeline by ID", "pipeline_id", pipelineID)
 	} else {
 		pipeline, err = client.GetByOrgAndName(ctx, orgID, name)
 		if err != nil {
-			return errors.HandleGRPCError(err, fmt.Sprintf("%s/%s", orgID, name)), nil
+			return errors.HandleGRPCError(ctx, err, errors.Resource{OrgID: orgID, Name: name}), nil
 		}
-		log.Printf("Retrieved pipeline by org/name: %s/%s", orgID, name)
+		slog.DebugContext(ctx, "Retrieved pipeline by org and name", "org_id", orgID, "name", name)
 	}
 
 	// Convert to detailed struct with YAML content
@@ -118,17 +108,15 @@ func HandleGetTektonPipeline(
 		YAMLContent: pipeline.GetSpec().GetYamlContent(),
 	}
 
-	log.Printf("Tool completed: get_tekton_pipeline")
+	slog.DebugContext(ctx, "Tool completed")
 
 	// Return formatted JSON response
 	resultJSON, err := json.MarshalIndent(pipelineDetails, "", "  ")
 	if err != nil {
-		errResp := errors.ErrorResponse{
-			Error:   "INTERNAL_ERROR",
+		return errors.ToolError(errors.ErrorResponse{
+			Error:   errors.CodeInternal,
 			Message: fmt.Sprintf("Failed to marshal response: %v", err),
-		}
-		errJSON, _ := json.MarshalIndent(errResp, "", "  ")
-		return mcp.NewToolResultText(string(errJSON)), nil
+		}), nil
 	}
 
 	return mcp.NewToolResultText(string(resultJSON)), nil
@@ -2,29 +2,27 @@ package tektonpipeline
 
 import (
 	"context"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
-	"github.com/plantoncloud/mcp-server-planton/internal/common/auth"
-	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
 )
 
 // RegisterTools registers all Tekton pipeline tools with the MCP server.
-func RegisterTools(s *server.MCPServer, cfg *config.Config) {
-	registerGetTektonPipelineTool(s, cfg)
+func RegisterTools(s *server.MCPServer, factory clientfactory.Factory) {
+	registerGetTektonPipelineTool(s, factory)
 
-	log.Println("Registered 1 Tekton pipeline tool")
+	slog.Debug("Registered 1 Tekton pipeline tool")
 }
 
 // registerGetTektonPipelineTool registers the get_tekton_pipeline tool.
-func registerGetTektonPipelineTool(s *server.MCPServer, cfg *config.Config) {
+func registerGetTektonPipelineTool(s *server.MCPServer, factory clientfactory.Factory) {
 	s.AddTool(
 		CreateGetTektonPipelineTool(),
-		func(arguments map[string]interface{}) (*mcp.CallToolResult, error) {
-			ctx := auth.GetContextWithAPIKey(context.Background())
-			return HandleGetTektonPipeline(ctx, arguments, cfg)
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return HandleGetTektonPipeline(ctx, request.GetArguments(), factory)
 		},
 	)
-	log.Println("  - get_tekton_pipeline")
+	slog.Debug("Registered tool", "tool", "get_tekton_pipeline")
 }
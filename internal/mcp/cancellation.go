@@ -0,0 +1,228 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	commonerrors "github.com/plantoncloud/mcp-server-planton/internal/common/errors"
+)
+
+// cancelledNotificationMethod is the notification a client sends to cancel a
+// request it issued earlier.
+const cancelledNotificationMethod = "notifications/cancelled"
+
+// requestIDMetaKey is the _meta key under which the OnBeforeCallTool hook hands the
+// JSON-RPC request ID of a tool call to the tool handler middleware, which does not
+// receive it otherwise.
+const requestIDMetaKey = "ai.planton.mcp/request-id"
+
+// Causes of a cancelled tool call context.
+var (
+	errToolTimeout       = errors.New("tool call timed out")
+	errCancelledByClient = errors.New("tool call cancelled by the client")
+	errSessionClosed     = errors.New("client session closed")
+)
+
+// callKey identifies a tool call: JSON-RPC request IDs are only unique per session.
+type callKey struct {
+	sessionID string
+	requestID string
+}
+
+// trackedCall is the cancel function of an in-flight tool call.
+type trackedCall struct {
+	cancel context.CancelCauseFunc
+}
+
+// callCanceller gives every tool call its own context, which is cancelled when:
+//   - the tool's timeout expires (ToolTimeout or its per-tool override)
+//   - the client sends notifications/cancelled for the call
+//   - the client's session closes, e.g. when the SSE stream disconnects
+//
+// Handlers pass the context to their gRPC calls, so cancelling it stops in-flight
+// Planton API calls and log streams. Transports cannot cancel tool calls on their
+// own: the SSE transport detaches them from the HTTP request and the STDIO transport
+// runs them on the server's context.
+type callCanceller struct {
+	defaultTimeout time.Duration
+	timeouts       map[string]time.Duration
+
+	mu    sync.Mutex
+	calls map[callKey]*trackedCall
+}
+
+// newCallCanceller creates a callCanceller.
+//
+// Args:
+//   - defaultTimeout: Deadline of tools without a per-tool timeout; zero disables it
+//   - timeouts: Per-tool timeouts keyed by tool name; zero disables the tool's deadline
+func newCallCanceller(defaultTimeout time.Duration, timeouts map[string]time.Duration) *callCanceller {
+	return &callCanceller{
+		defaultTimeout: defaultTimeout,
+		timeouts:       timeouts,
+		calls:          make(map[callKey]*trackedCall),
+	}
+}
+
+// timeout returns the deadline of a tool; zero means none.
+func (c *callCanceller) timeout(tool string) time.Duration {
+	if timeout, ok := c.timeouts[tool]; ok {
+		return timeout
+	}
+	return c.defaultTimeout
+}
+
+// registerHooks installs the hooks the canceller needs to correlate tool calls with
+// cancellations and session ends.
+func (c *callCanceller) registerHooks(hooks *server.Hooks) {
+	hooks.AddBeforeCallTool(c.stampRequestID)
+	hooks.AddOnUnregisterSession(c.cancelSession)
+}
+
+// stampRequestID records the JSON-RPC request ID of a tool call in its _meta, where
+// the middleware picks it up.
+func (c *callCanceller) stampRequestID(_ context.Context, id any, request *mcp.CallToolRequest) {
+	if id == nil {
+		return
+	}
+	if request.Params.Meta == nil {
+		request.Params.Meta = &mcp.Meta{}
+	}
+	if request.Params.Meta.AdditionalFields == nil {
+		request.Params.Meta.AdditionalFields = make(map[string]any)
+	}
+	request.Params.Meta.AdditionalFields[requestIDMetaKey] = mcp.NewRequestId(id).String()
+}
+
+// middleware returns a tool handler middleware that runs every tool call on a
+// context with the tool's deadline, which the canceller can cancel on behalf of
+// the client.
+//
+// A call stopped by its timeout or by the client returns a TIMEOUT or CANCELLED
+// tool error instead of whatever the handler returned after its context ended.
+func (c *callCanceller) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, cancel := context.WithCancelCause(ctx)
+		defer cancel(nil)
+
+		if key, ok := callKeyFor(ctx, request); ok {
+			call := c.track(key, cancel)
+			defer c.untrack(key, call)
+		}
+
+		timeout := c.timeout(request.Params.Name)
+		if timeout > 0 {
+			var stop context.CancelFunc
+			ctx, stop = context.WithTimeoutCause(ctx, timeout, errToolTimeout)
+			defer stop()
+		}
+
+		result, err := next(ctx, request)
+		if ctx.Err() == nil {
+			return result, err
+		}
+
+		switch cause := context.Cause(ctx); {
+		case errors.Is(cause, errToolTimeout):
+			slog.WarnContext(ctx, "Tool call timed out", "timeout", timeout)
+			return timeoutResult(timeout), nil
+		case errors.Is(cause, errCancelledByClient), errors.Is(cause, errSessionClosed):
+			slog.InfoContext(ctx, "Tool call cancelled", "reason", cause.Error())
+			return cancelledResult(cause), nil
+		}
+		return result, err
+	}
+}
+
+// track registers the cancel function of an in-flight call. A later call reusing
+// the same request ID replaces the earlier one.
+func (c *callCanceller) track(key callKey, cancel context.CancelCauseFunc) *trackedCall {
+	call := &trackedCall{cancel: cancel}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls[key] = call
+	return call
+}
+
+// untrack removes a finished call unless a later call has replaced it.
+func (c *callCanceller) untrack(key callKey, call *trackedCall) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.calls[key] == call {
+		delete(c.calls, key)
+	}
+}
+
+// handleCancelled cancels the tool call named by a notifications/cancelled
+// notification. Notifications for unknown or finished calls are ignored, as the
+// protocol allows them to arrive after the call has completed.
+func (c *callCanceller) handleCancelled(ctx context.Context, notification mcp.JSONRPCNotification) {
+	session := server.ClientSessionFromContext(ctx)
+	requestID, ok := notification.Params.AdditionalFields["requestId"]
+	if session == nil || !ok || requestID == nil {
+		return
+	}
+	key := callKey{sessionID: session.SessionID(), requestID: mcp.NewRequestId(requestID).String()}
+
+	c.mu.Lock()
+	call, ok := c.calls[key]
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	reason, _ := notification.Params.AdditionalFields["reason"].(string)
+	slog.InfoContext(ctx, "Client cancelled tool call", "request_id", key.requestID, "reason", reason)
+	call.cancel(errCancelledByClient)
+}
+
+// cancelSession cancels every in-flight tool call of a session that closed.
+func (c *callCanceller) cancelSession(_ context.Context, session server.ClientSession) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, call := range c.calls {
+		if key.sessionID == session.SessionID() {
+			call.cancel(errSessionClosed)
+		}
+	}
+}
+
+// callKeyFor returns the key of a tool call. Calls without a session ID (stateless
+// requests) cannot be cancelled by notification and are not tracked.
+func callKeyFor(ctx context.Context, request mcp.CallToolRequest) (callKey, bool) {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil || session.SessionID() == "" || request.Params.Meta == nil {
+		return callKey{}, false
+	}
+	requestID, ok := request.Params.Meta.AdditionalFields[requestIDMetaKey].(string)
+	if !ok {
+		return callKey{}, false
+	}
+	return callKey{sessionID: session.SessionID(), requestID: requestID}, true
+}
+
+// timeoutResult builds the tool error returned for a call stopped by its deadline.
+func timeoutResult(timeout time.Duration) *mcp.CallToolResult {
+	return commonerrors.ToolError(commonerrors.ErrorResponse{
+		Error: commonerrors.CodeTimeout,
+		Message: fmt.Sprintf(
+			"The tool call did not finish within %s and was stopped. "+
+				"Narrow the request or retry later.",
+			timeout,
+		),
+	})
+}
+
+// cancelledResult builds the tool error returned for a call the client gave up on.
+func cancelledResult(cause error) *mcp.CallToolResult {
+	return commonerrors.ToolError(commonerrors.ErrorResponse{
+		Error:   commonerrors.CodeCancelled,
+		Message: fmt.Sprintf("The tool call was stopped: %v.", cause),
+	})
+}
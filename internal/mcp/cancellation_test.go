@@ -0,0 +1,134 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// fakeSession is a client session identified only by its ID.
+type fakeSession struct {
+	id string
+}
+
+func (s *fakeSession) Initialize()       {}
+func (s *fakeSession) Initialized() bool { return true }
+func (s *fakeSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return make(chan mcp.JSONRPCNotification, 1)
+}
+func (s *fakeSession) SessionID() string { return s.id }
+
+// newBlockingServer returns an MCP server with a "wait" tool that blocks until its
+// context ends, and a channel receiving a value whenever the tool starts.
+func newBlockingServer(calls *callCanceller) (*server.MCPServer, chan struct{}) {
+	hooks := &server.Hooks{}
+	calls.registerHooks(hooks)
+	mcpServer := server.NewMCPServer("planton-cloud-test", "0.0.0",
+		server.WithToolHandlerMiddleware(calls.middleware),
+		server.WithHooks(hooks),
+	)
+	mcpServer.AddNotificationHandler(cancelledNotificationMethod, calls.handleCancelled)
+
+	started := make(chan struct{}, 1)
+	mcpServer.AddTool(mcp.NewTool("wait"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		started <- struct{}{}
+		<-ctx.Done()
+		return mcp.NewToolResultText("context ended"), nil
+	})
+	return mcpServer, started
+}
+
+// callWait calls the "wait" tool with the given request ID in the background and
+// returns a channel receiving the code of the tool error it ends with.
+func callWait(t *testing.T, mcpServer *server.MCPServer, ctx context.Context, requestID int) <-chan string {
+	t.Helper()
+	codes := make(chan string, 1)
+	go func() {
+		message := fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"wait"}}`, requestID)
+		response, ok := mcpServer.HandleMessage(ctx, json.RawMessage(message)).(mcp.JSONRPCResponse)
+		if !ok {
+			codes <- "no response"
+			return
+		}
+		result := response.Result.(mcp.CallToolResult)
+		var errResp struct {
+			Error string `json:"error"`
+		}
+		_ = json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &errResp)
+		codes <- errResp.Error
+	}()
+	return codes
+}
+
+// sendCancelled sends notifications/cancelled for a request ID from a session.
+func sendCancelled(mcpServer *server.MCPServer, ctx context.Context, requestID int) {
+	message := fmt.Sprintf(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":%d,"reason":"user aborted"}}`, requestID)
+	mcpServer.HandleMessage(ctx, json.RawMessage(message))
+}
+
+// waitForCode returns the error code a tool call ended with, failing after a second.
+func waitForCode(t *testing.T, codes <-chan string) string {
+	t.Helper()
+	select {
+	case code := <-codes:
+		return code
+	case <-time.After(time.Second):
+		t.Fatal("tool call was not stopped")
+		return ""
+	}
+}
+
+func TestCallCancellerCancelledByClient(t *testing.T) {
+	mcpServer, started := newBlockingServer(newCallCanceller(0, nil))
+	ctx := mcpServer.WithContext(context.Background(), &fakeSession{id: "session-1"})
+	otherCtx := mcpServer.WithContext(context.Background(), &fakeSession{id: "session-2"})
+
+	codes := callWait(t, mcpServer, ctx, 7)
+	<-started
+
+	// Request IDs are per session: another session cannot cancel the call
+	sendCancelled(mcpServer, otherCtx, 7)
+	select {
+	case code := <-codes:
+		t.Fatalf("call stopped with %q by another session's cancellation", code)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	sendCancelled(mcpServer, ctx, 7)
+	if code := waitForCode(t, codes); code != "CANCELLED" {
+		t.Errorf("error = %q, want CANCELLED", code)
+	}
+}
+
+func TestCallCancellerTimeout(t *testing.T) {
+	calls := newCallCanceller(time.Hour, map[string]time.Duration{"wait": 10 * time.Millisecond})
+	mcpServer, started := newBlockingServer(calls)
+	ctx := mcpServer.WithContext(context.Background(), &fakeSession{id: "session-1"})
+
+	codes := callWait(t, mcpServer, ctx, 1)
+	<-started
+	if code := waitForCode(t, codes); code != "TIMEOUT" {
+		t.Errorf("error = %q, want TIMEOUT", code)
+	}
+}
+
+func TestCallCancellerSessionClosed(t *testing.T) {
+	mcpServer, started := newBlockingServer(newCallCanceller(0, nil))
+	session := &fakeSession{id: "session-1"}
+	if err := mcpServer.RegisterSession(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+	ctx := mcpServer.WithContext(context.Background(), session)
+
+	codes := callWait(t, mcpServer, ctx, 1)
+	<-started
+	mcpServer.UnregisterSession(context.Background(), session.SessionID())
+	if code := waitForCode(t, codes); code != "CANCELLED" {
+		t.Errorf("error = %q, want CANCELLED", code)
+	}
+}
@@ -0,0 +1,372 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
+	commonerrors "github.com/plantoncloud/mcp-server-planton/internal/common/errors"
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+)
+
+// Tool arguments filled from the context when a call omits them
+const (
+	orgIDArg   = "org_id"
+	envNameArg = "env_name"
+)
+
+// contextArgs are the context arguments in the order they are reported
+var contextArgs = []string{orgIDArg, envNameArg}
+
+// Sources of a context value
+const (
+	// sourceSession is a value set with set_context for the calling session
+	sourceSession = "session"
+	// sourceDefault is PLANTON_MCP_DEFAULT_ORG or PLANTON_MCP_DEFAULT_ENV
+	sourceDefault = "default"
+	// sourceUnset means neither the session nor the configuration has a value
+	sourceUnset = "unset"
+)
+
+// sessionContextTTL is how long an unused session context is kept. Not every
+// transport reports closed sessions, so contexts are also dropped when left idle.
+const sessionContextTTL = 24 * time.Hour
+
+// contextHint is appended to the description of arguments filled from the context
+const contextHint = " Defaults to the current context when omitted (see get_context)."
+
+// contextValue is a context argument and where its value comes from.
+type contextValue struct {
+	Value  string `json:"value,omitempty"`
+	Source string `json:"source"`
+}
+
+// sessionContext is the organization and environment a session set with set_context.
+type sessionContext struct {
+	org, env string
+	// used is when the session last set or read its context
+	used time.Time
+}
+
+// contextStore keeps the current organization and environment of each client
+// session and fills them into tool calls that omit them.
+//
+// A tool takes part when its input schema requires org_id or env_name. At
+// registration those arguments become optional, and a call that omits one gets the
+// session's value, set with set_context, or else the configured default. Tool results
+// then say which values were filled in, so the caller can tell which organization or
+// environment a call acted on. Optional arguments such as the env_names filter of
+// search_cloud_resources are never filled: omitting them has a meaning of its own.
+type contextStore struct {
+	defaults sessionContext
+	// params lists the context arguments of each tool; written only at registration
+	params map[string][]string
+	now    func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string]*sessionContext
+}
+
+// contextStoreFromConfig creates the context store of the configuration, whose
+// default organization and environment apply to every session.
+func contextStoreFromConfig(cfg *config.Config) *contextStore {
+	return &contextStore{
+		defaults: sessionContext{org: cfg.DefaultOrg, env: cfg.DefaultEnv},
+		params:   make(map[string][]string),
+		now:      time.Now,
+		sessions: make(map[string]*sessionContext),
+	}
+}
+
+// registerHooks installs the hook that drops the context of closed sessions.
+func (c *contextStore) registerHooks(hooks *server.Hooks) {
+	hooks.AddOnUnregisterSession(func(_ context.Context, session server.ClientSession) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.sessions, session.SessionID())
+	})
+}
+
+// current returns the context arguments of the calling session, keyed by argument.
+func (c *contextStore) current(ctx context.Context) map[string]contextValue {
+	var session sessionContext
+	if id := sessionID(ctx); id != "" {
+		c.mu.Lock()
+		if s, ok := c.sessions[id]; ok {
+			s.used = c.now()
+			session = *s
+		}
+		c.mu.Unlock()
+	}
+	return map[string]contextValue{
+		orgIDArg:   resolveContextValue(session.org, c.defaults.org),
+		envNameArg: resolveContextValue(session.env, c.defaults.env),
+	}
+}
+
+// set updates the context of a session and drops the contexts of idle sessions.
+// Only the arguments present in values change; an empty value clears the
+// session's value so the default applies again. Changing the organization also
+// clears the environment unless values sets it, since environments belong to an
+// organization.
+//
+// Args:
+//   - id: Session ID
+//   - values: New values keyed by context argument
+func (c *contextStore) set(id string, values map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for other, s := range c.sessions {
+		if now.Sub(s.used) > sessionContextTTL {
+			delete(c.sessions, other)
+		}
+	}
+
+	session, ok := c.sessions[id]
+	if !ok {
+		session = &sessionContext{}
+		c.sessions[id] = session
+	}
+	session.used = now
+	if org, ok := values[orgIDArg]; ok {
+		if _, setsEnv := values[envNameArg]; !setsEnv && org != session.org {
+			session.env = ""
+		}
+		session.org = org
+	}
+	if env, ok := values[envNameArg]; ok {
+		session.env = env
+	}
+}
+
+// adoptTools makes the required org_id and env_name arguments of the registered tools
+// optional and records them as the tool's context arguments. It runs once, after
+// every tool is registered and before any client connects.
+func (c *contextStore) adoptTools(s *server.MCPServer) {
+	for name, serverTool := range s.ListTools() {
+		tool, params := withContextArgs(serverTool.Tool)
+		if len(params) == 0 {
+			continue
+		}
+		c.params[name] = params
+		s.AddTool(tool, serverTool.Handler)
+	}
+}
+
+// withContextArgs returns a copy of a tool whose required string org_id and env_name
+// arguments are optional, and the names of those arguments.
+func withContextArgs(tool mcp.Tool) (mcp.Tool, []string) {
+	var params []string
+	for _, arg := range contextArgs {
+		property, ok := tool.InputSchema.Properties[arg].(map[string]interface{})
+		if ok && property["type"] == "string" && slices.Contains(tool.InputSchema.Required, arg) {
+			params = append(params, arg)
+		}
+	}
+	if len(params) == 0 {
+		return tool, nil
+	}
+
+	tool.InputSchema.Properties = maps.Clone(tool.InputSchema.Properties)
+	for _, arg := range params {
+		property := maps.Clone(tool.InputSchema.Properties[arg].(map[string]interface{}))
+		description, _ := property["description"].(string)
+		property["description"] = description + contextHint
+		tool.InputSchema.Properties[arg] = property
+	}
+	tool.InputSchema.Required = slices.DeleteFunc(slices.Clone(tool.InputSchema.Required), func(arg string) bool {
+		return slices.Contains(params, arg)
+	})
+	return tool, params
+}
+
+// middleware returns a tool handler middleware that fills the omitted context
+// arguments of a call and appends the filled values to the tool result. A call
+// omitting a context argument that has no value fails with INVALID_ARGUMENT before
+// the handler runs.
+func (c *contextStore) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		params := c.params[request.Params.Name]
+		if len(params) == 0 {
+			return next(ctx, request)
+		}
+
+		arguments := request.GetArguments()
+		var current map[string]contextValue
+		applied := make(map[string]contextValue)
+		for _, arg := range params {
+			if value, ok := arguments[arg]; ok && value != nil && value != "" {
+				continue
+			}
+			if current == nil {
+				current = c.current(ctx)
+			}
+			if current[arg].Source == sourceUnset {
+				return missingContextResult(arg), nil
+			}
+			applied[arg] = current[arg]
+		}
+		if len(applied) == 0 {
+			return next(ctx, request)
+		}
+
+		filled := maps.Clone(arguments)
+		if filled == nil {
+			filled = make(map[string]any)
+		}
+		for arg, value := range applied {
+			filled[arg] = value.Value
+		}
+		request.Params.Arguments = filled
+		slog.DebugContext(ctx, "Filled tool arguments from context", "arguments", applied)
+
+		result, err := next(ctx, request)
+		if err != nil || result == nil {
+			return result, err
+		}
+		note, _ := json.MarshalIndent(map[string]any{"context_applied": applied}, "", "  ")
+		result.Content = append(result.Content, mcp.NewTextContent(string(note)))
+		return result, nil
+	}
+}
+
+// registerTools registers set_context and get_context. It has the signature of a
+// domain's RegisterTools so the context tools form a domain of their own.
+func (c *contextStore) registerTools(s *server.MCPServer, _ clientfactory.Factory) {
+	s.AddTool(createSetContextTool(), c.handleSetContext)
+	s.AddTool(createGetContextTool(), c.handleGetContext)
+}
+
+// createSetContextTool creates the MCP tool definition for setting the context.
+func createSetContextTool() mcp.Tool {
+	return mcp.Tool{
+		Name: "set_context",
+		Description: "Set the current organization and environment of this session. " +
+			"Tools that require org_id or env_name use them when a call omits the argument, " +
+			"and say so in their result. Pass an empty string to clear a value and fall back " +
+			"to the server default. Changing org_id clears env_name unless it is passed too. " +
+			"Values are not checked until a tool uses them.",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				orgIDArg: map[string]interface{}{
+					"type":        "string",
+					"description": "Organization ID to use by default (e.g. 'planton-cloud')",
+				},
+				envNameArg: map[string]interface{}{
+					"type":        "string",
+					"description": "Environment name to use by default (e.g. 'dev', 'prod')",
+				},
+			},
+		},
+		// Only changes this server's session state, nothing in Planton Cloud
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
+	}
+}
+
+// createGetContextTool creates the MCP tool definition for reading the context.
+func createGetContextTool() mcp.Tool {
+	return mcp.Tool{
+		Name: "get_context",
+		Description: "Get the current organization and environment of this session: the values " +
+			"tools use when a call omits org_id or env_name. Each value has a source: 'session' " +
+			"(set with set_context), 'default' (server configuration) or 'unset'.",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{},
+		},
+		Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)},
+	}
+}
+
+// handleSetContext handles the MCP tool invocation for setting the context.
+func (c *contextStore) handleSetContext(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	values := make(map[string]string)
+	for _, arg := range contextArgs {
+		value, ok := request.GetArguments()[arg]
+		if !ok || value == nil {
+			continue
+		}
+		s, ok := value.(string)
+		if !ok {
+			return invalidArgumentResult(fmt.Sprintf("%s must be a string", arg)), nil
+		}
+		values[arg] = s
+	}
+	if len(values) == 0 {
+		return invalidArgumentResult("Provide org_id, env_name or both"), nil
+	}
+
+	id := sessionID(ctx)
+	if id == "" {
+		return invalidArgumentResult(fmt.Sprintf(
+			"This connection has no session to keep a context for. Pass org_id and env_name "+
+				"to each tool, or configure %s and %s.",
+			config.DefaultOrgEnvVar, config.DefaultEnvEnvVar,
+		)), nil
+	}
+	c.set(id, values)
+	slog.DebugContext(ctx, "Context set", "values", values)
+	return c.handleGetContext(ctx, request)
+}
+
+// handleGetContext handles the MCP tool invocation for reading the context.
+func (c *contextStore) handleGetContext(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resultJSON, err := json.MarshalIndent(c.current(ctx), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(resultJSON)), nil
+}
+
+// resolveContextValue returns the session value if set, else the default.
+func resolveContextValue(session, fallback string) contextValue {
+	switch {
+	case session != "":
+		return contextValue{Value: session, Source: sourceSession}
+	case fallback != "":
+		return contextValue{Value: fallback, Source: sourceDefault}
+	default:
+		return contextValue{Source: sourceUnset}
+	}
+}
+
+// sessionID returns the ID of the calling client session, or "" without one, e.g.
+// for the tools call command.
+func sessionID(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
+
+// missingContextResult builds the tool error returned when a call omits a context
+// argument that has no value.
+func missingContextResult(arg string) *mcp.CallToolResult {
+	envVar := config.DefaultOrgEnvVar
+	if arg == envNameArg {
+		envVar = config.DefaultEnvEnvVar
+	}
+	return invalidArgumentResult(fmt.Sprintf(
+		"%s is required. Pass it, set it for this session with set_context, or configure a default with %s.",
+		arg, envVar,
+	))
+}
+
+// invalidArgumentResult builds an INVALID_ARGUMENT tool error.
+func invalidArgumentResult(message string) *mcp.CallToolResult {
+	return commonerrors.ToolError(commonerrors.ErrorResponse{
+		Error:   commonerrors.CodeInvalidArgument,
+		Message: message,
+	})
+}
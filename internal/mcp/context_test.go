@@ -0,0 +1,226 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+)
+
+// newContextServer returns an MCP server with the context tools and an "echo" tool
+// that requires org_id and env_name and returns the arguments it received.
+func newContextServer(cfg *config.Config) (*server.MCPServer, *contextStore) {
+	contexts := contextStoreFromConfig(cfg)
+	hooks := &server.Hooks{}
+	contexts.registerHooks(hooks)
+	mcpServer := server.NewMCPServer("planton-cloud-test", "0.0.0",
+		server.WithToolHandlerMiddleware(contexts.middleware),
+		server.WithHooks(hooks),
+	)
+	contexts.registerTools(mcpServer, nil)
+	mcpServer.AddTool(mcp.NewTool("echo",
+		mcp.WithString("org_id", mcp.Required(), mcp.Description("Organization ID")),
+		mcp.WithString("env_name", mcp.Required(), mcp.Description("Environment name")),
+	), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, _ := json.Marshal(request.GetArguments())
+		return mcp.NewToolResultText(string(args)), nil
+	})
+	contexts.adoptTools(mcpServer)
+	return mcpServer, contexts
+}
+
+// callTool calls a tool and returns its result.
+func callTool(t *testing.T, mcpServer *server.MCPServer, ctx context.Context, name string, arguments map[string]any) mcp.CallToolResult {
+	t.Helper()
+	message, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": name, "arguments": arguments},
+	})
+	response, ok := mcpServer.HandleMessage(ctx, message).(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatalf("%s: no result", name)
+	}
+	return response.Result.(mcp.CallToolResult)
+}
+
+// contentText returns the text of a tool result's content at index i.
+func contentText(t *testing.T, result mcp.CallToolResult, i int) string {
+	t.Helper()
+	if len(result.Content) <= i {
+		t.Fatalf("result has %d content items, want more than %d", len(result.Content), i)
+	}
+	return result.Content[i].(mcp.TextContent).Text
+}
+
+func TestWithContextArgs(t *testing.T) {
+	tools := make(map[string]mcp.Tool)
+	for _, tool := range NewServer(&config.Config{}).Tools() {
+		tools[tool.Name] = tool
+	}
+
+	tests := []struct {
+		tool         string
+		optional     []string
+		stillPresent []string
+	}{
+		{tool: "list_environments_for_org", optional: []string{"org_id"}},
+		{tool: "create_cloud_resource", optional: []string{"org_id", "env_name"}, stillPresent: []string{"cloud_resource_kind"}},
+		// env_names is a filter where omitting it means all environments
+		{tool: "search_cloud_resources", optional: []string{"org_id"}},
+		// org_id is only needed with name, so the tool does not take part
+		{tool: "get_tekton_pipeline"},
+	}
+	for _, tt := range tests {
+		tool, ok := tools[tt.tool]
+		if !ok {
+			t.Fatalf("%s is not registered", tt.tool)
+		}
+		for _, arg := range tt.optional {
+			if slices.Contains(tool.InputSchema.Required, arg) {
+				t.Errorf("%s still requires %s", tt.tool, arg)
+			}
+			description := tool.InputSchema.Properties[arg].(map[string]interface{})["description"].(string)
+			if !strings.HasSuffix(description, contextHint) {
+				t.Errorf("%s %s description = %q, want the context hint", tt.tool, arg, description)
+			}
+		}
+		for _, arg := range tt.stillPresent {
+			if !slices.Contains(tool.InputSchema.Required, arg) {
+				t.Errorf("%s no longer requires %s", tt.tool, arg)
+			}
+		}
+		if tt.optional == nil && strings.Contains(string(mustMarshal(t, tool.InputSchema)), contextHint) {
+			t.Errorf("%s was changed, want it untouched", tt.tool)
+		}
+	}
+}
+
+func TestContextMiddlewareFillsArguments(t *testing.T) {
+	mcpServer, _ := newContextServer(&config.Config{DefaultOrg: "acme", DefaultEnv: "dev"})
+	ctx := mcpServer.WithContext(context.Background(), &fakeSession{id: "session-1"})
+
+	// Explicit arguments are left alone and the result gets no note
+	result := callTool(t, mcpServer, ctx, "echo", map[string]any{"org_id": "other", "env_name": "prod"})
+	if got := contentText(t, result, 0); got != `{"env_name":"prod","org_id":"other"}` {
+		t.Errorf("explicit arguments = %s", got)
+	}
+	if len(result.Content) != 1 {
+		t.Errorf("result has %d content items, want no context note", len(result.Content))
+	}
+
+	// Omitted and empty arguments come from the configured defaults
+	result = callTool(t, mcpServer, ctx, "echo", map[string]any{"env_name": ""})
+	if got := contentText(t, result, 0); got != `{"env_name":"dev","org_id":"acme"}` {
+		t.Errorf("filled arguments = %s", got)
+	}
+	var note struct {
+		Applied map[string]contextValue `json:"context_applied"`
+	}
+	if err := json.Unmarshal([]byte(contentText(t, result, 1)), &note); err != nil {
+		t.Fatalf("context note: %v", err)
+	}
+	want := map[string]contextValue{
+		"org_id":   {Value: "acme", Source: sourceDefault},
+		"env_name": {Value: "dev", Source: sourceDefault},
+	}
+	if note.Applied["org_id"] != want["org_id"] || note.Applied["env_name"] != want["env_name"] {
+		t.Errorf("context_applied = %v, want %v", note.Applied, want)
+	}
+
+	// A session value wins over the default, for that session only
+	callTool(t, mcpServer, ctx, "set_context", map[string]any{"env_name": "prod"})
+	if got := contentText(t, callTool(t, mcpServer, ctx, "echo", nil), 0); got != `{"env_name":"prod","org_id":"acme"}` {
+		t.Errorf("arguments after set_context = %s", got)
+	}
+	otherCtx := mcpServer.WithContext(context.Background(), &fakeSession{id: "session-2"})
+	if got := contentText(t, callTool(t, mcpServer, otherCtx, "echo", nil), 0); got != `{"env_name":"dev","org_id":"acme"}` {
+		t.Errorf("arguments of another session = %s", got)
+	}
+}
+
+func TestContextMiddlewareMissingValue(t *testing.T) {
+	mcpServer, _ := newContextServer(&config.Config{DefaultOrg: "acme"})
+	ctx := mcpServer.WithContext(context.Background(), &fakeSession{id: "session-1"})
+
+	result := callTool(t, mcpServer, ctx, "echo", nil)
+	if !result.IsError {
+		t.Fatalf("call without env_name succeeded: %s", contentText(t, result, 0))
+	}
+	text := contentText(t, result, 0)
+	if !strings.Contains(text, "INVALID_ARGUMENT") || !strings.Contains(text, config.DefaultEnvEnvVar) {
+		t.Errorf("error = %s, want INVALID_ARGUMENT naming %s", text, config.DefaultEnvEnvVar)
+	}
+}
+
+func TestSetContext(t *testing.T) {
+	mcpServer, contexts := newContextServer(&config.Config{DefaultOrg: "acme", DefaultEnv: "dev"})
+	session := &fakeSession{id: "session-1"}
+	ctx := mcpServer.WithContext(context.Background(), session)
+
+	getContext := func() map[string]contextValue {
+		t.Helper()
+		var current map[string]contextValue
+		if err := json.Unmarshal([]byte(contentText(t, callTool(t, mcpServer, ctx, "get_context", nil), 0)), &current); err != nil {
+			t.Fatalf("get_context: %v", err)
+		}
+		return current
+	}
+
+	callTool(t, mcpServer, ctx, "set_context", map[string]any{"org_id": "globex", "env_name": "prod"})
+	if got := getContext(); got["org_id"] != (contextValue{"globex", sourceSession}) || got["env_name"] != (contextValue{"prod", sourceSession}) {
+		t.Errorf("context after set_context = %v", got)
+	}
+
+	// Changing the organization drops the environment of the previous one
+	callTool(t, mcpServer, ctx, "set_context", map[string]any{"org_id": "initech"})
+	if got := getContext(); got["org_id"] != (contextValue{"initech", sourceSession}) || got["env_name"] != (contextValue{"dev", sourceDefault}) {
+		t.Errorf("context after changing org = %v", got)
+	}
+
+	// An empty value falls back to the default
+	callTool(t, mcpServer, ctx, "set_context", map[string]any{"org_id": ""})
+	if got := getContext(); got["org_id"] != (contextValue{"acme", sourceDefault}) {
+		t.Errorf("context after clearing org = %v", got)
+	}
+
+	if result := callTool(t, mcpServer, ctx, "set_context", nil); !result.IsError {
+		t.Error("set_context without arguments succeeded")
+	}
+	if result := callTool(t, mcpServer, context.Background(), "set_context", map[string]any{"org_id": "acme"}); !result.IsError {
+		t.Error("set_context without a session succeeded")
+	}
+
+	// Closed and idle sessions are forgotten
+	callTool(t, mcpServer, ctx, "set_context", map[string]any{"org_id": "globex"})
+	if err := mcpServer.RegisterSession(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+	mcpServer.UnregisterSession(context.Background(), session.SessionID())
+	if got := getContext(); got["org_id"].Source != sourceDefault {
+		t.Errorf("context of closed session = %v", got)
+	}
+
+	contexts.set("idle", map[string]string{orgIDArg: "globex"})
+	contexts.now = func() time.Time { return time.Now().Add(sessionContextTTL + time.Minute) }
+	contexts.set("session-1", map[string]string{orgIDArg: "acme"})
+	if _, ok := contexts.sessions["idle"]; ok {
+		t.Error("idle session context was kept")
+	}
+}
+
+func mustMarshal(t *testing.T, v any) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -1,298 +1,353 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/plantoncloud/mcp-server-planton/internal/common/auth"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/logging"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/metrics"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/tracing"
 	"github.com/plantoncloud/mcp-server-planton/internal/config"
 )
 
+// MCP endpoint paths, relative to the configured base path.
+const (
+	streamableHTTPPath  = "/mcp"
+	sseEndpointPath     = "/sse"
+	messageEndpointPath = "/message"
+)
+
 // HTTPServerOptions configures the HTTP server
 type HTTPServerOptions struct {
-	Port            string
-	AuthEnabled     bool
-	BaseURL         string
+	Port        string
+	AuthEnabled bool
+	// BaseURL is the public scheme and host advertised to SSE clients in the message
+	// endpoint. When empty, clients receive a relative endpoint and resolve it against
+	// the URL they used to open the SSE stream.
+	BaseURL string
+	// BasePath is the path prefix for all MCP endpoints (e.g. "/planton").
+	// The health and readiness checks and metrics are always served at /health,
+	// /ready and /metrics.
+	BasePath string
+	// AllowedOrigins lists the browser origins allowed to call the server, or "*" for
+	// any. The origin of BaseURL is always allowed. Requests without an Origin header
+	// (non-browser clients) are not affected.
+	AllowedOrigins []string
+	// BindAddress is the interface address to listen on. Empty means all interfaces.
+	BindAddress string
+	// OAuthAuthorizationServers are advertised to clients in the protected resource
+	// metadata served at /.well-known/oauth-protected-resource.
+	OAuthAuthorizationServers []string
+	// JWT configures local validation of JWT bearer tokens. An empty JWKS URL passes
+	// every bearer token through to Planton APIs unchecked.
+	JWT auth.JWTConfig
+	// ShutdownDelay is how long /ready reports the server as shutting down, while
+	// connections are still accepted, before the listener is closed.
+	ShutdownDelay time.Duration
+	// ShutdownTimeout bounds graceful shutdown after the serve context is cancelled.
 	ShutdownTimeout time.Duration
 }
 
-// ServeHTTP starts the MCP server with HTTP transport using SSE (Server-Sent Events).
+// ServeHTTP starts the MCP server with HTTP transport.
+//
+// This method blocks until ctx is cancelled or an error occurs.
+// It serves both MCP HTTP transports with bearer token authentication and health checks:
+//   - Streamable HTTP: single endpoint (POST/GET/DELETE /mcp, or POST /) with
+//     JSON or SSE responses and Mcp-Session-Id based sessions
+//   - SSE (legacy): GET /sse for the event stream, POST /message for messages
+//
+// All MCP handlers are mounted directly on our mux under opts.BasePath, so requests
+// keep their context (including the caller's API key) all the way into tool handlers.
+//
+// When ctx is cancelled the server shuts down gracefully:
+//  1. /ready responds with HTTP 503 for opts.ShutdownDelay while requests are still
+//     served, so readiness probes take the server out of rotation first
+//  2. The listener is closed, so no new connections or sessions are accepted
+//  3. New tool calls are rejected and in-flight tool calls are allowed to finish
+//  4. Open SSE and Streamable HTTP streams are closed
+//  5. Remaining connections are drained, or forcibly closed at the deadline
 //
-// This method blocks until the server is shut down or an error occurs.
-// It supports stateless HTTP transport with bearer token authentication and health checks.
+// Steps 2 to 5 are bounded by opts.ShutdownTimeout.
 //
-// Implementation note: The mcp-go library's SSEServer doesn't expose individual handlers,
-// so we run the SSE server on an internal port and proxy requests through our custom
-// server which adds health checks and optional authentication.
-func (s *Server) ServeHTTP(opts HTTPServerOptions) error {
-	log.Printf("Starting MCP server on HTTP port %s", opts.Port)
-	log.Printf("Base URL: %s", opts.BaseURL)
+// Returns nil on a clean shutdown and ErrShutdownTimeout if the deadline was hit.
+func (s *Server) ServeHTTP(ctx context.Context, opts HTTPServerOptions) error {
+	externalURL := opts.BaseURL
+	if externalURL == "" {
+		externalURL = "not set (SSE clients receive a relative message endpoint)"
+	}
+	slog.Info("Starting MCP server on HTTP",
+		"port", opts.Port,
+		"external_url", externalURL,
+		"base_path", opts.BasePath,
+	)
 
 	// Determine if authentication is enabled
 	authEnabled := opts.AuthEnabled
 
 	if authEnabled {
-		log.Println("Bearer token authentication: ENABLED (per-user API keys from Authorization header)")
+		slog.Info("Bearer token authentication enabled (per-user API keys from Authorization header)")
+		if s.tokens != nil {
+			slog.Info("Bearer tokens are checked with Planton APIs before they are served",
+				"cache_ttl", s.config.TokenCacheTTL,
+				"rejected_cache_ttl", s.config.TokenRejectedCacheTTL,
+				"cache_size", s.config.TokenCacheSize,
+			)
+		}
 	} else {
-		log.Println("Bearer token authentication: DISABLED (not recommended for production)")
+		slog.Warn("Bearer token authentication disabled (not recommended for production)")
+		if !isLoopbackAddress(opts.BindAddress) {
+			slog.Warn("Authentication is disabled and the server is reachable from the network; "+
+				"anyone who can connect acts with the server's API key",
+				"bind_address", opts.BindAddress,
+			)
+		}
 	}
 
-	// Start SSE server on internal port
-	internalPort := "18080" // Internal port for SSE server
-	sseServerAddr := "localhost:" + internalPort
-	sseServer := server.NewSSEServer(s.mcpServer, "http://"+sseServerAddr)
+	if len(opts.AllowedOrigins) > 0 {
+		slog.Info("Browser origins allowed", "origins", opts.AllowedOrigins)
+	}
 
-	// Start SSE server in background
-	// Note: We store API keys in the proxy before forwarding requests,
-	// so tool handlers can access them via GetContextWithAPIKey
-	go func() {
-		log.Printf("Starting internal SSE server on %s", sseServerAddr)
-		if err := sseServer.Start(":" + internalPort); err != nil {
-			log.Printf("Internal SSE server error: %v", err)
+	// The JWKS is refreshed in the background until the server shuts down
+	var jwtValidator *auth.JWTValidator
+	if authEnabled && opts.JWT.JWKSURL != "" {
+		var err error
+		jwtValidator, err = auth.NewJWTValidator(ctx, opts.JWT)
+		if err != nil {
+			return fmt.Errorf("failed to set up JWT validation: %w", err)
 		}
-	}()
-
-	// Give the internal server time to start
-	time.Sleep(100 * time.Millisecond)
-
-	// Create proxy server with health check
-	mux := http.NewServeMux()
-
-	// Add health check endpoint (no authentication required)
-	mux.HandleFunc("/health", healthCheckHandler)
-
-	// Create proxy handler with optional authentication
-	var proxyHandler http.HandlerFunc
-	if authEnabled {
-		proxyHandler = createAuthenticatedProxy(sseServerAddr)
-		log.Println("SSE endpoints protected with per-user bearer token authentication")
-	} else {
-		proxyHandler = createProxy(sseServerAddr)
+		slog.Info("JWT bearer tokens are validated locally",
+			"jwks_url", opts.JWT.JWKSURL,
+			"issuer", opts.JWT.Issuer,
+			"audience", opts.JWT.Audience,
+		)
 	}
 
-	// Register catch-all handler that rewrites paths to internal SSE server
-	// This allows users to configure just "http://localhost:8080/" without knowing about /sse
-	mux.HandleFunc("/", proxyHandler)
-
-	log.Println("MCP endpoints available:")
-	log.Println("  - GET  /health   - Health check endpoint")
-	if authEnabled {
-		log.Println("  - GET  /         - SSE connection endpoint (root, authenticated)")
-		log.Println("  - GET  /sse      - SSE connection endpoint (explicit, authenticated)")
-		log.Println("  - POST /message  - Message endpoint (authenticated)")
-	} else {
-		log.Println("  - GET  /         - SSE connection endpoint (root)")
-		log.Println("  - GET  /sse      - SSE connection endpoint (explicit)")
-		log.Println("  - POST /message  - Message endpoint")
-	}
-	log.Println("Transport support:")
-	log.Println("  - SSE transport: SUPPORTED (GET /sse, POST /message)")
-	log.Println("  - streamableHttp: NOT SUPPORTED (POST / will return HTTP 405)")
-
-	// Create logging middleware
-	loggingHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		log.Printf("Request: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
-		mux.ServeHTTP(w, r)
-	})
+	handler := s.newHTTPHandler(opts, jwtValidator)
+
+	root := opts.BasePath + "/"
+	slog.Info("MCP endpoints available",
+		"health", "/health",
+		"ready", "/ready",
+		"metrics", "/metrics",
+		"streamable_http", []string{opts.BasePath + streamableHTTPPath, root},
+		"sse", []string{opts.BasePath + sseEndpointPath, root},
+		"message", opts.BasePath+messageEndpointPath,
+		"protected_resource_metadata", protectedResourceMetadataPath+opts.BasePath+streamableHTTPPath,
+		"authenticated", authEnabled,
+		"credential_policy", httpCredentialPolicy(opts).String(),
+	)
+
+	// Every request context derives from streamsCtx. Cancelling it during shutdown
+	// ends the long-lived SSE and Streamable HTTP GET streams, which would otherwise
+	// keep their connections active forever.
+	streamsCtx, closeStreams := context.WithCancel(context.Background())
+	defer closeStreams()
 
 	// Create and start HTTP server
 	httpServer := &http.Server{
-		Addr:              ":" + opts.Port,
-		Handler:           loggingHandler,
+		Addr:              net.JoinHostPort(opts.BindAddress, opts.Port),
+		Handler:           handler,
 		ReadHeaderTimeout: 10 * time.Second,
 		WriteTimeout:      0, // No timeout for SSE connections
 		IdleTimeout:       120 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return streamsCtx
+		},
 	}
 
-	log.Printf("HTTP server listening on %s", httpServer.Addr)
-	return httpServer.ListenAndServe()
-}
+	errChan := make(chan error, 1)
+	go func() {
+		slog.Info("HTTP server listening", "addr", httpServer.Addr)
+		errChan <- httpServer.ListenAndServe()
+	}()
 
-// createProxy creates a reverse proxy handler without authentication.
-// The proxy forwards requests to the internal SSE server.
-func createProxy(targetAddr string) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		proxyRequest(w, r, targetAddr)
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
 	}
-}
 
-// createAuthenticatedProxy creates a reverse proxy handler with bearer token authentication.
-// The proxy extracts the user's API key from the Authorization header and stores it in the
-// request context for use by downstream gRPC clients. This enables per-user authentication
-// with proper Fine-Grained Authorization.
-func createAuthenticatedProxy(targetAddr string) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		// Extract bearer token from Authorization header
-		authHeader := r.Header.Get("Authorization")
+	slog.Info("Shutting down HTTP server...")
+	s.readiness.setShuttingDown()
+	if opts.ShutdownDelay > 0 {
+		slog.Info("Reporting not ready before closing the listener", "delay", opts.ShutdownDelay)
+		time.Sleep(opts.ShutdownDelay)
+	}
 
-		if authHeader == "" {
-			log.Printf("Authentication failed: Missing Authorization header from %s", r.RemoteAddr)
-			http.Error(w, "Missing Authorization header. Include 'Authorization: Bearer YOUR_API_KEY' header.", http.StatusUnauthorized)
-			return
-		}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), opts.ShutdownTimeout)
+	defer cancel()
 
-		// Extract bearer token
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			log.Printf("Authentication failed: Invalid Authorization header format from %s", r.RemoteAddr)
-			http.Error(w, "Invalid Authorization header format. Expected: Bearer <token>", http.StatusUnauthorized)
-			return
-		}
-
-		token := parts[1]
-		if token == "" {
-			log.Printf("Authentication failed: Empty bearer token from %s", r.RemoteAddr)
-			http.Error(w, "Empty bearer token", http.StatusUnauthorized)
-			return
-		}
+	// Stop accepting connections; Shutdown returns once active connections are done
+	shutdownErr := make(chan error, 1)
+	go func() {
+		shutdownErr <- httpServer.Shutdown(shutdownCtx)
+	}()
 
-		// Store API key in request context for downstream use by gRPC clients
-		ctx := auth.WithAPIKey(r.Context(), token)
-		r = r.WithContext(ctx)
+	drainErr := s.toolCalls.drain(shutdownCtx)
 
-		log.Printf("Authentication: Extracted API key from Authorization header for %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	slog.Info("Closing open MCP streams...")
+	closeStreams()
 
-		// Forward to proxy handler with enriched context
-		proxyRequest(w, r, targetAddr)
+	if err := <-shutdownErr; err != nil {
+		slog.Warn("HTTP connections did not drain in time, closing them", "error", err)
+		if closeErr := httpServer.Close(); closeErr != nil {
+			slog.Error("Error closing HTTP server", "error", closeErr)
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			return ErrShutdownTimeout
+		}
+		return fmt.Errorf("HTTP server shutdown: %w", err)
+	}
+	if drainErr != nil {
+		return drainErr
 	}
+
+	slog.Info("HTTP server stopped")
+	return nil
 }
 
-// proxyRequest handles the actual proxying of requests to the internal SSE server.
-// It properly handles SSE streaming with flushing for real-time updates.
-// It also rewrites internal port references to external port in SSE responses.
-func proxyRequest(w http.ResponseWriter, r *http.Request, targetAddr string) {
-	// Rewrite path for internal SSE server based on HTTP method
-	// This handles both SSE transport (GET /sse, POST /message) and
-	// streamableHttp attempts (POST /) which will fail with 405
-	internalPath := r.URL.Path
-	originalPath := internalPath
-
-	// Route based on HTTP method and path
-	if internalPath == "/" || internalPath == "" {
-		if r.Method == http.MethodGet {
-			// GET / → GET /sse (SSE connection establishment)
-			internalPath = "/sse"
-		} else if r.Method == http.MethodPost {
-			// POST / → POST /message (message sending for clients that use root path)
-			// Note: streamableHttp POST to / will get proxied to /sse and fail with 405
-			internalPath = "/message"
-		}
-		// Other methods pass through as-is and will likely fail
-	}
-	// For explicit paths like /sse, /message, pass through unchanged
+// newHTTPHandler builds the HTTP handler serving the health and readiness checks, metrics and both MCP
+// transports under opts.BasePath, wrapped in request logging and Origin validation.
+// With authentication enabled it also serves the protected resource metadata.
+//
+// Authentication never leaves the request: requireBearerToken stores the caller's
+// API key in the request context, and mcp-go passes that context to the tool handler
+// serving the request. Each tool call therefore sees only its own caller's key.
+// JWT bearer tokens are validated with jwtValidator first, unless it is nil.
+func (s *Server) newHTTPHandler(opts HTTPServerOptions, jwtValidator *auth.JWTValidator) http.Handler {
+	// Legacy SSE transport. The message endpoint sent to clients is built from the
+	// external base URL and base path rather than from the listening address.
+	sseServer := server.NewSSEServer(
+		s.mcpServer,
+		server.WithBaseURL(opts.BaseURL),
+		server.WithStaticBasePath(opts.BasePath),
+		server.WithSSEEndpoint(sseEndpointPath),
+		server.WithMessageEndpoint(messageEndpointPath),
+	)
+
+	// Streamable HTTP transport
+	streamableServer := server.NewStreamableHTTPServer(
+		s.mcpServer,
+		server.WithEndpointPath(opts.BasePath+streamableHTTPPath),
+	)
 
-	// Log path mapping for debugging
-	if originalPath != internalPath {
-		log.Printf("Path mapping: %s %s → %s %s (query: %s)", r.Method, originalPath, r.Method, internalPath, r.URL.RawQuery)
-	}
+	mux := http.NewServeMux()
 
-	// Create proxy request to internal SSE server
-	proxyURL := "http://" + targetAddr + internalPath
-	if r.URL.RawQuery != "" {
-		proxyURL += "?" + r.URL.RawQuery
-	}
+	// Add health check endpoint (no authentication required)
+	mux.HandleFunc("/health", healthCheckHandler)
 
-	proxyReq, err := http.NewRequestWithContext(r.Context(), r.Method, proxyURL, r.Body)
-	if err != nil {
-		log.Printf("Error creating proxy request: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
+	// Add readiness endpoint (no authentication required)
+	mux.HandleFunc("/ready", s.readiness.handler())
+
+	// Add Prometheus metrics endpoint (no authentication required)
+	mux.Handle("/metrics", metrics.Handler())
+
+	// Create MCP handlers with per-API-key rate limits and optional authentication.
+	// Authentication wraps the rate limiter so limits are keyed by the caller's API key.
+	sseHandler := s.limiter.HTTPMiddleware(metrics.TrackSSESession(sseServer.SSEHandler()))
+	messageHandler := s.limiter.HTTPMiddleware(sseServer.MessageHandler())
+	streamableHandler := s.limiter.HTTPMiddleware(streamableServer)
+	rootHandler := createRootHandler(sseHandler, messageHandler, streamableHandler)
+	if opts.AuthEnabled {
+		authenticator := newBearerAuthenticator(opts, jwtValidator, s.tokens)
+		sseHandler = authenticator.requireBearerToken(sseHandler)
+		messageHandler = authenticator.requireBearerToken(messageHandler)
+		streamableHandler = authenticator.requireBearerToken(streamableHandler)
+		rootHandler = authenticator.requireBearerToken(rootHandler)
+
+		// Protected resource metadata (no authentication required): MCP clients read it
+		// after a 401 to discover the authorization servers
+		mux.HandleFunc(protectedResourceMetadataPath, authenticator.metadataHandler)
+		mux.HandleFunc(protectedResourceMetadataPath+"/", authenticator.metadataHandler)
 	}
 
-	// Copy headers (except Authorization since internal server doesn't need it)
-	for key, values := range r.Header {
-		if key != "Authorization" {
-			for _, value := range values {
-				proxyReq.Header.Add(key, value)
-			}
-		}
-	}
+	mux.Handle(opts.BasePath+sseEndpointPath, sseHandler)
+	mux.Handle(opts.BasePath+messageEndpointPath, messageHandler)
+	mux.Handle(opts.BasePath+streamableHTTPPath, streamableHandler)
 
-	// Store API key in global store for tool handlers to access
-	// This is a workaround since mcp-go's AddTool doesn't support context parameters
-	if apiKey, err := auth.GetAPIKey(r.Context()); err == nil {
-		auth.SetCurrentAPIKey(apiKey)
-		log.Printf("Stored API key for tool handlers to access")
+	// Register the root handler that routes to the right transport
+	// This allows users to configure just "http://localhost:8080/" without knowing about /sse or /mcp
+	mux.Handle(opts.BasePath+"/{$}", rootHandler)
+	if opts.BasePath != "" {
+		mux.Handle(opts.BasePath, rootHandler)
 	}
 
-	// Forward request to internal SSE server
-	client := &http.Client{
-		Timeout: 0, // No timeout for SSE connections
-	}
-	resp, err := client.Do(proxyReq)
-	if err != nil {
-		log.Printf("Error proxying request: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
+	// Incoming W3C trace context is extracted first so tool spans join the caller's
+	// trace; the logging middleware then assigns the request ID used in log lines.
+	// Origin checks run before authentication, as CORS preflights carry no credentials.
+	origins := newOriginPolicy(opts.AllowedOrigins, opts.BaseURL)
+	return tracing.HTTPMiddleware(logging.HTTPMiddleware(origins.middleware(
+		withCredentialPolicy(httpCredentialPolicy(opts), mux),
+	)))
+}
+
+// httpCredentialPolicy returns the credential policy of tool calls served over HTTP.
+// With authentication, each call must use its caller's API key and never the server's.
+// Without it the server listens on localhost only, and calls act with the server's key.
+func httpCredentialPolicy(opts HTTPServerOptions) auth.CredentialPolicy {
+	if opts.AuthEnabled {
+		return auth.PolicyCallerKey
 	}
-	defer resp.Body.Close()
+	return auth.PolicyServerKey
+}
 
-	// Log response status for debugging
-	log.Printf("Proxy response: %s %s → status %d", r.Method, internalPath, resp.StatusCode)
+// withCredentialPolicy sets the credential policy on the context of every request
+// served by next, so the tool calls of the request resolve API keys under it.
+func withCredentialPolicy(policy auth.CredentialPolicy, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(auth.WithCredentialPolicy(r.Context(), policy)))
+	})
+}
 
-	// Copy response headers
-	for key, values := range resp.Header {
-		for _, value := range values {
-			w.Header().Add(key, value)
+// createRootHandler creates the handler for the base path itself.
+// Streamable HTTP requests are served by the streamable handler; GET opens a legacy
+// SSE stream and POST with a sessionId query parameter is an SSE message.
+func createRootHandler(sseHandler, messageHandler, streamableHandler http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case isStreamableHTTPRequest(r):
+			streamableHandler.ServeHTTP(w, r)
+		case r.Method == http.MethodGet:
+			sseHandler.ServeHTTP(w, r)
+		case r.Method == http.MethodPost:
+			messageHandler.ServeHTTP(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
 	}
+}
 
-	// Copy status code
-	w.WriteHeader(resp.StatusCode)
-
-	// For SSE connections, we need to flush data as it arrives
-	// and rewrite internal port references to external port
-	if flusher, ok := w.(http.Flusher); ok {
-		// Determine external scheme (http or https) from request
-		scheme := "http"
-		// Check X-Forwarded-Proto header (set by reverse proxies/gateways)
-		if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
-			scheme = proto
-		} else if r.TLS != nil {
-			scheme = "https"
-		}
+// isStreamableHTTPRequest reports whether a request to the root path belongs to the
+// Streamable HTTP transport. Streamable HTTP clients carry an Mcp-Session-Id header
+// once initialized, and POST without the SSE transport's sessionId query parameter.
+func isStreamableHTTPRequest(r *http.Request) bool {
+	if r.Header.Get(server.HeaderKeySessionID) != "" {
+		return true
+	}
+	return r.Method == http.MethodPost && r.URL.Query().Get("sessionId") == ""
+}
 
-		// Stream response body
-		buf := make([]byte, 4096)
-		for {
-			n, err := resp.Body.Read(buf)
-			if n > 0 {
-				// Rewrite internal URLs to external URLs
-				data := buf[:n]
-				dataStr := string(data)
-				// Replace http://localhost:18080 with the external scheme and host
-				if strings.Contains(dataStr, "http://localhost:18080") {
-					host := r.Host
-					if host == "" {
-						host = "localhost:8080"
-					}
-					externalURL := scheme + "://" + host
-					dataStr = strings.ReplaceAll(dataStr, "http://localhost:18080", externalURL)
-					data = []byte(dataStr)
-				}
-
-				if _, writeErr := w.Write(data); writeErr != nil {
-					return
-				}
-				flusher.Flush()
-			}
-			if err != nil {
-				return
-			}
-		}
-	} else {
-		// Fallback for non-streaming responses
-		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+// isLoopbackAddress reports whether a bind address only accepts local connections.
+func isLoopbackAddress(address string) bool {
+	if address == "localhost" {
+		return true
 	}
+	ip := net.ParseIP(address)
+	return ip != nil && ip.IsLoopback()
 }
 
 // healthCheckHandler handles health check requests.
 // Returns a simple JSON response with status "ok" and HTTP 200.
+// It only reports that the process is alive; use /ready to check that the
+// Planton APIs backend is reachable.
 func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
 	// Only allow GET requests
 	if r.Method != http.MethodGet {
@@ -307,7 +362,7 @@ func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
 	// Write JSON response
 	response := map[string]string{"status": "ok"}
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Printf("Error encoding health check response: %v", err)
+		slog.ErrorContext(r.Context(), "Error encoding health check response", "error", err)
 	}
 }
 
@@ -316,9 +371,19 @@ func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
 // header rather than using a shared bearer token from the environment.
 func DefaultHTTPOptions(cfg *config.Config) HTTPServerOptions {
 	return HTTPServerOptions{
-		Port:            cfg.HTTPPort,
-		AuthEnabled:     cfg.HTTPAuthEnabled,
-		BaseURL:         fmt.Sprintf("http://localhost:%s", cfg.HTTPPort),
-		ShutdownTimeout: 10 * time.Second,
+		Port:                      cfg.HTTPPort,
+		AuthEnabled:               cfg.HTTPAuthEnabled,
+		BaseURL:                   cfg.HTTPExternalURL,
+		BasePath:                  cfg.HTTPBasePath,
+		AllowedOrigins:            cfg.HTTPAllowedOrigins,
+		BindAddress:               cfg.HTTPBindAddress,
+		OAuthAuthorizationServers: cfg.OAuthAuthorizationServers,
+		JWT: auth.JWTConfig{
+			JWKSURL:  cfg.JWTJWKSURL,
+			Issuer:   cfg.JWTIssuer,
+			Audience: cfg.JWTAudience,
+		},
+		ShutdownDelay:   cfg.ShutdownDelay,
+		ShutdownTimeout: cfg.ShutdownTimeout,
 	}
 }
@@ -0,0 +1,191 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/auth"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/ratelimit"
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+)
+
+// newWhoAmIServer returns a Server exposing a single "whoami" tool that echoes the
+// API key found in the tool handler's context.
+func newWhoAmIServer() *Server {
+	mcpServer := server.NewMCPServer("planton-cloud-test", "0.0.0")
+	mcpServer.AddTool(
+		mcp.NewTool("whoami"),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			apiKey, err := auth.GetAPIKey(ctx)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			// Give the other session a chance to interleave with this call
+			time.Sleep(time.Millisecond)
+			return mcp.NewToolResultText(apiKey), nil
+		},
+	)
+	return &Server{
+		mcpServer: mcpServer,
+		config:    &config.Config{},
+		toolCalls: &toolCallTracker{},
+		readiness: newReadinessChecker(""),
+		limiter:   ratelimit.New(ratelimit.Limits{}),
+	}
+}
+
+// TestHTTPSessionsAreIsolated runs two sessions with different bearer tokens
+// concurrently against the same HTTP server and checks that every tool call sees
+// only its own session's API key, for both HTTP transports.
+func TestHTTPSessionsAreIsolated(t *testing.T) {
+	s := newWhoAmIServer()
+	httpServer := httptest.NewServer(s.newHTTPHandler(HTTPServerOptions{AuthEnabled: true}, nil))
+	defer httpServer.Close()
+
+	transports := map[string]func(apiKey string) (*client.Client, error){
+		"streamable": func(apiKey string) (*client.Client, error) {
+			return client.NewStreamableHttpClient(httpServer.URL+streamableHTTPPath,
+				transport.WithHTTPHeaders(map[string]string{"Authorization": "Bearer " + apiKey}))
+		},
+		"sse": func(apiKey string) (*client.Client, error) {
+			return client.NewSSEMCPClient(httpServer.URL+sseEndpointPath,
+				client.WithHeaders(map[string]string{"Authorization": "Bearer " + apiKey}))
+		},
+	}
+
+	const callsPerSession = 25
+
+	for name, newClient := range transports {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			var wg sync.WaitGroup
+			for _, apiKey := range []string{"key-alice", "key-bob"} {
+				c, err := newClient(apiKey)
+				if err != nil {
+					t.Fatalf("create client: %v", err)
+				}
+				defer c.Close()
+				if err := c.Start(ctx); err != nil {
+					t.Fatalf("start client: %v", err)
+				}
+				if _, err := c.Initialize(ctx, mcp.InitializeRequest{}); err != nil {
+					t.Fatalf("initialize: %v", err)
+				}
+
+				for i := 0; i < callsPerSession; i++ {
+					wg.Add(1)
+					go func(apiKey string) {
+						defer wg.Done()
+						got, err := callWhoAmI(ctx, c)
+						if err != nil {
+							t.Errorf("call whoami: %v", err)
+							return
+						}
+						if got != apiKey {
+							t.Errorf("session with key %q saw key %q", apiKey, got)
+						}
+					}(apiKey)
+				}
+			}
+			wg.Wait()
+		})
+	}
+}
+
+// callWhoAmI calls the whoami tool and returns the API key it reported.
+func callWhoAmI(ctx context.Context, c *client.Client) (string, error) {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "whoami"
+	result, err := c.CallTool(ctx, request)
+	if err != nil {
+		return "", err
+	}
+	if len(result.Content) != 1 {
+		return "", fmt.Errorf("expected 1 content item, got %d", len(result.Content))
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		return "", fmt.Errorf("unexpected content type %T", result.Content[0])
+	}
+	if result.IsError {
+		return "", fmt.Errorf("tool error: %s", text.Text)
+	}
+	return text.Text, nil
+}
+
+// TestServeHTTPShutdownDelay checks that /ready reports the server as shutting down,
+// while requests are still served, for the shutdown delay before the listener closes.
+func TestServeHTTPShutdownDelay(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	listener.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- newWhoAmIServer().ServeHTTP(ctx, HTTPServerOptions{
+			Port:            port,
+			BindAddress:     "127.0.0.1",
+			ShutdownDelay:   300 * time.Millisecond,
+			ShutdownTimeout: time.Second,
+		})
+	}()
+
+	readyURL := "http://127.0.0.1:" + port + "/ready"
+	readiness := func() (string, error) {
+		resp, err := http.Get(readyURL)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		var report readinessReport
+		if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+			return "", err
+		}
+		return report.Status, nil
+	}
+
+	// The listener is up once /ready answers
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		if _, err := readiness(); err == nil {
+			break
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("server did not start")
+		}
+	}
+
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	if status, err := readiness(); err != nil || status != readinessStatusShuttingDown {
+		t.Errorf("/ready during the shutdown delay = %q, %v, want %q", status, err, readinessStatusShuttingDown)
+	}
+
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("ServeHTTP() = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ServeHTTP did not return")
+	}
+	if _, err := readiness(); err == nil {
+		t.Error("/ready still answers after shutdown")
+	}
+}
@@ -0,0 +1,264 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/plantoncloud/mcp-server-planton/internal/common/auth"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/metrics"
+)
+
+// protectedResourceMetadataPath is the well-known path of the OAuth 2.0 Protected
+// Resource Metadata document (RFC 9728). The metadata of a resource served at path P
+// lives at this path followed by P.
+const protectedResourceMetadataPath = "/.well-known/oauth-protected-resource"
+
+// Human-readable resource name and documentation advertised in the metadata.
+const (
+	resourceName             = "Planton Cloud MCP Server"
+	resourceDocumentationURL = "https://github.com/plantoncloud/mcp-server-planton/blob/main/docs/http-transport.md"
+)
+
+// protectedResourceMetadata is the OAuth 2.0 Protected Resource Metadata document
+// (RFC 9728). MCP clients read it after a 401 to find out where to obtain a token.
+type protectedResourceMetadata struct {
+	Resource               string   `json:"resource"`
+	AuthorizationServers   []string `json:"authorization_servers,omitempty"`
+	BearerMethodsSupported []string `json:"bearer_methods_supported"`
+	ResourceName           string   `json:"resource_name"`
+	ResourceDocumentation  string   `json:"resource_documentation"`
+}
+
+// bearerAuthenticator authenticates MCP requests with bearer tokens, following the
+// MCP authorization specification:
+//   - JWT bearer tokens are validated locally (signature, exp, iss, aud) when a JWKS
+//     is configured
+//   - Other tokens, such as opaque API keys, are checked once with Planton APIs and
+//     the result is cached, when token pre-validation is enabled
+//   - 401 responses carry a WWW-Authenticate challenge pointing at the protected
+//     resource metadata, so clients can discover the authorization servers
+type bearerAuthenticator struct {
+	// jwtValidator validates JWT bearer tokens. Nil passes JWTs on to tokenVerifier.
+	jwtValidator *auth.JWTValidator
+	// tokenVerifier checks the tokens jwtValidator does not with Planton APIs, once
+	// per cache TTL. Nil passes them through unchecked.
+	tokenVerifier *auth.TokenVerifier
+	// externalURL is the public scheme and host of the server, if configured
+	externalURL string
+	// basePath is the path prefix of all MCP endpoints
+	basePath string
+	// authorizationServers are advertised in the protected resource metadata
+	authorizationServers []string
+}
+
+// newBearerAuthenticator creates a bearer authenticator.
+//
+// Args:
+//   - opts: HTTP server options providing the external URL, base path and
+//     authorization servers
+//   - jwtValidator: Validator for JWT bearer tokens, or nil
+//   - tokenVerifier: Verifier for all other bearer tokens, or nil
+//
+// Returns the bearer authenticator.
+func newBearerAuthenticator(opts HTTPServerOptions, jwtValidator *auth.JWTValidator, tokenVerifier *auth.TokenVerifier) *bearerAuthenticator {
+	return &bearerAuthenticator{
+		jwtValidator:         jwtValidator,
+		tokenVerifier:        tokenVerifier,
+		externalURL:          opts.BaseURL,
+		basePath:             opts.BasePath,
+		authorizationServers: opts.OAuthAuthorizationServers,
+	}
+}
+
+// requireBearerToken wraps a handler with bearer token authentication.
+// It extracts the user's API key from the Authorization header and stores it in the
+// request context for use by downstream gRPC clients. This enables per-user authentication
+// with proper Fine-Grained Authorization.
+//
+// JWT bearer tokens are validated locally when a JWKS is configured; other tokens are
+// checked with Planton APIs when a token verifier is set. Rejected requests get HTTP
+// 401 with a WWW-Authenticate challenge (RFC 6750) that references the protected
+// resource metadata.
+func (a *bearerAuthenticator) requireBearerToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract bearer token from Authorization header
+		authHeader := r.Header.Get("Authorization")
+
+		if authHeader == "" {
+			slog.WarnContext(r.Context(), "Authentication failed: missing Authorization header", "remote_addr", r.RemoteAddr)
+			a.challenge(w, r, "", "")
+			http.Error(w, "Missing Authorization header. Include 'Authorization: Bearer YOUR_API_KEY' header.", http.StatusUnauthorized)
+			return
+		}
+
+		// Extract bearer token
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+			slog.WarnContext(r.Context(), "Authentication failed: invalid Authorization header format", "remote_addr", r.RemoteAddr)
+			a.challenge(w, r, "invalid_request", "expected a bearer token")
+			http.Error(w, "Invalid Authorization header format. Expected: Bearer <token>", http.StatusUnauthorized)
+			return
+		}
+
+		token := parts[1]
+		if token == "" {
+			slog.WarnContext(r.Context(), "Authentication failed: empty token", "remote_addr", r.RemoteAddr)
+			a.challenge(w, r, "invalid_request", "empty bearer token")
+			http.Error(w, "Empty bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		// Validate JWTs locally; other tokens are checked with Planton APIs below
+		validatedLocally := a.jwtValidator != nil && auth.IsJWT(token)
+		if validatedLocally {
+			if err := a.jwtValidator.Validate(r.Context(), token); err != nil {
+				reason := "invalid token"
+				var tokenErr *auth.TokenError
+				if errors.As(err, &tokenErr) {
+					reason = tokenErr.Reason
+				}
+				slog.WarnContext(r.Context(), "Authentication failed: invalid JWT",
+					"remote_addr", r.RemoteAddr,
+					"error", err,
+				)
+				a.challenge(w, r, "invalid_token", reason)
+				http.Error(w, "Invalid bearer token: "+reason, http.StatusUnauthorized)
+				return
+			}
+		}
+
+		if a.tokenVerifier != nil && !validatedLocally {
+			if !a.verifyToken(w, r, token) {
+				return
+			}
+		}
+
+		// Store API key in request context for downstream use by gRPC clients
+		ctx := auth.WithAPIKey(r.Context(), token)
+		r = r.WithContext(ctx)
+
+		slog.DebugContext(ctx, "Authentication: extracted API key from Authorization header")
+
+		next(w, r)
+	}
+}
+
+// verifyToken checks a bearer token with Planton APIs, or with the cached result of
+// an earlier check.
+//
+// A token Planton APIs reject is answered with HTTP 401. When the check itself fails,
+// e.g. because Planton APIs are unreachable, the token is let through: its tool calls
+// will report the problem, and an outage does not lock every client out.
+//
+// Returns true if the request may proceed.
+func (a *bearerAuthenticator) verifyToken(w http.ResponseWriter, r *http.Request, token string) bool {
+	cached, err := a.tokenVerifier.Verify(r.Context(), token)
+	cachedLabel := strconv.FormatBool(cached)
+
+	switch {
+	case err == nil:
+		metrics.TokenValidationsTotal.WithLabelValues(metrics.TokenValid, cachedLabel).Inc()
+		return true
+	case errors.Is(err, auth.ErrTokenRejected):
+		metrics.TokenValidationsTotal.WithLabelValues(metrics.TokenRejected, cachedLabel).Inc()
+		slog.WarnContext(r.Context(), "Authentication failed: bearer token rejected by Planton APIs",
+			"remote_addr", r.RemoteAddr,
+			"cached", cached,
+		)
+		a.challenge(w, r, "invalid_token", "invalid or revoked API key")
+		http.Error(w, "Invalid or revoked API key. Check the key in the Planton Cloud console.", http.StatusUnauthorized)
+		return false
+	case r.Context().Err() != nil:
+		// The client went away while waiting for the check
+		return false
+	default:
+		metrics.TokenValidationsTotal.WithLabelValues(metrics.TokenUnverified, cachedLabel).Inc()
+		slog.WarnContext(r.Context(), "Could not verify bearer token with Planton APIs, passing it through",
+			"error", err,
+		)
+		return true
+	}
+}
+
+// challenge sets the WWW-Authenticate header of a 401 response.
+//
+// Args:
+//   - w: The response writer
+//   - r: The rejected request; its path selects the protected resource metadata URL
+//   - errorCode: RFC 6750 error code, or empty when no credentials were sent
+//   - description: Human-readable error description, used with errorCode
+func (a *bearerAuthenticator) challenge(w http.ResponseWriter, r *http.Request, errorCode, description string) {
+	value := fmt.Sprintf(`Bearer resource_metadata=%q`,
+		a.origin(r)+protectedResourceMetadataPath+strings.TrimSuffix(r.URL.Path, "/"))
+	if errorCode != "" {
+		value += fmt.Sprintf(`, error=%q, error_description=%q`, errorCode, description)
+	}
+	w.Header().Set("WWW-Authenticate", value)
+}
+
+// metadataHandler serves the protected resource metadata of each MCP endpoint.
+//
+// The metadata URL of a resource is the well-known path followed by the resource's
+// path, e.g. /.well-known/oauth-protected-resource/planton/mcp for /planton/mcp.
+// Requests for paths that are not MCP endpoints get HTTP 404.
+func (a *bearerAuthenticator) metadataHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	resourcePath := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, protectedResourceMetadataPath), "/")
+	if !a.isResourcePath(resourcePath) {
+		http.NotFound(w, r)
+		return
+	}
+
+	metadata := protectedResourceMetadata{
+		Resource:               a.origin(r) + resourcePath,
+		AuthorizationServers:   a.authorizationServers,
+		BearerMethodsSupported: []string{"header"},
+		ResourceName:           resourceName,
+		ResourceDocumentation:  resourceDocumentationURL,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	if err := json.NewEncoder(w).Encode(metadata); err != nil {
+		slog.ErrorContext(r.Context(), "Error encoding protected resource metadata", "error", err)
+	}
+}
+
+// isResourcePath reports whether path (without trailing slash) is a protected MCP
+// endpoint: the base path itself or one of the transport endpoints under it.
+func (a *bearerAuthenticator) isResourcePath(path string) bool {
+	switch path {
+	case a.basePath,
+		a.basePath + streamableHTTPPath,
+		a.basePath + sseEndpointPath,
+		a.basePath + messageEndpointPath:
+		return true
+	}
+	return false
+}
+
+// origin returns the public scheme and host of the server. The configured external
+// URL wins; otherwise it is derived from the request, honoring X-Forwarded-Proto set
+// by a TLS-terminating proxy.
+func (a *bearerAuthenticator) origin(r *http.Request) string {
+	if a.externalURL != "" {
+		return a.externalURL
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = strings.TrimSpace(strings.Split(proto, ",")[0])
+	}
+	return scheme + "://" + r.Host
+}
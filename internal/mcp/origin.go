@@ -0,0 +1,129 @@
+package mcp
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// corsMaxAge is how long, in seconds, browsers may cache a preflight response.
+const corsMaxAge = 600
+
+// CORS header values. Browsers must be allowed to send the MCP and tracing headers,
+// and to read the session ID, request ID, rate limit and authentication challenge
+// headers from responses.
+var (
+	corsAllowedMethods = strings.Join([]string{
+		http.MethodGet, http.MethodPost, http.MethodDelete, http.MethodOptions,
+	}, ", ")
+
+	corsAllowedHeaders = strings.Join([]string{
+		"Authorization",
+		"Content-Type",
+		"Accept",
+		"Last-Event-ID",
+		server.HeaderKeySessionID,
+		"Mcp-Protocol-Version",
+		"X-Request-Id",
+		"traceparent",
+		"tracestate",
+	}, ", ")
+
+	corsExposedHeaders = strings.Join([]string{
+		server.HeaderKeySessionID,
+		"X-Request-Id",
+		"Retry-After",
+		"WWW-Authenticate",
+	}, ", ")
+)
+
+// originPolicy decides which browser origins may call the HTTP server.
+//
+// Browsers attach an Origin header to cross-origin requests, and to every POST. The
+// MCP specification requires servers to validate it: otherwise a web page can use
+// DNS rebinding to reach a server listening on localhost, which is especially
+// dangerous with authentication disabled. Requests without an Origin header come
+// from non-browser clients and are not affected.
+type originPolicy struct {
+	allowAll bool
+	allowed  map[string]bool
+}
+
+// newOriginPolicy creates an origin policy.
+//
+// Args:
+//   - allowedOrigins: Normalized origins (scheme://host[:port]) allowed to call the
+//     server, or "*" to allow any origin
+//   - externalURL: The server's own public URL. Its origin is always allowed.
+//
+// Returns the origin policy.
+func newOriginPolicy(allowedOrigins []string, externalURL string) *originPolicy {
+	p := &originPolicy{allowed: make(map[string]bool, len(allowedOrigins)+1)}
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			p.allowAll = true
+			continue
+		}
+		p.allowed[origin] = true
+	}
+	if externalURL != "" {
+		p.allowed[strings.ToLower(externalURL)] = true
+	}
+	return p
+}
+
+// isAllowed reports whether requests from origin may be served.
+func (p *originPolicy) isAllowed(origin string) bool {
+	return p.allowAll || p.allowed[strings.ToLower(origin)]
+}
+
+// middleware validates the Origin header of every request and handles CORS.
+//
+// It must wrap authentication: browsers send preflight requests without the
+// Authorization header.
+//   - No Origin header: the request is passed on unchanged
+//   - Disallowed origin: HTTP 403, for preflight and actual requests alike
+//   - Preflight (OPTIONS with Access-Control-Request-Method): answered with HTTP 204
+//     and the allowed methods and headers, without reaching the MCP handlers
+//   - Allowed origin: passed on with CORS response headers
+func (p *originPolicy) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Responses differ by origin, so shared caches must not mix them up
+		w.Header().Add("Vary", "Origin")
+
+		if !p.isAllowed(origin) {
+			slog.WarnContext(r.Context(), "Rejected request from disallowed origin",
+				"origin", origin,
+				"method", r.Method,
+				"path", r.URL.Path,
+				"remote_addr", r.RemoteAddr,
+			)
+			http.Error(w, "Origin not allowed", http.StatusForbidden)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			w.Header().Set("Access-Control-Allow-Methods", corsAllowedMethods)
+			w.Header().Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(corsMaxAge))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		w.Header().Set("Access-Control-Expose-Headers", corsExposedHeaders)
+		next.ServeHTTP(w, r)
+	})
+}
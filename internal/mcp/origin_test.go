@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOriginPolicy(t *testing.T) {
+	policy := newOriginPolicy([]string{"https://app.planton.ai"}, "https://MCP.planton.ai")
+
+	tests := []struct {
+		name        string
+		method      string
+		origin      string
+		preflight   bool
+		wantCode    int
+		wantReached bool
+		wantAllow   string
+	}{
+		{name: "no origin", method: http.MethodPost, wantCode: http.StatusOK, wantReached: true},
+		{name: "allowed origin", method: http.MethodPost, origin: "https://app.planton.ai", wantCode: http.StatusOK, wantReached: true, wantAllow: "https://app.planton.ai"},
+		{name: "allowed origin in another case", method: http.MethodPost, origin: "https://APP.planton.ai", wantCode: http.StatusOK, wantReached: true, wantAllow: "https://APP.planton.ai"},
+		{name: "external url origin", method: http.MethodGet, origin: "https://mcp.planton.ai", wantCode: http.StatusOK, wantReached: true, wantAllow: "https://mcp.planton.ai"},
+		{name: "disallowed origin", method: http.MethodPost, origin: "http://evil.example", wantCode: http.StatusForbidden},
+		{name: "disallowed preflight", method: http.MethodOptions, origin: "http://evil.example", preflight: true, wantCode: http.StatusForbidden},
+		{name: "preflight", method: http.MethodOptions, origin: "https://app.planton.ai", preflight: true, wantCode: http.StatusNoContent, wantAllow: "https://app.planton.ai"},
+		// An OPTIONS request that is not a preflight is passed on
+		{name: "plain options", method: http.MethodOptions, origin: "https://app.planton.ai", wantCode: http.StatusOK, wantReached: true, wantAllow: "https://app.planton.ai"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached := false
+			handler := policy.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				reached = true
+			}))
+
+			req := httptest.NewRequest(tt.method, "/mcp", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode || reached != tt.wantReached {
+				t.Errorf("status %d, reached %t; want %d, %t", rec.Code, reached, tt.wantCode, tt.wantReached)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantAllow {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantAllow)
+			}
+			if tt.preflight && tt.wantCode == http.StatusNoContent {
+				if rec.Header().Get("Access-Control-Allow-Headers") != corsAllowedHeaders ||
+					rec.Header().Get("Access-Control-Allow-Methods") != corsAllowedMethods {
+					t.Errorf("preflight headers = %v", rec.Header())
+				}
+			}
+			if tt.origin != "" && rec.Header().Get("Vary") != "Origin" {
+				t.Errorf("Vary = %q, want Origin", rec.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+func TestOriginPolicyAllowAll(t *testing.T) {
+	policy := newOriginPolicy([]string{"*"}, "")
+	if !policy.isAllowed("http://localhost:3000") {
+		t.Error("* does not allow every origin")
+	}
+}
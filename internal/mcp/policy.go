@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+)
+
+// Reasons a tool is not registered
+const (
+	excludedDenied   = "denied"
+	excludedReadOnly = "read-only mode"
+	excludedNotAllow = "not allowed"
+)
+
+// toolPolicy decides which tools the server registers, from PLANTON_MCP_READ_ONLY,
+// PLANTON_MCP_TOOLS_ALLOW and PLANTON_MCP_TOOLS_DENY. Allow and deny entries are tool
+// names or domain names (see Server.toolDomains). A denied tool is never registered, and in
+// read-only mode neither is a tool not annotated as read-only, even when allowed by
+// name.
+type toolPolicy struct {
+	readOnly bool
+	// allow lists the tools and domains that may be registered; empty allows all
+	allow []string
+	deny  []string
+}
+
+// toolPolicyFromConfig creates the tool policy of the configuration.
+func toolPolicyFromConfig(cfg *config.Config) toolPolicy {
+	return toolPolicy{
+		readOnly: cfg.ReadOnly,
+		allow:    cfg.ToolsAllow,
+		deny:     cfg.ToolsDeny,
+	}
+}
+
+// active reports whether the policy can exclude any tool.
+func (p toolPolicy) active() bool {
+	return p.readOnly || len(p.allow) > 0 || len(p.deny) > 0
+}
+
+// exclusion returns why a tool of a domain is not registered, or "" if it is.
+//
+// Args:
+//   - domain: Domain registering the tool, e.g. "infrahub"
+//   - tool: Tool definition, whose ReadOnlyHint annotation decides read-only mode
+func (p toolPolicy) exclusion(domain string, tool mcp.Tool) string {
+	switch {
+	case slices.Contains(p.deny, tool.Name) || slices.Contains(p.deny, domain):
+		return excludedDenied
+	case p.readOnly && !isReadOnlyTool(tool):
+		return excludedReadOnly
+	case len(p.allow) > 0 && !slices.Contains(p.allow, tool.Name) && !slices.Contains(p.allow, domain):
+		return excludedNotAllow
+	default:
+		return ""
+	}
+}
+
+// unknownEntries returns the allow and deny entries that name neither a tool nor a
+// domain, which are most likely typos.
+//
+// Args:
+//   - known: Names of every tool and domain, including excluded tools
+func (p toolPolicy) unknownEntries(known map[string]bool) (allow, deny []string) {
+	for _, entry := range p.allow {
+		if !known[entry] {
+			allow = append(allow, entry)
+		}
+	}
+	for _, entry := range p.deny {
+		if !known[entry] {
+			deny = append(deny, entry)
+		}
+	}
+	return allow, deny
+}
+
+// isReadOnlyTool reports whether a tool is annotated as not modifying anything.
+// Tools without the annotation count as modifying, so a new tool is left out of
+// read-only mode until it is annotated.
+func isReadOnlyTool(tool mcp.Tool) bool {
+	return tool.Annotations.ReadOnlyHint != nil && *tool.Annotations.ReadOnlyHint
+}
@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+)
+
+// mutatingTools are the tools read-only mode must exclude
+var mutatingTools = []string{
+	"create_cloud_resource",
+	"update_cloud_resource",
+	"delete_cloud_resource",
+	"get_github_installation_token",
+}
+
+// toolNames returns the names of the tools a server registers for a configuration.
+func toolNames(t *testing.T, cfg *config.Config) []string {
+	t.Helper()
+	var names []string
+	for _, tool := range NewServer(cfg).Tools() {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func TestToolsDeclareReadOnlyHint(t *testing.T) {
+	for _, tool := range NewServer(&config.Config{}).Tools() {
+		if tool.Annotations.ReadOnlyHint == nil {
+			t.Errorf("%s has no ReadOnlyHint annotation; read-only mode would exclude it", tool.Name)
+			continue
+		}
+		if want := !slices.Contains(mutatingTools, tool.Name); isReadOnlyTool(tool) != want {
+			t.Errorf("%s ReadOnlyHint = %v, want %v", tool.Name, isReadOnlyTool(tool), want)
+		}
+	}
+}
+
+func TestToolPolicyExclusion(t *testing.T) {
+	readOnlyTool := mcp.Tool{Name: "list_services_for_org", Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(true)}}
+	writeTool := mcp.Tool{Name: "delete_cloud_resource", Annotations: mcp.ToolAnnotation{ReadOnlyHint: mcp.ToBoolPtr(false)}}
+	unannotatedTool := mcp.Tool{Name: "new_tool"}
+
+	tests := []struct {
+		name   string
+		policy toolPolicy
+		domain string
+		tool   mcp.Tool
+		want   string
+	}{
+		{name: "no policy", domain: "infrahub", tool: writeTool, want: ""},
+		{name: "read-only allows read tools", policy: toolPolicy{readOnly: true}, domain: "servicehub", tool: readOnlyTool, want: ""},
+		{name: "read-only excludes write tools", policy: toolPolicy{readOnly: true}, domain: "infrahub", tool: writeTool, want: excludedReadOnly},
+		{name: "read-only excludes unannotated tools", policy: toolPolicy{readOnly: true}, domain: "infrahub", tool: unannotatedTool, want: excludedReadOnly},
+		{name: "read-only wins over allow by name", policy: toolPolicy{readOnly: true, allow: []string{"delete_cloud_resource"}}, domain: "infrahub", tool: writeTool, want: excludedReadOnly},
+		{name: "allow by domain", policy: toolPolicy{allow: []string{"servicehub"}}, domain: "servicehub", tool: readOnlyTool, want: ""},
+		{name: "allow by name", policy: toolPolicy{allow: []string{"delete_cloud_resource"}}, domain: "infrahub", tool: writeTool, want: ""},
+		{name: "not in allow list", policy: toolPolicy{allow: []string{"servicehub"}}, domain: "infrahub", tool: writeTool, want: excludedNotAllow},
+		{name: "deny by domain", policy: toolPolicy{deny: []string{"servicehub"}}, domain: "servicehub", tool: readOnlyTool, want: excludedDenied},
+		{name: "deny wins over allow", policy: toolPolicy{allow: []string{"servicehub"}, deny: []string{"list_services_for_org"}}, domain: "servicehub", tool: readOnlyTool, want: excludedDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.exclusion(tt.domain, tt.tool); got != tt.want {
+				t.Errorf("exclusion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterToolsAppliesPolicy(t *testing.T) {
+	all := toolNames(t, &config.Config{})
+
+	readOnly := toolNames(t, &config.Config{ReadOnly: true})
+	for _, name := range mutatingTools {
+		if !slices.Contains(all, name) {
+			t.Errorf("%s is not registered without a policy", name)
+		}
+		if slices.Contains(readOnly, name) {
+			t.Errorf("%s is registered in read-only mode", name)
+		}
+	}
+	if len(readOnly) != len(all)-len(mutatingTools) {
+		t.Errorf("read-only mode registers %d of %d tools, want all but the %d mutating ones", len(readOnly), len(all), len(mutatingTools))
+	}
+
+	got := toolNames(t, &config.Config{
+		ToolsAllow: []string{"resourcemanager", "get_cloud_resource_by_id"},
+		ToolsDeny:  []string{"list_environments_for_org"},
+	})
+	want := []string{"get_cloud_resource_by_id", "list_organizations"}
+	if !slices.Equal(got, want) {
+		t.Errorf("tools = %v, want %v", got, want)
+	}
+}
+
+func TestToolPolicyUnknownEntries(t *testing.T) {
+	policy := toolPolicy{allow: []string{"infrahub", "infrahb"}, deny: []string{"delete_cloud_resource", "delete_resource"}}
+	allow, deny := policy.unknownEntries(map[string]bool{"infrahub": true, "delete_cloud_resource": true})
+	if !slices.Equal(allow, []string{"infrahb"}) || !slices.Equal(deny, []string{"delete_resource"}) {
+		t.Errorf("unknownEntries() = %v, %v; want the misspelled entries", allow, deny)
+	}
+}
@@ -0,0 +1,222 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/plantoncloud/mcp-server-planton/internal/common/grpcpool"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// readinessCacheTTL is how long a readiness report is reused before the
+	// dependencies are probed again. It keeps frequent probes from several
+	// kubelets or load balancers from hammering the backend.
+	readinessCacheTTL = 5 * time.Second
+
+	// readinessProbeTimeout bounds each dependency probe.
+	readinessProbeTimeout = 3 * time.Second
+
+	// plantonAPIsDependency is the name of the Planton APIs dependency in readiness reports.
+	plantonAPIsDependency = "planton_apis"
+)
+
+// Readiness status values
+const (
+	readinessStatusReady        = "ready"
+	readinessStatusNotReady     = "not_ready"
+	readinessStatusShuttingDown = "shutting_down"
+	dependencyStatusOK          = "ok"
+	dependencyStatusError       = "error"
+)
+
+// dependencyProbe checks a single dependency and returns an error if it is unusable.
+type dependencyProbe struct {
+	name     string
+	endpoint string
+	check    func(ctx context.Context) error
+}
+
+// dependencyStatus is the readiness result for a single dependency.
+type dependencyStatus struct {
+	Status    string `json:"status"`
+	Endpoint  string `json:"endpoint,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// readinessReport is the JSON body returned by the /ready endpoint.
+type readinessReport struct {
+	Status       string                      `json:"status"`
+	CheckedAt    time.Time                   `json:"checked_at"`
+	Dependencies map[string]dependencyStatus `json:"dependencies"`
+}
+
+// readinessChecker probes the server's dependencies and caches the result for
+// readinessCacheTTL.
+type readinessChecker struct {
+	probes []dependencyProbe
+
+	// shuttingDown is set when the HTTP server starts shutting down
+	shuttingDown atomic.Bool
+
+	mu        sync.Mutex
+	lastCheck time.Time
+	report    readinessReport
+}
+
+// newReadinessChecker creates a readiness checker for the Planton APIs gRPC endpoint.
+func newReadinessChecker(grpcEndpoint string) *readinessChecker {
+	return &readinessChecker{
+		probes: []dependencyProbe{
+			{
+				name:     plantonAPIsDependency,
+				endpoint: grpcEndpoint,
+				check: func(ctx context.Context) error {
+					return probeGRPCEndpoint(ctx, grpcEndpoint)
+				},
+			},
+		},
+	}
+}
+
+// check returns the current readiness report, probing the dependencies again
+// when the cached report is older than readinessCacheTTL.
+//
+// Concurrent callers share a single probe: the lock is held while probing, so
+// callers arriving during a probe wait for its result instead of starting another.
+func (c *readinessChecker) check(ctx context.Context) readinessReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.lastCheck.IsZero() && time.Since(c.lastCheck) < readinessCacheTTL {
+		return c.report
+	}
+
+	report := readinessReport{
+		Status:       readinessStatusReady,
+		CheckedAt:    time.Now().UTC(),
+		Dependencies: make(map[string]dependencyStatus, len(c.probes)),
+	}
+
+	for _, probe := range c.probes {
+		probeCtx, cancel := context.WithTimeout(ctx, readinessProbeTimeout)
+		start := time.Now()
+		err := probe.check(probeCtx)
+		cancel()
+
+		dep := dependencyStatus{
+			Status:    dependencyStatusOK,
+			Endpoint:  probe.endpoint,
+			LatencyMs: time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			dep.Status = dependencyStatusError
+			dep.Error = err.Error()
+			report.Status = readinessStatusNotReady
+			slog.WarnContext(ctx, "Readiness: dependency is unavailable",
+				"dependency", probe.name,
+				"endpoint", probe.endpoint,
+				"error", err,
+			)
+		}
+		report.Dependencies[probe.name] = dep
+	}
+
+	// A probe aborted by the caller going away says nothing about the dependency,
+	// so don't let it poison the cache for other callers.
+	if ctx.Err() == nil {
+		c.lastCheck = time.Now()
+		c.report = report
+	}
+
+	return report
+}
+
+// setShuttingDown makes the /ready endpoint report the server as shutting down.
+func (c *readinessChecker) setShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// handler returns the HTTP handler for the /ready endpoint.
+//
+// It responds with HTTP 200 when every dependency is reachable and HTTP 503
+// otherwise, so the pod is taken out of rotation until the backend is reachable
+// again. Once the server is shutting down it always responds with HTTP 503.
+func (c *readinessChecker) handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Only allow GET requests
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var report readinessReport
+		if c.shuttingDown.Load() {
+			report = readinessReport{
+				Status:       readinessStatusShuttingDown,
+				CheckedAt:    time.Now().UTC(),
+				Dependencies: map[string]dependencyStatus{},
+			}
+		} else {
+			report = c.check(r.Context())
+		}
+
+		statusCode := http.StatusOK
+		if report.Status != readinessStatusReady {
+			statusCode = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			slog.ErrorContext(r.Context(), "Error encoding readiness response", "error", err)
+		}
+	}
+}
+
+// probeGRPCEndpoint checks that the gRPC endpoint is reachable.
+//
+// It opens a fresh connection with the same transport security as the domain
+// clients (see grpcpool.TransportSecurity) and calls the standard gRPC
+// health service. Connection and TLS handshake failures surface as Unavailable.
+// Any other response - including Unimplemented when the backend does not expose
+// the health service - proves the endpoint accepted the connection and answered.
+//
+// Args:
+//   - ctx: Context bounding the probe
+//   - grpcEndpoint: Planton Cloud APIs endpoint (e.g., "api.live.planton.cloud:443")
+//
+// Returns nil if the endpoint is reachable, or an error describing why it is not.
+func probeGRPCEndpoint(ctx context.Context, grpcEndpoint string) error {
+	conn, err := grpc.NewClient(grpcEndpoint, grpc.WithTransportCredentials(grpcpool.TransportCredentials(grpcEndpoint)))
+	if err != nil {
+		return fmt.Errorf("failed to create gRPC client: %w", err)
+	}
+	defer conn.Close()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+			return fmt.Errorf("gRPC endpoint unreachable: %w", err)
+		default:
+			// The server answered, so the connection (and TLS handshake) works
+			return nil
+		}
+	}
+
+	if resp.GetStatus() == healthpb.HealthCheckResponse_NOT_SERVING {
+		return fmt.Errorf("gRPC endpoint reports NOT_SERVING")
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// fakeHealthServer answers health checks with a fixed status or error.
+type fakeHealthServer struct {
+	healthpb.UnimplementedHealthServer
+	status healthpb.HealthCheckResponse_ServingStatus
+	err    error
+}
+
+func (s *fakeHealthServer) Check(context.Context, *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &healthpb.HealthCheckResponse{Status: s.status}, nil
+}
+
+// startHealthServer serves the health service on a local port and returns its endpoint.
+func startHealthServer(t *testing.T, health healthpb.HealthServer) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, health)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String()
+}
+
+func TestProbeGRPCEndpoint(t *testing.T) {
+	tests := []struct {
+		name    string
+		health  *fakeHealthServer
+		wantErr bool
+	}{
+		{name: "serving", health: &fakeHealthServer{status: healthpb.HealthCheckResponse_SERVING}},
+		{name: "not serving", health: &fakeHealthServer{status: healthpb.HealthCheckResponse_NOT_SERVING}, wantErr: true},
+		{name: "unavailable", health: &fakeHealthServer{err: status.Error(codes.Unavailable, "overloaded")}, wantErr: true},
+		{name: "deadline exceeded", health: &fakeHealthServer{err: status.Error(codes.DeadlineExceeded, "slow")}, wantErr: true},
+		{name: "canceled", health: &fakeHealthServer{err: status.Error(codes.Canceled, "canceled")}, wantErr: true},
+		// The backend answered, so it is reachable
+		{name: "health service not exposed", health: &fakeHealthServer{err: status.Error(codes.Unimplemented, "unknown service")}},
+		{name: "unauthenticated", health: &fakeHealthServer{err: status.Error(codes.Unauthenticated, "no token")}},
+		{name: "internal", health: &fakeHealthServer{err: status.Error(codes.Internal, "boom")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint := startHealthServer(t, tt.health)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := probeGRPCEndpoint(ctx, endpoint); (err != nil) != tt.wantErr {
+				t.Errorf("probeGRPCEndpoint() = %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("nothing listening", func(t *testing.T) {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		endpoint := lis.Addr().String()
+		lis.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := probeGRPCEndpoint(ctx, endpoint); err == nil {
+			t.Error("probeGRPCEndpoint() = nil for a closed port")
+		}
+	})
+}
+
+// countingChecker returns a readiness checker whose only probe counts its calls
+// and fails with err.
+func countingChecker(probes *int, err error) *readinessChecker {
+	return &readinessChecker{
+		probes: []dependencyProbe{{
+			name:     plantonAPIsDependency,
+			endpoint: "planton:443",
+			check: func(context.Context) error {
+				*probes++
+				return err
+			},
+		}},
+	}
+}
+
+func TestReadinessCache(t *testing.T) {
+	probes := 0
+	checker := countingChecker(&probes, nil)
+
+	checker.check(context.Background())
+	checker.check(context.Background())
+	if probes != 1 {
+		t.Errorf("probes within readinessCacheTTL = %d, want 1", probes)
+	}
+
+	checker.lastCheck = time.Now().Add(-readinessCacheTTL)
+	checker.check(context.Background())
+	if probes != 2 {
+		t.Errorf("probes after readinessCacheTTL = %d, want 2", probes)
+	}
+
+	// A probe cut short by the caller is not cached
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	checker.lastCheck = time.Time{}
+	checker.check(ctx)
+	checker.check(context.Background())
+	if probes != 4 {
+		t.Errorf("probes after a canceled check = %d, want 4", probes)
+	}
+}
+
+func TestReadinessHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		probeErr   error
+		shutdown   bool
+		wantCode   int
+		wantStatus string
+		wantProbes int
+	}{
+		{name: "ready", wantCode: http.StatusOK, wantStatus: readinessStatusReady, wantProbes: 1},
+		{name: "dependency down", probeErr: errors.New("gRPC endpoint unreachable"), wantCode: http.StatusServiceUnavailable, wantStatus: readinessStatusNotReady, wantProbes: 1},
+		{name: "shutting down", shutdown: true, wantCode: http.StatusServiceUnavailable, wantStatus: readinessStatusShuttingDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probes := 0
+			checker := countingChecker(&probes, tt.probeErr)
+			if tt.shutdown {
+				checker.setShuttingDown()
+			}
+
+			rec := httptest.NewRecorder()
+			checker.handler()(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+			var report readinessReport
+			if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantCode || report.Status != tt.wantStatus {
+				t.Errorf("got %d %q, want %d %q", rec.Code, report.Status, tt.wantCode, tt.wantStatus)
+			}
+			if probes != tt.wantProbes {
+				t.Errorf("probes = %d, want %d", probes, tt.wantProbes)
+			}
+		})
+	}
+}
@@ -1,74 +1,264 @@
 package mcp
 
 import (
-	"log"
+	"context"
+	"errors"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"time"
 
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/auth"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/buildinfo"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/clientfactory"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/logging"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/metrics"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/ratelimit"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/tracing"
 	"github.com/plantoncloud/mcp-server-planton/internal/config"
 	"github.com/plantoncloud/mcp-server-planton/internal/domains/commons"
 	"github.com/plantoncloud/mcp-server-planton/internal/domains/connect"
 	"github.com/plantoncloud/mcp-server-planton/internal/domains/infrahub"
 	"github.com/plantoncloud/mcp-server-planton/internal/domains/resourcemanager"
 	"github.com/plantoncloud/mcp-server-planton/internal/domains/servicehub"
+	"github.com/plantoncloud/mcp-server-planton/internal/domains/servicehub/pipeline"
 )
 
+// streamingTools lists the tools that hold a long-lived stream to Planton APIs.
+// They have their own per-API-key concurrency cap on the HTTP transport.
+var streamingTools = []string{"get_pipeline_build_logs"}
+
+// defaultToolTimeouts are the per-tool timeouts used unless PLANTON_MCP_TOOL_TIMEOUTS
+// overrides them. The log stream gets its usual streaming time plus the time to build
+// its response, since it returns partial logs shortly before its deadline.
+var defaultToolTimeouts = map[string]time.Duration{
+	"get_pipeline_build_logs": pipeline.MaxLogStreamDuration + pipeline.LogResponseReserve,
+}
+
 // Server wraps the MCP server instance and configuration.
 type Server struct {
 	mcpServer *server.MCPServer
 	config    *config.Config
+	toolCalls *toolCallTracker
+	// calls applies tool deadlines and cancels tool calls the client gave up on
+	calls *callCanceller
+	// contexts keeps the current organization and environment of each session
+	contexts  *contextStore
+	readiness *readinessChecker
+	limiter   *ratelimit.Limiter
+	// tokens pre-validates HTTP bearer tokens with Planton APIs; nil disables it
+	tokens *auth.TokenVerifier
+	// clients creates the Planton API clients of tool handlers
+	clients clientfactory.Factory
 }
 
 // NewServer creates a new MCP server instance.
 func NewServer(cfg *config.Config) *Server {
+	toolCalls := &toolCallTracker{}
+	limiter := ratelimit.New(ratelimit.LimitsFromConfig(cfg), streamingTools...)
+
+	toolTimeouts := maps.Clone(defaultToolTimeouts)
+	maps.Copy(toolTimeouts, cfg.ToolTimeouts)
+	calls := newCallCanceller(cfg.ToolTimeout, toolTimeouts)
+	hooks := &server.Hooks{}
+	calls.registerHooks(hooks)
+	contexts := contextStoreFromConfig(cfg)
+	contexts.registerHooks(hooks)
+
 	// Create MCP server with server info and resource capabilities enabled
 	mcpServer := server.NewMCPServer(
 		"planton-cloud",
-		"0.1.0",
+		buildinfo.Get().Version,
 		server.WithResourceCapabilities(false, false), // (subscribe, listChanged)
+		server.WithToolHandlerMiddleware(tracing.ToolHandlerMiddleware),
+		server.WithToolHandlerMiddleware(logging.ToolHandlerMiddleware),
+		server.WithToolHandlerMiddleware(metrics.ToolHandlerMiddleware),
+		server.WithToolHandlerMiddleware(limiter.ToolHandlerMiddleware),
+		server.WithToolHandlerMiddleware(toolCalls.middleware),
+		server.WithToolHandlerMiddleware(calls.middleware),
+		server.WithToolHandlerMiddleware(contexts.middleware),
+		server.WithHooks(hooks),
 	)
+	mcpServer.AddNotificationHandler(cancelledNotificationMethod, calls.handleCancelled)
 
 	s := &Server{
 		mcpServer: mcpServer,
 		config:    cfg,
+		toolCalls: toolCalls,
+		calls:     calls,
+		contexts:  contexts,
+		readiness: newReadinessChecker(cfg.PlantonAPIsGRPCEndpoint),
+		limiter:   limiter,
+		tokens:    newTokenVerifier(cfg),
+		clients:   clientfactory.New(cfg),
 	}
 
 	// Register tool handlers
-	s.registerTools()
+	known := s.registerTools()
+	s.warnUnknownToolTimeouts(known)
 
-	log.Println("MCP server initialized with resource capabilities")
-	log.Printf("Transport mode: %s", cfg.Transport)
-	log.Printf("Planton APIs endpoint: %s", cfg.PlantonAPIsGRPCEndpoint)
-	log.Println("User API key loaded from environment")
+	slog.Info("MCP server initialized with resource capabilities",
+		"version", buildinfo.Get().Version,
+		"transport", cfg.Transport,
+		"planton_apis_endpoint", cfg.PlantonAPIsGRPCEndpoint,
+		"default_auth_configured", cfg.PlantonAPIKey != "",
+		"default_org", cfg.DefaultOrg,
+		"default_env", cfg.DefaultEnv,
+	)
 
 	return s
 }
 
-// registerTools registers all available MCP tools with their handlers.
-func (s *Server) registerTools() {
-	log.Println("Registering MCP tools...")
+// toolDomain is a group of tools registered together.
+type toolDomain struct {
+	name     string
+	register func(*server.MCPServer, clientfactory.Factory)
+}
+
+// toolDomains returns the tool domains in registration order. Their names can be
+// used in PLANTON_MCP_TOOLS_ALLOW and PLANTON_MCP_TOOLS_DENY to select all their tools.
+func (s *Server) toolDomains() []toolDomain {
+	return []toolDomain{
+		// set_context and get_context
+		{"context", s.contexts.registerTools},
+		// API resource kinds, etc.
+		{"commons", commons.RegisterTools},
+		{"infrahub", infrahub.RegisterTools},
+		{"resourcemanager", resourcemanager.RegisterTools},
+		{"servicehub", servicehub.RegisterTools},
+		{"connect", connect.RegisterTools},
+	}
+}
+
+// registerTools registers the MCP tools the tool policy allows (see toolPolicy).
+// Excluded tools are removed right after their domain registers them, before any
+// client connects, so they never appear in tools/list and cannot be called. The
+// required org_id and env_name arguments of the remaining tools are then made
+// optional, to be filled from the session context (see contextStore).
+//
+// Returns the names of every tool and domain, including excluded tools.
+func (s *Server) registerTools() map[string]bool {
+	slog.Debug("Registering MCP tools...")
 
-	// Register Commons tools (API resource kinds, etc.)
-	commons.RegisterTools(s.mcpServer, s.config)
+	policy := toolPolicyFromConfig(s.config)
+	known := make(map[string]bool)
+	excluded := make(map[string][]string)
+	for _, domain := range s.toolDomains() {
+		known[domain.name] = true
+		before := s.mcpServer.ListTools()
+		domain.register(s.mcpServer, s.clients)
 
-	// Register InfraHub tools
-	infrahub.RegisterTools(s.mcpServer, s.config)
+		for name, tool := range s.mcpServer.ListTools() {
+			if _, ok := before[name]; ok {
+				continue
+			}
+			known[name] = true
+			if reason := policy.exclusion(domain.name, tool.Tool); reason != "" {
+				excluded[reason] = append(excluded[reason], name)
+				s.mcpServer.DeleteTools(name)
+			}
+		}
+	}
 
-	// Register ResourceManager tools
-	resourcemanager.RegisterTools(s.mcpServer, s.config)
+	s.contexts.adoptTools(s.mcpServer)
 
-	// Register Service Hub tools
-	servicehub.RegisterTools(s.mcpServer, s.config)
+	registered := len(s.mcpServer.ListTools())
+	if !policy.active() {
+		slog.Info("All tools registered successfully", "tools", registered)
+		return known
+	}
 
-	// Register Connect tools
-	connect.RegisterTools(s.mcpServer, s.config)
+	unknownAllow, unknownDeny := policy.unknownEntries(known)
+	for _, entry := range unknownAllow {
+		slog.Warn("Ignoring unknown tool or domain", "entry", entry, "env_var", config.ToolsAllowEnvVar)
+	}
+	for _, entry := range unknownDeny {
+		slog.Warn("Ignoring unknown tool or domain", "entry", entry, "env_var", config.ToolsDenyEnvVar)
+	}
+	for _, names := range excluded {
+		slices.Sort(names)
+	}
+	slog.Info("Tools registered under tool policy",
+		"read_only", policy.readOnly,
+		"allow", policy.allow,
+		"deny", policy.deny,
+		"tools", registered,
+		"excluded_denied", excluded[excludedDenied],
+		"excluded_read_only", excluded[excludedReadOnly],
+		"excluded_not_allowed", excluded[excludedNotAllow],
+	)
+	return known
+}
 
-	log.Println("All tools registered successfully")
+// warnUnknownToolTimeouts logs per-tool timeouts configured for tools that do not
+// exist, which are most likely typos. Timeouts of tools excluded by the tool policy
+// are not reported.
+//
+// Args:
+//   - known: Names of every tool, as returned by registerTools
+func (s *Server) warnUnknownToolTimeouts(known map[string]bool) {
+	for tool := range s.config.ToolTimeouts {
+		if !known[tool] {
+			slog.Warn("Ignoring timeout of unknown tool", "tool", tool, "env_var", config.ToolTimeoutsEnvVar)
+		}
+	}
 }
 
 // Serve starts the MCP server with stdio transport.
 //
-// This method blocks until the server is shut down or an error occurs.
-func (s *Server) Serve() error {
-	log.Println("Starting MCP server on stdio...")
-	return server.ServeStdio(s.mcpServer)
+// This method blocks until stdin is closed, an error occurs, or ctx is cancelled.
+// On cancellation it shuts down gracefully:
+//  1. New tool calls are rejected
+//  2. In-flight tool calls get up to the configured shutdown timeout to finish
+//  3. Reading from stdin stops and the server returns
+//
+// Returns nil on a clean shutdown and ErrShutdownTimeout if tool calls were
+// still running when the timeout expired.
+func (s *Server) Serve(ctx context.Context) error {
+	slog.Info("Starting MCP server on stdio...")
+
+	// The stdio server runs on its own context so that a shutdown signal does not
+	// cancel in-flight tool calls before they had a chance to finish
+	listenCtx, stopListening := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopListening()
+
+	// stdout carries the MCP protocol; the stdio server's own errors go to the
+	// structured logger, which writes to stderr
+	stdioServer := server.NewStdioServer(s.mcpServer)
+	// Tool calls over stdio act as the process that started the server
+	stdioServer.SetContextFunc(func(ctx context.Context) context.Context {
+		return auth.WithCredentialPolicy(ctx, auth.PolicyServerKey)
+	})
+	stdioServer.SetErrorLogger(slog.NewLogLogger(slog.Default().Handler(), slog.LevelError))
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- stdioServer.Listen(listenCtx, os.Stdin, os.Stdout)
+	}()
+
+	select {
+	case err := <-errChan:
+		// stdin closed by the client or a read/write error
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("Shutting down STDIO server...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
+	defer cancel()
+
+	if err := s.toolCalls.drain(shutdownCtx); err != nil {
+		// Tool calls are still running; don't wait for the stdio workers to return
+		stopListening()
+		return err
+	}
+
+	stopListening()
+	if err := <-errChan; err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	slog.Info("STDIO server stopped")
+	return nil
 }
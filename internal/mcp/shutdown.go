@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ErrShutdownTimeout is returned by Serve and ServeHTTP when in-flight tool calls or
+// connections did not finish within the shutdown timeout and had to be abandoned.
+var ErrShutdownTimeout = errors.New("graceful shutdown timed out")
+
+// toolCallTracker counts in-flight tool calls so shutdown can wait for them.
+//
+// Once draining has started, new tool calls are rejected with a tool error so
+// clients retry elsewhere, while calls already running (e.g. get_pipeline_build_logs
+// streams) are allowed to finish.
+type toolCallTracker struct {
+	mu       sync.Mutex
+	inFlight int
+	draining bool
+	idle     chan struct{}
+}
+
+// middleware returns a tool handler middleware that registers every tool call
+// with the tracker.
+func (t *toolCallTracker) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		t.mu.Lock()
+		if t.draining {
+			t.mu.Unlock()
+			slog.WarnContext(ctx, "Rejecting tool call: server is shutting down")
+			return mcp.NewToolResultError("The MCP server is shutting down. Retry the call on a new connection."), nil
+		}
+		t.inFlight++
+		t.mu.Unlock()
+
+		defer t.done()
+		return next(ctx, request)
+	}
+}
+
+// done marks a tool call as finished and wakes up drain when it was the last one.
+func (t *toolCallTracker) done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.inFlight--
+	if t.inFlight == 0 && t.idle != nil {
+		close(t.idle)
+		t.idle = nil
+	}
+}
+
+// drain stops accepting new tool calls and waits until in-flight calls finish or
+// ctx is done. It returns ErrShutdownTimeout if calls were still running at the deadline.
+func (t *toolCallTracker) drain(ctx context.Context) error {
+	t.mu.Lock()
+	t.draining = true
+	if t.inFlight == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	if t.idle == nil {
+		t.idle = make(chan struct{})
+	}
+	idle := t.idle
+	inFlight := t.inFlight
+	t.mu.Unlock()
+
+	slog.Info("Waiting for in-flight tool calls to finish...", "in_flight", inFlight)
+	select {
+	case <-idle:
+		slog.Info("All in-flight tool calls finished")
+		return nil
+	case <-ctx.Done():
+		t.mu.Lock()
+		inFlight = t.inFlight
+		t.mu.Unlock()
+		slog.Warn("Shutdown timeout reached with tool calls still running", "in_flight", inFlight)
+		return ErrShutdownTimeout
+	}
+}
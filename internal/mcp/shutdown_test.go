@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// startCall runs a tool call through the tracker that blocks until release is
+// closed, and waits until it is in flight.
+func startCall(t *testing.T, tracker *toolCallTracker, release <-chan struct{}) {
+	t.Helper()
+	started := make(chan struct{})
+	handler := tracker.middleware(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		close(started)
+		<-release
+		return mcp.NewToolResultText("done"), nil
+	})
+	go handler(context.Background(), mcp.CallToolRequest{})
+	<-started
+}
+
+func TestDrainWaitsForInFlightCalls(t *testing.T) {
+	tracker := &toolCallTracker{}
+	release := make(chan struct{})
+	startCall(t, tracker, release)
+
+	drained := make(chan error, 1)
+	go func() {
+		drained <- tracker.drain(context.Background())
+	}()
+
+	select {
+	case err := <-drained:
+		t.Fatalf("drain returned %v while a call was in flight", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	tracker.mu.Lock()
+	draining := tracker.draining
+	tracker.mu.Unlock()
+	if !draining {
+		t.Error("tracker is not draining")
+	}
+
+	close(release)
+	select {
+	case err := <-drained:
+		if err != nil {
+			t.Errorf("drain() = %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("drain did not return after the call finished")
+	}
+}
+
+func TestDrainTimeout(t *testing.T) {
+	tracker := &toolCallTracker{}
+	release := make(chan struct{})
+	defer close(release)
+	startCall(t, tracker, release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := tracker.drain(ctx); !errors.Is(err, ErrShutdownTimeout) {
+		t.Errorf("drain() = %v, want ErrShutdownTimeout", err)
+	}
+}
+
+func TestDrainRejectsNewCalls(t *testing.T) {
+	tracker := &toolCallTracker{}
+	if err := tracker.drain(context.Background()); err != nil {
+		t.Fatalf("drain() without calls = %v", err)
+	}
+
+	called := false
+	handler := tracker.middleware(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("done"), nil
+	})
+	result, err := handler(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if called || !result.IsError {
+		t.Errorf("call during drain: handler called = %t, IsError = %t", called, result.IsError)
+	}
+}
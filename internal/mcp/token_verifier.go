@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/plantoncloud/mcp-server-planton/internal/common/auth"
+	"github.com/plantoncloud/mcp-server-planton/internal/config"
+	"github.com/plantoncloud/mcp-server-planton/internal/domains/resourcemanager/clients"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newTokenVerifier creates the verifier that checks bearer tokens with Planton APIs
+// before the HTTP server serves them.
+//
+// Returns nil when token pre-validation is disabled, in which case tokens are passed
+// through unchecked.
+func newTokenVerifier(cfg *config.Config) *auth.TokenVerifier {
+	if !cfg.TokenValidationEnabled {
+		return nil
+	}
+	return auth.NewTokenVerifier(verifyWithOrganizationList(cfg.PlantonAPIsGRPCEndpoint), auth.TokenCacheOptions{
+		TTL:         cfg.TokenCacheTTL,
+		RejectedTTL: cfg.TokenRejectedCacheTTL,
+		Size:        cfg.TokenCacheSize,
+	})
+}
+
+// verifyWithOrganizationList returns a VerifyFunc that checks a token by listing the
+// caller's organizations, the cheapest authenticated query every user may run.
+//
+// Only codes.Unauthenticated rejects the token. Any other failure, such as Planton
+// APIs being unreachable, leaves the token unverified rather than rejected.
+func verifyWithOrganizationList(grpcEndpoint string) auth.VerifyFunc {
+	return func(ctx context.Context, token string) error {
+		client, err := clients.NewOrganizationClient(grpcEndpoint, token)
+		if err != nil {
+			return fmt.Errorf("failed to create organization client: %w", err)
+		}
+		defer client.Close()
+
+		if _, err := client.List(ctx); err != nil {
+			if status.Code(err) == codes.Unauthenticated {
+				return fmt.Errorf("%w: %s", auth.ErrTokenRejected, status.Convert(err).Message())
+			}
+			return fmt.Errorf("failed to list organizations: %w", err)
+		}
+		return nil
+	}
+}
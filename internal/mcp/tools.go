@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/auth"
+)
+
+// Tools returns the registered tools sorted by name.
+func (s *Server) Tools() []mcp.Tool {
+	var tools []mcp.Tool
+	for _, tool := range s.mcpServer.ListTools() {
+		tools = append(tools, tool.Tool)
+	}
+	slices.SortFunc(tools, func(a, b mcp.Tool) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return tools
+}
+
+// CallTool runs a tool on behalf of the process itself, e.g. for the
+// "tools call" command. The call goes through the same tools/call handling and
+// middleware as calls from MCP clients (deadlines, logging, metrics, tracing), and
+// authenticates with the configured API key (auth.PolicyServerKey).
+//
+// Args:
+//   - ctx: Context of the call; cancelling it stops the tool
+//   - name: Tool name
+//   - arguments: Tool arguments, as they would appear in the tools/call request
+//
+// Returns the tool result, which is flagged with IsError if the tool failed, or an
+// error if the request was rejected (e.g. an unknown tool).
+func (s *Server) CallTool(ctx context.Context, name string, arguments map[string]any) (*mcp.CallToolResult, error) {
+	message, err := json.Marshal(map[string]any{
+		"jsonrpc": mcp.JSONRPC_VERSION,
+		"id":      1,
+		"method":  string(mcp.MethodToolsCall),
+		"params": map[string]any{
+			"name":      name,
+			"arguments": arguments,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encode arguments of %s: %w", name, err)
+	}
+
+	ctx = auth.WithCredentialPolicy(ctx, auth.PolicyServerKey)
+	switch response := s.mcpServer.HandleMessage(ctx, message).(type) {
+	case mcp.JSONRPCResponse:
+		result, ok := response.Result.(mcp.CallToolResult)
+		if !ok {
+			return nil, fmt.Errorf("call %s: unexpected result %T", name, response.Result)
+		}
+		return &result, nil
+	case mcp.JSONRPCError:
+		return nil, fmt.Errorf("call %s: %s", name, response.Error.Message)
+	default:
+		return nil, fmt.Errorf("call %s: unexpected response %T", name, response)
+	}
+}
@@ -0,0 +1,41 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/plantoncloud/mcp-server-planton/internal/common/auth"
+)
+
+func TestServerCallTool(t *testing.T) {
+	mcpServer := server.NewMCPServer("planton-cloud-test", "0.0.0")
+	mcpServer.AddTool(
+		mcp.NewTool("policy"),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(auth.CredentialPolicyFromContext(ctx).String() + " " + request.GetString("name", "")), nil
+		},
+	)
+	mcpServer.AddTool(mcp.NewTool("echo"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("echo"), nil
+	})
+	s := &Server{mcpServer: mcpServer}
+
+	tools := s.Tools()
+	if len(tools) != 2 || tools[0].Name != "echo" || tools[1].Name != "policy" {
+		t.Errorf("Tools() = %v, want echo and policy sorted by name", tools)
+	}
+
+	result, err := s.CallTool(context.Background(), "policy", map[string]any{"name": "acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != "server-key acme" {
+		t.Errorf("result = %q, want the server-key policy and the argument", text)
+	}
+
+	if _, err := s.CallTool(context.Background(), "missing", nil); err == nil {
+		t.Error("CallTool() of an unknown tool succeeded, want an error")
+	}
+}